@@ -0,0 +1,103 @@
+package assets
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+)
+
+// SpriteSheetFile is the sprite sheet's file name under the web root
+const SpriteSheetFile = "sprites.png"
+
+// Frame is where a sprite sits on the sprite sheet, in sheet pixels
+type Frame struct {
+	X, Y          int
+	Width, Height int
+}
+
+// sheetPadding keeps neighboring sprites from bleeding into each other when
+// the sheet is scaled
+const sheetPadding = 1
+
+// sheetSprite is a sprite on the sheet and the color it is painted in
+type sheetSprite struct {
+	name   string
+	sprite *Sprite
+	color  color.NRGBA
+}
+
+// Sprite colors, matching the shapes the renderer draws without a sheet
+var (
+	playerColor    = color.NRGBA{0x00, 0xff, 0x00, 0xff}
+	invaderColors  = []color.NRGBA{{0xff, 0x00, 0xff, 0xff}, {0xff, 0xff, 0x00, 0xff}, {0x00, 0xff, 0xff, 0xff}}
+	ufoColor       = color.NRGBA{0xff, 0x00, 0xff, 0xff}
+	explosionColor = color.NRGBA{0xff, 0xff, 0xff, 0xff}
+	barrierColor   = color.NRGBA{0x00, 0xff, 0x00, 0xff}
+)
+
+// ExplosionFrames is how many frames the explosion sprite has
+const ExplosionFrames = 3
+
+// InvaderFrame returns the sheet name of an invader sprite
+func InvaderFrame(invaderType, frame int) string {
+	return fmt.Sprintf("invader-%d-%d", invaderType, frame)
+}
+
+// ExplosionFrame returns the sheet name of an explosion frame
+func ExplosionFrame(frame int) string {
+	return fmt.Sprintf("explosion-%d", frame)
+}
+
+// sheetSprites lists every sprite on the sheet in layout order
+func sheetSprites() []sheetSprite {
+	sprites := []sheetSprite{
+		{"player", GetPlayerSprite(), playerColor},
+		{"ufo", GetUFOSprite(), ufoColor},
+		{"barrier", GetBarrierSprite(), barrierColor},
+	}
+	for invaderType, invaderColor := range invaderColors {
+		for frame := range 2 {
+			sprites = append(sprites, sheetSprite{InvaderFrame(invaderType, frame), GetInvaderSprite(invaderType, frame), invaderColor})
+		}
+	}
+	for frame := range ExplosionFrames {
+		sprites = append(sprites, sheetSprite{ExplosionFrame(frame), GetExplosionSprite(frame), explosionColor})
+	}
+	return sprites
+}
+
+// SheetFrames returns where each named sprite sits on the sheet. The layout
+// is a single padded row, worked out the same way by the sheet generator and
+// the renderer so no separate atlas file is needed.
+func SheetFrames() map[string]Frame {
+	frames := make(map[string]Frame)
+	x := sheetPadding
+	for _, s := range sheetSprites() {
+		frames[s.name] = Frame{X: x, Y: sheetPadding, Width: s.sprite.Width, Height: s.sprite.Height}
+		x += s.sprite.Width + sheetPadding
+	}
+	return frames
+}
+
+// BuildSheet paints every sprite onto a transparent sprite sheet
+func BuildSheet() *image.NRGBA {
+	frames := SheetFrames()
+	width, height := sheetPadding, 0
+	for _, frame := range frames {
+		width = max(width, frame.X+frame.Width+sheetPadding)
+		height = max(height, frame.Y+frame.Height+sheetPadding)
+	}
+
+	sheet := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for _, s := range sheetSprites() {
+		frame := frames[s.name]
+		for y, row := range s.sprite.Data {
+			for x, filled := range row {
+				if filled != 0 {
+					sheet.SetNRGBA(frame.X+x, frame.Y+y, s.color)
+				}
+			}
+		}
+	}
+	return sheet
+}
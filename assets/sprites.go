@@ -198,4 +198,17 @@ func GetExplosionSprite(frame int) *Sprite {
 			},
 		}
 	}
-}
\ No newline at end of file
+}
+// GetBarrierSprite returns the tile each barrier block is drawn with
+func GetBarrierSprite() *Sprite {
+	return &Sprite{
+		Width:  4,
+		Height: 4,
+		Data: [][]int{
+			{1, 1, 1, 1},
+			{1, 1, 1, 1},
+			{1, 1, 1, 1},
+			{1, 1, 1, 1},
+		},
+	}
+}
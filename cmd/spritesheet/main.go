@@ -0,0 +1,28 @@
+// Command spritesheet paints the game's pixel-art sprites onto the PNG
+// sprite sheet the browser front-end draws from
+package main
+
+import (
+	"flag"
+	"image/png"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/jonasrmichel/bobn/assets"
+)
+
+func main() {
+	out := flag.String("o", filepath.Join("web", assets.SpriteSheetFile), "where to write the sprite sheet")
+	flag.Parse()
+
+	file, err := os.Create(*out)
+	if err != nil {
+		log.Fatalf("Failed to create sprite sheet: %v", err)
+	}
+	defer file.Close()
+
+	if err := png.Encode(file, assets.BuildSheet()); err != nil {
+		log.Fatalf("Failed to write sprite sheet: %v", err)
+	}
+}
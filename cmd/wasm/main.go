@@ -4,13 +4,25 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"math"
 	"syscall/js"
 	"time"
 
-	"github.com/jonasrmichel/bobn/internal/game"
+	"github.com/jonasrmichel/bobn/assets"
+	"github.com/jonasrmichel/bobn/pkg/game"
 	"github.com/jonasrmichel/bobn/internal/wasm"
 )
 
+// maxFrameCatchUp is the most simulation time, in milliseconds, one frame
+// will run to catch up
+const maxFrameCatchUp = 250.0
+
+// settingsStorageKey is the localStorage key holding the player's settings
+const settingsStorageKey = "bobnSettings"
+
+// highScoresStorageKey is the localStorage key holding the high score table
+const highScoresStorageKey = "bobnHighScores"
+
 // Game represents the main game state
 type Game struct {
 	canvas    js.Value
@@ -54,11 +66,12 @@ func NewGame(canvas js.Value) *Game {
 		return nil
 	}
 
-	engine := game.NewEngine(width, height)
-	renderer := wasm.NewRenderer(bridge, width, height)
+	engine := game.NewEngine()
+	renderer := wasm.NewRenderer(bridge)
 
 	// Set the renderer to use the same context
 	renderer.SetContext(ctx)
+	renderer.SetSpriteSheet(bridge.LoadImage(assets.SpriteSheetFile))
 
 	// Initialize camera controller
 	camera := wasm.NewCameraController()
@@ -82,9 +95,137 @@ func NewGame(canvas js.Value) *Game {
 		g.cameraY = y
 	})
 
+	// Sound effects follow what happens in the game
+	engine.Subscribe(g.playEventSound)
+
+	// Big hits shake the screen
+	engine.Subscribe(func(event game.Event) {
+		g.renderer.Shake(event.Shake)
+	}, game.EventShake)
+
+	// The HTML scoreboard only changes when the values it shows do
+	engine.Subscribe(g.updateUI,
+		game.EventScoreChanged,
+		game.EventHighScoreChanged,
+		game.EventLivesChanged,
+		game.EventWaveChanged,
+		game.EventModeChanged,
+	)
+
+	// Wave and boss behavior can be scripted from the page
+	if script := wasm.LoadScript("bobnScript"); script != nil {
+		log.Println("Loaded wave script")
+		engine.SetScript(script)
+	}
+
+	g.loadSettings()
+	g.loadHighScores()
 	return g
 }
 
+// eventSounds maps engine events to the sound effects they trigger
+var eventSounds = map[game.EventType]string{
+	game.EventInvaderKilled:    "invaderKilled",
+	game.EventPlayerHit:        "playerHit",
+	game.EventWaveCleared:      "waveCleared",
+	game.EventUFOSpawned:       "ufo",
+	game.EventPowerUpCollected: "powerUp",
+}
+
+// playEventSound plays the sound effect for an engine event
+func (g *Game) playEventSound(event game.Event) {
+	if sound, ok := eventSounds[event.Type]; ok && !g.engine.GetState().Demo {
+		g.bridge.PlaySound(sound)
+	}
+}
+
+// loadSettings restores the player's saved settings and applies them
+func (g *Game) loadSettings() {
+	data := g.bridge.GetLocalStorage(settingsStorageKey)
+	if data == "" {
+		return
+	}
+	settings, err := game.ParseSettings([]byte(data))
+	if err != nil {
+		log.Printf("Ignoring saved settings: %v", err)
+		return
+	}
+	g.engine.ApplySettings(settings)
+	g.applySettings(settings)
+}
+
+// saveSettings stores the player's settings for the next session
+func (g *Game) saveSettings(settings game.Settings) {
+	data, err := game.MarshalSettings(settings)
+	if err != nil {
+		log.Printf("Failed to save settings: %v", err)
+		return
+	}
+	g.bridge.SetLocalStorage(settingsStorageKey, string(data))
+}
+
+// loadHighScores restores the high score table from earlier sessions
+func (g *Game) loadHighScores() {
+	data := g.bridge.GetLocalStorage(highScoresStorageKey)
+	if data == "" {
+		return
+	}
+	table, err := game.ParseHighScores([]byte(data))
+	if err != nil {
+		log.Printf("Ignoring saved high scores: %v", err)
+		return
+	}
+	g.engine.ApplyHighScores(table)
+}
+
+// saveHighScores stores the high score table for the next session
+func (g *Game) saveHighScores(table game.HighScoreTable) {
+	data, err := game.MarshalHighScores(table)
+	if err != nil {
+		log.Printf("Failed to save high scores: %v", err)
+		return
+	}
+	g.bridge.SetLocalStorage(highScoresStorageKey, string(data))
+}
+
+// applySettings pushes settings out to the front-end components
+func (g *Game) applySettings(settings game.Settings) {
+	g.bridge.SetVolume(settings.Volume)
+	g.renderer.SetTheme(settings.Theme)
+	g.renderer.SetShakeEnabled(settings.ScreenShake)
+	g.renderer.SetCRTEnabled(settings.CRT)
+	g.renderer.SetGlow(settings.Glow)
+	g.renderer.SetShotShapes(settings.ShotShapes)
+	g.renderer.SetWebGL(settings.WebGL)
+	g.camera.SetSensitivity(settings.Sensitivity)
+}
+
+// usingCamera reports whether head tracking steers the ship
+func (g *Game) usingCamera() bool {
+	return g.camera.IsEnabled() && g.engine.GetState().Settings.Controls == game.ControlCamera
+}
+
+// commands turns player one's keys, or head position while playing with the
+// camera, into game commands
+func (g *Game) commands(input wasm.InputState, state *game.GameState) game.Commands {
+	commands := game.Commands{
+		MoveAxis:    game.Axis(input.LeftPressed, input.RightPressed),
+		Fire:        input.FirePressed,
+		FirePressed: input.FireJustPressed,
+		Pause:       input.PauseJustPressed,
+		Confirm:     input.EnterJustPressed,
+		Up:          input.UpJustPressed,
+		Down:        input.DownJustPressed,
+		CycleWeapon: input.WeaponJustPressed,
+		SmartBomb:   input.BombJustPressed,
+	}
+	if g.usingCamera() && state.Mode == game.Playing {
+		commands.MoveAxis = g.cameraX // -1 to 1
+		commands.Aim = true
+	}
+	return commands
+}
+
 // Start begins the game loop
 func (g *Game) Start() {
 	log.Println("Start() called - starting game loop")
@@ -135,45 +276,94 @@ func (g *Game) gameLoop() {
 
 // update handles game logic updates with fixed timestep
 func (g *Game) update(deltaTime float64) {
-	// Fixed timestep accumulator pattern for consistent physics
-	g.accumulator += deltaTime
+	// Fixed timestep accumulator pattern for consistent physics. A long gap
+	// between frames, from a throttled or hidden tab, only catches up a few
+	// ticks, so UFO countdowns and cooldowns don't run out while nobody is
+	// watching.
+	g.accumulator = math.Min(g.accumulator+deltaTime, maxFrameCatchUp)
 
 	// Fixed update step (50ms = 20Hz)
 	fixedTimeStep := 50.0
 	for g.accumulator >= fixedTimeStep {
-		// Get input state from bridge
-		input := g.bridge.GetInputState()
-
-		// If camera is enabled, use analog control
-		if g.camera.IsEnabled() && g.engine.GetState().Mode == game.Playing {
-			// Use camera position for analog control
-			g.engine.ProcessAnalogInput(
-				g.cameraX,  // Analog X position (-1 to 1)
-				input.FirePressed,  // Only fire when Space is pressed
-				input.FireJustPressed,
-				input.PauseJustPressed || input.EnterJustPressed,
-			)
-		} else {
-			// Use digital keyboard input
-			leftPressed := input.LeftPressed
-			rightPressed := input.RightPressed
-
-			// Process input and update game state
-			g.engine.ProcessInput(
-				leftPressed,
-				rightPressed,
-				input.FirePressed,
-				input.FireJustPressed,
-				input.PauseJustPressed || input.EnterJustPressed,
-			)
+		// Take the input that arrived before this tick's end; input from
+		// later in the frame waits for the ticks it belongs to
+		tickEnd := g.lastTime - (g.accumulator - fixedTimeStep)
+		input := g.bridge.GetInputState(tickEnd)
+
+		// Skill varies widely with head tracking, so let difficulty adapt
+		if g.engine.GetState().Mode == game.AttractMode {
+			g.engine.SetAdaptiveDifficulty(g.usingCamera())
+		}
+
+		// Head tracking points at menu entries while a menu is open
+		state := g.engine.GetState()
+		if g.usingCamera() {
+			switch {
+			case state.Mode == game.SettingsMenu:
+				g.engine.PointSettingsMenu(g.cameraY)
+			case state.Mode == game.HighScore:
+				g.engine.PointInitials(g.cameraY)
+			case state.Mode == game.Playing && state.Paused:
+				g.engine.PointPauseMenu(g.cameraY)
+			}
+		}
+		g.engine.ProcessCommands(0, g.commands(input, state))
+		if g.engine.TakeCalibrationRequest() {
+			g.camera.StartCalibration()
+		}
+		if input.DailyJustPressed {
+			g.engine.ToggleDailyMode()
 		}
+		if input.PracticeJustPressed {
+			g.engine.TogglePracticeMode()
+		}
+		if input.MirrorJustPressed {
+			g.engine.ToggleMirrorMode()
+		}
+		if input.TwoPlayerJustPressed {
+			g.engine.ToggleTwoPlayer()
+		}
+		if input.CoopJustPressed {
+			g.engine.ToggleCoop()
+		}
+		if input.GhostJustPressed {
+			g.engine.ToggleGhost()
+		}
+		if input.DebrisJustPressed {
+			g.engine.ToggleDebris()
+		}
+		if input.WrapJustPressed {
+			g.engine.ToggleScreenWrap()
+		}
+		if input.SettingsJustPressed {
+			g.engine.OpenSettings()
+		}
+		if input.DebugJustPressed {
+			g.renderer.ToggleDebugOverlay()
+		}
+		if input.CollisionJustPressed {
+			g.renderer.ToggleCollisionView()
+		}
+		for _, key := range input.TypedKeys {
+			g.engine.ProcessCheatKey(key)
+		}
+		if settings, changed := g.engine.TakeSettingsChange(); changed {
+			g.saveSettings(settings)
+			g.applySettings(settings)
+		}
+		if table, changed := g.engine.TakeHighScoresChange(); changed {
+			g.saveHighScores(table)
+		}
+
+		// The second co-op ship is driven from the WASD keys
+		g.engine.ProcessCommands(1, game.Commands{
+			MoveAxis:    game.Axis(input.P2LeftPressed, input.P2RightPressed),
+			FirePressed: input.P2FireJustPressed,
+		})
 		g.engine.Update(fixedTimeStep / 1000.0) // Convert to seconds
 
 		g.accumulator -= fixedTimeStep
 	}
-
-	// Update UI elements in HTML
-	g.updateUI()
 }
 
 // render handles drawing the game
@@ -188,157 +378,59 @@ func (g *Game) render() {
 	}
 
 	// Clear canvas
-	ctx.Set("fillStyle", "#000000")
+	theme := g.renderer.Theme()
+	ctx.Set("fillStyle", theme.Background)
 	ctx.Call("fillRect", 0, 0, g.width, g.height)
 
 	if g.engine == nil {
 		// Show loading message if not ready
-		ctx.Set("fillStyle", "#00ff00")
+		ctx.Set("fillStyle", theme.Title)
 		ctx.Set("font", "20px monospace")
 		ctx.Set("textAlign", "center")
 		ctx.Call("fillText", "ENGINE NOT INITIALIZED", g.width/2, g.height/2)
 		return
 	}
 
-	// Draw the game directly
-	state := g.engine.GetState()
-
-	// Draw stars background
-	ctx.Set("fillStyle", "#ffffff")
-	for i := 0; i < 50; i++ {
-		x := (i * 73) % g.width
-		y := (i * 37) % g.height
-		ctx.Call("fillRect", x, y, 2, 2)
-	}
-
-	// Draw game based on mode
-	switch state.Mode {
-	case game.AttractMode:
-		// Draw title
-		ctx.Set("fillStyle", "#00ff00")
-		ctx.Set("font", "48px monospace")
-		ctx.Set("textAlign", "center")
-		ctx.Set("textBaseline", "middle")
-		ctx.Call("fillText", "BOBN", g.width/2, 150)
+	// Draw the game through the shared renderer
+	g.renderer.SetMetrics(g.engine.Metrics())
+	g.renderer.RenderGame(g.engine.GetState())
+}
 
-		ctx.Set("font", "20px monospace")
-		ctx.Set("fillStyle", "#00ffff")
-		ctx.Call("fillText", "SPACE INVADERS", g.width/2, 200)
-
-		// Instructions
-		ctx.Set("font", "16px monospace")
-		ctx.Set("fillStyle", "#ffff00")
-		ctx.Call("fillText", "PRESS ENTER TO START", g.width/2, 300)
-		ctx.Call("fillText", "USE ARROWS TO MOVE", g.width/2, 330)
-		ctx.Call("fillText", "SPACE TO FIRE", g.width/2, 360)
-
-	case game.Playing:
-		// Draw player ship
-		if state.Player != nil && state.Player.Alive {
-			ctx.Set("fillStyle", "#00ff00")
-			// Simple triangle ship
-			ctx.Call("beginPath")
-			ctx.Call("moveTo", state.Player.Position.X, state.Player.Position.Y)
-			ctx.Call("lineTo", state.Player.Position.X-15, state.Player.Position.Y+20)
-			ctx.Call("lineTo", state.Player.Position.X+15, state.Player.Position.Y+20)
-			ctx.Call("closePath")
-			ctx.Call("fill")
-		}
+// modeStatus is the status line shown for each game mode
+var modeStatus = map[game.GameMode]string{
+	game.AttractMode:  "PRESS START TO PLAY",
+	game.Playing:      "PLAYING",
+	game.GameOver:     "GAME OVER",
+	game.HighScore:    "NEW HIGH SCORE!",
+	game.Summary:      "RESULTS",
+	game.SettingsMenu: "SETTINGS",
+	game.Continue:     "CONTINUE?",
+}
 
-		// Draw invaders
-		for _, invader := range state.Invaders {
-			if invader.Alive {
-				ctx.Set("fillStyle", "#ff00ff")
-				ctx.Call("fillRect", invader.Position.X-10, invader.Position.Y-5, 20, 10)
-				// Eyes
-				ctx.Set("fillStyle", "#000000")
-				ctx.Call("fillRect", invader.Position.X-6, invader.Position.Y-2, 3, 3)
-				ctx.Call("fillRect", invader.Position.X+3, invader.Position.Y-2, 3, 3)
-			}
+// updateUI updates the HTML UI element showing the value that changed
+func (g *Game) updateUI(event game.Event) {
+	switch event.Type {
+	case game.EventScoreChanged:
+		if event.Player == 0 {
+			g.setText("score", fmt.Sprintf("%06d", event.Value))
 		}
-
-		// Draw bullets
-		for _, bullet := range state.Bullets {
-			if bullet.Alive {
-				if bullet.IsPlayerBullet {
-					ctx.Set("fillStyle", "#00ff00")
-				} else {
-					ctx.Set("fillStyle", "#ff0000")
-				}
-				ctx.Call("fillRect", bullet.Position.X-1, bullet.Position.Y, 2, 8)
-			}
+	case game.EventHighScoreChanged:
+		g.setText("highScore", fmt.Sprintf("%06d", event.Value))
+	case game.EventLivesChanged:
+		if event.Player == 0 {
+			g.setText("lives", fmt.Sprintf("%d", event.Value))
 		}
-
-	case game.GameOver:
-		ctx.Set("fillStyle", "#ff0000")
-		ctx.Set("font", "48px monospace")
-		ctx.Set("textAlign", "center")
-		ctx.Call("fillText", "GAME OVER", g.width/2, g.height/2)
-
-		ctx.Set("font", "20px monospace")
-		ctx.Set("fillStyle", "#ffffff")
-		ctx.Call("fillText", fmt.Sprintf("SCORE: %d", state.Score), g.width/2, g.height/2+50)
-	}
-
-	// Draw UI (score, lives, etc)
-	ctx.Set("fillStyle", "#ffffff")
-	ctx.Set("font", "16px monospace")
-	ctx.Set("textAlign", "left")
-	ctx.Call("fillText", fmt.Sprintf("SCORE: %06d", state.Score), 10, 30)
-
-	ctx.Set("textAlign", "center")
-	ctx.Call("fillText", fmt.Sprintf("HIGH: %06d", state.HighScore), g.width/2, 30)
-
-	ctx.Set("textAlign", "right")
-	ctx.Call("fillText", fmt.Sprintf("LIVES: %d", state.Lives), g.width-10, 30)
-
-	// Draw wave number
-	if state.Mode == game.Playing {
-		ctx.Set("textAlign", "center")
-		ctx.Set("fillStyle", "#00ffff")
-		ctx.Call("fillText", fmt.Sprintf("WAVE %d", state.Wave), g.width/2, g.height-20)
+	case game.EventWaveChanged:
+		g.setText("level", fmt.Sprintf("%d", event.Value))
+	case game.EventModeChanged:
+		g.setText("status", modeStatus[event.Mode])
 	}
 }
 
-// updateUI updates the HTML UI elements
-func (g *Game) updateUI() {
-	state := g.engine.GetState()
-	doc := js.Global().Get("document")
-
-	// Update score
-	if scoreElem := doc.Call("getElementById", "score"); !scoreElem.IsUndefined() && !scoreElem.IsNull() {
-		scoreElem.Set("textContent", fmt.Sprintf("%06d", state.Score))
-	}
-
-	// Update high score
-	if highScoreElem := doc.Call("getElementById", "highScore"); !highScoreElem.IsUndefined() && !highScoreElem.IsNull() {
-		highScoreElem.Set("textContent", fmt.Sprintf("%06d", state.HighScore))
-	}
-
-	// Update lives
-	if livesElem := doc.Call("getElementById", "lives"); !livesElem.IsUndefined() && !livesElem.IsNull() {
-		livesElem.Set("textContent", fmt.Sprintf("%d", state.Lives))
-	}
-
-	// Update level
-	if levelElem := doc.Call("getElementById", "level"); !levelElem.IsUndefined() && !levelElem.IsNull() {
-		levelElem.Set("textContent", fmt.Sprintf("%d", state.Wave))
-	}
-
-	// Update status message
-	if statusElem := doc.Call("getElementById", "status"); !statusElem.IsUndefined() && !statusElem.IsNull() {
-		var status string
-		switch state.Mode {
-		case game.AttractMode:
-			status = "PRESS START TO PLAY"
-		case game.Playing:
-			status = "PLAYING"
-		case game.GameOver:
-			status = "GAME OVER"
-		case game.HighScore:
-			status = "NEW HIGH SCORE!"
-		}
-		statusElem.Set("textContent", status)
+// setText sets the text of the HTML element with the given id, if the page has one
+func (g *Game) setText(id, text string) {
+	if elem := js.Global().Get("document").Call("getElementById", id); !elem.IsUndefined() && !elem.IsNull() {
+		elem.Set("textContent", text)
 	}
 }
 
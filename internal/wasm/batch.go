@@ -0,0 +1,61 @@
+package wasm
+
+import (
+	"github.com/jonasrmichel/bobn/assets"
+	"github.com/jonasrmichel/bobn/pkg/game"
+)
+
+// quadBatch draws the quads most of the playfield is made of: frames of the
+// sprite sheet and solid rectangles. The 2D canvas draws each one straight
+// away; WebGL queues them and draws a whole batch in one call. Anything the
+// renderer draws on the canvas directly must come after a flush, or queued
+// quads would end up on top of it.
+//
+// Only sprites and solid quads are batched. Text, paths, arcs, gradients and
+// glows, which the HUD, bullets, beams and most effects are drawn with, stay
+// on the 2D canvas whichever backend is in use.
+type quadBatch interface {
+	// begin readies the batch for a frame
+	begin()
+	// sprite draws a frame of the sprite sheet stretched over the bounds
+	sprite(frame assets.Frame, bounds game.Bounds)
+	// rect fills a rectangle in a color at an opacity
+	rect(x, y, width, height float64, color string, alpha float64)
+	// flush puts whatever is queued on the canvas
+	flush()
+}
+
+// newQuadBatch picks the fastest backend the browser supports
+func newQuadBatch(r *Renderer) quadBatch {
+	if batch := r.webGL(); batch != nil {
+		return batch
+	}
+	return &canvasBatch{r: r}
+}
+
+// canvasBatch draws quads through the 2D canvas as they come, so they pick
+// up the canvas's transform and opacity like everything else
+type canvasBatch struct {
+	r *Renderer
+}
+
+func (b *canvasBatch) begin() {}
+
+func (b *canvasBatch) sprite(frame assets.Frame, bounds game.Bounds) {
+	ctx := b.r.ctx
+
+	// Keep the pixel art crisp when scaled up
+	ctx.Set("imageSmoothingEnabled", false)
+	ctx.Call("drawImage", b.r.sprites,
+		frame.X, frame.Y, frame.Width, frame.Height,
+		bounds.X, bounds.Y, bounds.Width, bounds.Height)
+}
+
+func (b *canvasBatch) rect(x, y, width, height float64, color string, alpha float64) {
+	ctx := b.r.ctx
+	ctx.Set("globalAlpha", alpha)
+	ctx.Set("fillStyle", color)
+	ctx.Call("fillRect", x, y, width, height)
+}
+
+func (b *canvasBatch) flush() {}
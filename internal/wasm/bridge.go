@@ -2,8 +2,12 @@ package wasm
 
 import (
 	"errors"
+	"fmt"
+	"math"
 	"syscall/js"
 	"time"
+
+	"github.com/jonasrmichel/bobn/pkg/game"
 )
 
 // JSBridge handles JavaScript interop for WASM
@@ -19,10 +23,11 @@ type JSBridge struct {
 	resizeListener   js.Func
 	focusListener    js.Func
 	blurListener     js.Func
+	resizeObserver   js.Value // watches the canvas's own size, where supported
 
 	// Input state tracking
 	keysPressed map[string]bool
-	keysJustPressed map[string]bool
+	keyEvents   *game.InputQueue // key codes going down and up, for the fixed ticks
 
 	// Animation frame callback
 	animationCallback js.Func
@@ -32,6 +37,9 @@ type JSBridge struct {
 	canvasWidth  int
 	canvasHeight int
 	deviceRatio  float64
+
+	// Sound effect volume, 0 (muted) to 1
+	volume float64
 }
 
 // NewJSBridge creates a new JavaScript bridge
@@ -40,8 +48,9 @@ func NewJSBridge() *JSBridge {
 		document:    js.Global().Get("document"),
 		window:      js.Global(),
 		keysPressed: make(map[string]bool),
-		keysJustPressed: make(map[string]bool),
+		keyEvents:   game.NewInputQueue(),
 		deviceRatio: 1.0,
+		volume:      1.0,
 	}
 
 	// Get device pixel ratio for high DPI displays
@@ -67,27 +76,69 @@ type InputState struct {
 	FireJustPressed  bool
 	PauseJustPressed bool
 	EnterJustPressed bool
-}
-
-// GetInputState returns the current input state
-func (b *JSBridge) GetInputState() InputState {
-	state := InputState{
-		LeftPressed:      b.keysPressed["ArrowLeft"],
-		RightPressed:     b.keysPressed["ArrowRight"],
-		UpPressed:        b.keysPressed["ArrowUp"],
-		DownPressed:      b.keysPressed["ArrowDown"],
-		FirePressed:      b.keysPressed[" "] || b.keysPressed["Space"],
-		FireJustPressed:  b.keysJustPressed[" "] || b.keysJustPressed["Space"],
-		PauseJustPressed: b.keysJustPressed["Escape"] || b.keysJustPressed["p"] || b.keysJustPressed["P"],
-		EnterJustPressed: b.keysJustPressed["Enter"],
+	UpJustPressed    bool
+	DownJustPressed  bool
+	LeftJustPressed  bool
+	RightJustPressed bool
+	WeaponJustPressed    bool
+	BombJustPressed      bool
+	DailyJustPressed     bool
+	PracticeJustPressed  bool
+	MirrorJustPressed    bool
+	TwoPlayerJustPressed bool
+	CoopJustPressed      bool
+	GhostJustPressed     bool
+	DebrisJustPressed    bool
+	WrapJustPressed      bool
+	SettingsJustPressed  bool
+	DebugJustPressed     bool
+	CollisionJustPressed bool
+
+	// Key codes pressed since the last poll, in order
+	TypedKeys []string
+
+	// Second co-op player on the WASD keys
+	P2LeftPressed     bool
+	P2RightPressed    bool
+	P2FireJustPressed bool
+}
+
+// GetInputState returns what the keys did over the fixed tick ending at the
+// given time, in milliseconds on the page's clock. Presses and releases are
+// buffered with their timestamps, so a tap between two ticks is never lost.
+func (b *JSBridge) GetInputState(tickEnd float64) InputState {
+	keys := b.keyEvents.Drain(tickEnd / 1000)
+	return InputState{
+		LeftPressed:          keys.Held("ArrowLeft"),
+		RightPressed:         keys.Held("ArrowRight"),
+		UpPressed:            keys.Held("ArrowUp"),
+		DownPressed:          keys.Held("ArrowDown"),
+		FirePressed:          keys.Held("Space"),
+		FireJustPressed:      keys.Pressed("Space"),
+		PauseJustPressed:     keys.Pressed("Escape", "KeyP"),
+		EnterJustPressed:     keys.Pressed("Enter"),
+		UpJustPressed:        keys.Pressed("ArrowUp"),
+		DownJustPressed:      keys.Pressed("ArrowDown"),
+		LeftJustPressed:      keys.Pressed("ArrowLeft"),
+		RightJustPressed:     keys.Pressed("ArrowRight"),
+		WeaponJustPressed:    keys.Pressed("KeyQ"),
+		BombJustPressed:      keys.Pressed("KeyB"),
+		DailyJustPressed:     keys.Pressed("KeyT"),
+		PracticeJustPressed:  keys.Pressed("KeyR"),
+		MirrorJustPressed:    keys.Pressed("KeyM"),
+		TwoPlayerJustPressed: keys.Pressed("Digit2"),
+		CoopJustPressed:      keys.Pressed("KeyC"),
+		GhostJustPressed:     keys.Pressed("KeyG"),
+		DebrisJustPressed:    keys.Pressed("KeyX"),
+		WrapJustPressed:      keys.Pressed("KeyV"),
+		SettingsJustPressed:  keys.Pressed("KeyO"),
+		DebugJustPressed:     keys.Pressed("F3"),
+		CollisionJustPressed: keys.Pressed("F4"),
+		P2LeftPressed:        keys.Held("KeyA"),
+		P2RightPressed:       keys.Held("KeyD"),
+		P2FireJustPressed:    keys.Pressed("KeyW"),
+		TypedKeys:            keys.Presses,
 	}
-
-	// Clear just pressed keys after reading
-	for key := range b.keysJustPressed {
-		b.keysJustPressed[key] = false
-	}
-
-	return state
 }
 
 // Initialize sets up the JavaScript bridge with canvas and event listeners
@@ -113,27 +164,31 @@ func (b *JSBridge) Initialize(canvasID string) error {
 	return nil
 }
 
-// setupCanvas configures the canvas for high DPI displays
+// setupCanvas matches the canvas's pixels to the size the page lays it out
+// at, for sharp drawing on high DPI displays. The renderer letterboxes the
+// playfield into whatever shape that is, so the canvas's CSS size is free to
+// follow the window.
 func (b *JSBridge) setupCanvas() {
+	// The ratio changes with browser zoom and when moving between screens
+	if ratio := b.window.Get("devicePixelRatio"); !ratio.IsUndefined() {
+		b.deviceRatio = ratio.Float()
+	}
+
 	// Get actual canvas size from CSS
 	rect := b.canvas.Call("getBoundingClientRect")
-	cssWidth := rect.Get("width").Float()
-	cssHeight := rect.Get("height").Float()
-
-	// Set actual canvas size accounting for device pixel ratio
-	b.canvasWidth = int(cssWidth * b.deviceRatio)
-	b.canvasHeight = int(cssHeight * b.deviceRatio)
-
-	// Set canvas buffer size
-	b.canvas.Set("width", b.canvasWidth)
-	b.canvas.Set("height", b.canvasHeight)
-
-	// Scale the canvas back down using CSS
-	b.canvas.Get("style").Set("width", cssWidth)
-	b.canvas.Get("style").Set("height", cssHeight)
+	width := int(math.Round(rect.Get("width").Float() * b.deviceRatio))
+	height := int(math.Round(rect.Get("height").Float() * b.deviceRatio))
+	if width <= 0 || height <= 0 {
+		return // Not laid out yet
+	}
 
-	// Scale the context to match device pixel ratio
-	b.context.Call("scale", b.deviceRatio, b.deviceRatio)
+	// Resizing the buffer clears it, so only do so when the size changes
+	if width != b.canvasWidth || height != b.canvasHeight {
+		b.canvasWidth = width
+		b.canvasHeight = height
+		b.canvas.Set("width", width)
+		b.canvas.Set("height", height)
+	}
 
 	// Set default context properties
 	b.context.Set("imageSmoothingEnabled", false)
@@ -149,13 +204,8 @@ func (b *JSBridge) setupEventListeners() {
 		key := event.Get("key").String()
 		code := event.Get("code").String()
 
-		// Track just pressed only if key wasn't already pressed
-		if !b.keysPressed[key] {
-			b.keysJustPressed[key] = true
-		}
-		if !b.keysPressed[code] {
-			b.keysJustPressed[code] = true
-		}
+		// Queue the press with its time for the next fixed tick
+		b.keyEvents.Push(game.InputEvent{Key: code, Down: true, Time: eventTime(event)})
 
 		b.keysPressed[key] = true
 		b.keysPressed[code] = true
@@ -173,17 +223,23 @@ func (b *JSBridge) setupEventListeners() {
 		key := event.Get("key").String()
 		code := event.Get("code").String()
 
+		b.keyEvents.Push(game.InputEvent{Key: code, Time: eventTime(event)})
 		b.keysPressed[key] = false
 		b.keysPressed[code] = false
 
 		return nil
 	})
 
-	// Window resize listener
+	// Window resize listener, also run when the device rotates and, where
+	// the browser can watch it, whenever the canvas itself changes size
 	b.resizeListener = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
 		b.setupCanvas()
 		return nil
 	})
+	if observer := b.window.Get("ResizeObserver"); observer.Truthy() {
+		b.resizeObserver = observer.New(b.resizeListener)
+		b.resizeObserver.Call("observe", b.canvas)
+	}
 
 	// Focus/blur listeners for pausing
 	b.focusListener = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
@@ -191,6 +247,7 @@ func (b *JSBridge) setupEventListeners() {
 		for key := range b.keysPressed {
 			b.keysPressed[key] = false
 		}
+		b.keyEvents.ReleaseAll(eventTime(args[0]))
 		return nil
 	})
 
@@ -199,6 +256,7 @@ func (b *JSBridge) setupEventListeners() {
 		for key := range b.keysPressed {
 			b.keysPressed[key] = false
 		}
+		b.keyEvents.ReleaseAll(eventTime(args[0]))
 		return nil
 	})
 
@@ -206,6 +264,7 @@ func (b *JSBridge) setupEventListeners() {
 	b.document.Call("addEventListener", "keydown", b.keydownListener)
 	b.document.Call("addEventListener", "keyup", b.keyupListener)
 	b.window.Call("addEventListener", "resize", b.resizeListener)
+	b.window.Call("addEventListener", "orientationchange", b.resizeListener)
 	b.window.Call("addEventListener", "focus", b.focusListener)
 	b.window.Call("addEventListener", "blur", b.blurListener)
 
@@ -225,6 +284,20 @@ func (b *JSBridge) isGameKey(key string) bool {
 		"KeyA":       true,
 		"KeyD":       true,
 		"KeyP":       true,
+		"KeyQ":       true,
+		"KeyB":       true,
+		"KeyT":       true,
+		"KeyR":       true,
+		"KeyM":       true,
+		"Digit2":     true,
+		"KeyC":       true,
+		"KeyW":       true,
+		"KeyG":       true,
+		"KeyX":       true,
+		"KeyV":       true,
+		"KeyO":       true,
+		"F3":         true,
+		"F4":         true,
 		"Enter":      true,
 	}
 	return gameKeys[key]
@@ -271,6 +344,11 @@ func (b *JSBridge) GetInput() (left, right, fire, fireJustPressed, pauseJustPres
 	return
 }
 
+// eventTime returns when a DOM event happened, in seconds on the page's clock
+func eventTime(event js.Value) float64 {
+	return event.Get("timeStamp").Float() / 1000
+}
+
 // IsKeyPressed checks if a specific key is currently pressed
 func (b *JSBridge) IsKeyPressed(key string) bool {
 	return b.keysPressed[key]
@@ -333,9 +411,9 @@ func (b *JSBridge) DrawLine(x1, y1, x2, y2, lineWidth float64, color string) {
 
 // Utility functions
 
-// GetCanvasSize returns the canvas dimensions
+// GetCanvasSize returns the canvas dimensions in CSS pixels
 func (b *JSBridge) GetCanvasSize() (int, int) {
-	return b.canvasWidth / int(b.deviceRatio), b.canvasHeight / int(b.deviceRatio)
+	return int(float64(b.canvasWidth) / b.deviceRatio), int(float64(b.canvasHeight) / b.deviceRatio)
 }
 
 // GetDevicePixelRatio returns the device pixel ratio
@@ -351,10 +429,8 @@ func (b *JSBridge) SetCanvasSize(width, height int) {
 	b.canvas.Set("width", b.canvasWidth)
 	b.canvas.Set("height", b.canvasHeight)
 
-	b.canvas.Get("style").Set("width", width)
-	b.canvas.Get("style").Set("height", height)
-
-	b.context.Call("scale", b.deviceRatio, b.deviceRatio)
+	b.canvas.Get("style").Set("width", fmt.Sprintf("%dpx", width))
+	b.canvas.Get("style").Set("height", fmt.Sprintf("%dpx", height))
 }
 
 // Log logs a message to the browser console
@@ -409,10 +485,42 @@ func (b *JSBridge) SetElementHTML(elementID, html string) {
 
 // PlaySound plays a sound effect (to be implemented with Web Audio API)
 func (b *JSBridge) PlaySound(soundID string) {
+	if b.volume <= 0 {
+		return // Muted
+	}
 	// Placeholder - would implement Web Audio API calls here
 	b.Log("Playing sound: " + soundID)
 }
 
+// SetVolume sets the sound effect volume, from 0 (muted) to 1
+func (b *JSBridge) SetVolume(volume float64) {
+	b.volume = volume
+}
+
+// Image support
+
+// LoadImage starts loading an image from the given URL. The image can be
+// drawn once ImageReady reports it has loaded.
+func (b *JSBridge) LoadImage(url string) js.Value {
+	image := b.window.Get("Image").New()
+	image.Set("src", url)
+	return image
+}
+
+// NewCanvas creates an offscreen canvas for drawing layers that are cached
+// between frames
+func (b *JSBridge) NewCanvas(width, height int) js.Value {
+	canvas := b.document.Call("createElement", "canvas")
+	canvas.Set("width", width)
+	canvas.Set("height", height)
+	return canvas
+}
+
+// ImageReady reports whether an image has loaded and can be drawn
+func ImageReady(image js.Value) bool {
+	return image.Truthy() && image.Get("complete").Bool() && image.Get("naturalWidth").Int() > 0
+}
+
 // Storage support
 
 // SetLocalStorage sets a value in localStorage
@@ -446,8 +554,12 @@ func (b *JSBridge) Cleanup() {
 		b.document.Call("removeEventListener", "keyup", b.keyupListener)
 		b.keyupListener.Release()
 	}
+	if b.resizeObserver.Truthy() {
+		b.resizeObserver.Call("disconnect")
+	}
 	if !b.resizeListener.IsUndefined() {
 		b.window.Call("removeEventListener", "resize", b.resizeListener)
+		b.window.Call("removeEventListener", "orientationchange", b.resizeListener)
 		b.resizeListener.Release()
 	}
 	if !b.focusListener.IsUndefined() {
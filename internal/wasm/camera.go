@@ -1,6 +1,7 @@
 package wasm
 
 import (
+	"fmt"
 	"log"
 	"math"
 	"syscall/js"
@@ -379,9 +380,23 @@ func (c *CameraController) generateASCIIArt() []string {
 	return lines
 }
 
-// SetSensitivity sets the camera sensitivity
+// SetSensitivity sets the camera sensitivity, keeping the page's slider in step
 func (c *CameraController) SetSensitivity(sensitivity float64) {
 	c.sensitivity = sensitivity
+
+	// processFrame prefers the page's value, so update it too
+	window := js.Global().Get("window")
+	if window.IsUndefined() {
+		return
+	}
+	window.Set("cameraSensitivity", sensitivity)
+	doc := js.Global().Get("document")
+	if slider := doc.Call("getElementById", "sensitivitySlider"); slider.Truthy() {
+		slider.Set("value", sensitivity)
+	}
+	if label := doc.Call("getElementById", "sensitivityValue"); label.Truthy() {
+		label.Set("textContent", fmt.Sprintf("%.1fx", sensitivity))
+	}
 }
 
 // GetSensitivity returns the current sensitivity
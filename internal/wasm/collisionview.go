@@ -0,0 +1,133 @@
+package wasm
+
+import (
+	"math"
+
+	"github.com/jonasrmichel/bobn/pkg/game"
+)
+
+// collisionViewAlpha is how strongly the broad-phase grid and its occupied
+// cells show, faint enough not to hide the outlines over them
+const collisionViewAlpha = 0.2
+
+// ToggleCollisionView shows or hides the collision view: every entity's
+// bounds, the barrier grid, the broad-phase grid with the cells invaders
+// occupy, the formation's envelope and the line invaders land on
+func (r *Renderer) ToggleCollisionView() {
+	r.debug.collisions = !r.debug.collisions
+}
+
+// renderCollisionView draws the collision view over the playfield
+func (r *Renderer) renderCollisionView(state *game.GameState) {
+	r.ctx.Call("save")
+	defer r.ctx.Call("restore")
+	r.ctx.Set("lineWidth", 1)
+
+	r.renderBroadphaseGrid(state)
+	r.renderBarrierGrid(state)
+
+	// The formation reverses when this reaches the playfield's edge, and the
+	// wave is lost when its slots reach the landing line
+	if len(state.Invaders) > 0 {
+		r.strokeBounds(game.FormationBounds(state.Invaders), r.theme.Heading)
+	}
+	landing := state.LandingLine()
+	r.ctx.Set("strokeStyle", r.theme.Danger)
+	r.ctx.Call("setLineDash", []any{6, 4})
+	r.ctx.Call("beginPath")
+	r.ctx.Call("moveTo", 0, landing)
+	r.ctx.Call("lineTo", r.screenWidth, landing)
+	r.ctx.Call("stroke")
+	r.ctx.Call("setLineDash", []any{})
+
+	state.EachEntity(func(entity game.Entity) {
+		switch entity := entity.(type) {
+		case *game.PlayerShip:
+			r.strokeBounds(entity.Bounds, r.theme.Player)
+		case *game.Invader:
+			r.strokeBounds(entity.Bounds, r.theme.Danger)
+		case *game.Boss:
+			r.strokeBounds(entity.Bounds, r.theme.Danger)
+			for _, point := range entity.WeakPoints {
+				r.strokeBounds(point.Bounds, r.theme.Warning)
+			}
+		case *game.UFO:
+			r.strokeBounds(entity.Bounds, r.theme.Warning)
+		case *game.Bullet:
+			color := r.theme.Danger
+			if entity.IsPlayerBullet {
+				color = r.theme.Good
+			}
+			r.strokeBounds(entity.Bounds, color)
+		case *game.Beam:
+			r.ctx.Set("strokeStyle", r.theme.Good)
+			r.ctx.Call("beginPath")
+			r.ctx.Call("moveTo", entity.X, entity.StartY)
+			r.ctx.Call("lineTo", entity.X, entity.EndY)
+			r.ctx.Call("stroke")
+		case *game.Pickup:
+			r.strokeBounds(entity.Bounds, r.theme.PowerUp)
+		case *game.Debris:
+			r.strokeBounds(entity.Bounds, r.theme.Text)
+		}
+	})
+}
+
+// renderBroadphaseGrid draws the collision system's grid, filling the
+// cells live invaders were inserted into
+func (r *Renderer) renderBroadphaseGrid(state *game.GameState) {
+	const cell = game.DefaultCellSize
+	width, height := float64(r.screenWidth), float64(r.screenHeight)
+
+	r.ctx.Set("globalAlpha", collisionViewAlpha)
+	r.ctx.Set("fillStyle", r.theme.Danger)
+	occupied := make(map[[2]int]bool)
+	for _, invader := range state.Invaders {
+		if !invader.Alive {
+			continue
+		}
+		b := invader.Bounds
+		for x := math.Floor(b.X / cell); x*cell < b.X+b.Width; x++ {
+			for y := math.Floor(b.Y / cell); y*cell < b.Y+b.Height; y++ {
+				key := [2]int{int(x), int(y)}
+				if !occupied[key] {
+					occupied[key] = true
+					r.ctx.Call("fillRect", x*cell, y*cell, cell, cell)
+				}
+			}
+		}
+	}
+
+	r.ctx.Set("strokeStyle", r.theme.Dim)
+	r.ctx.Call("beginPath")
+	for x := 0.0; x <= width; x += cell {
+		r.ctx.Call("moveTo", x, 0)
+		r.ctx.Call("lineTo", x, height)
+	}
+	for y := 0.0; y <= height; y += cell {
+		r.ctx.Call("moveTo", 0, y)
+		r.ctx.Call("lineTo", width, y)
+	}
+	r.ctx.Call("stroke")
+	r.ctx.Set("globalAlpha", 1.0)
+}
+
+// renderBarrierGrid outlines every barrier cell, solid blocks brightly and
+// knocked out ones faintly
+func (r *Renderer) renderBarrierGrid(state *game.GameState) {
+	for x, column := range state.Barriers {
+		for y, solid := range column {
+			color := r.theme.Dim
+			if solid {
+				color = r.theme.Barrier
+			}
+			r.strokeBounds(state.BarrierBlockBounds(x, y), color)
+		}
+	}
+}
+
+// strokeBounds outlines bounds in a color
+func (r *Renderer) strokeBounds(b game.Bounds, color string) {
+	r.ctx.Set("strokeStyle", color)
+	r.ctx.Call("strokeRect", b.X, b.Y, b.Width, b.Height)
+}
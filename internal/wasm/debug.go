@@ -0,0 +1,129 @@
+package wasm
+
+import (
+	"fmt"
+	"runtime"
+	"time"
+
+	"github.com/jonasrmichel/bobn/pkg/game"
+)
+
+// Debug overlay layout and sampling
+const (
+	debugGraphFrames   = 120                    // frames shown in the frame time graph
+	debugGraphScale    = 50.0                   // frame time in milliseconds filling the graph
+	debugFrameBudget   = 1000.0 / 60            // milliseconds a frame has at 60fps
+	debugHeapInterval  = 500 * time.Millisecond // reading memory stats stops the world, so not every frame
+	debugFPSSmoothing  = 0.1
+	debugPanelWidth    = 240
+	debugPanelHeight   = 170
+	debugGraphHeight   = 40
+	debugOverlayMargin = 10
+)
+
+// debugOverlay shows how the game is performing: frame rate, a graph of
+// recent frame times, the engine's own metrics and memory in use
+type debugOverlay struct {
+	enabled bool
+	metrics game.Metrics // the engine's, as of the frame being drawn
+
+	// Whether the collision view is drawn over the playfield
+	collisions bool
+
+	// Recent frame times in milliseconds, a ring with next the oldest
+	frames    [debugGraphFrames]float64
+	next      int
+	lastFrame time.Time
+	fps       float64
+
+	// Go heap in use and memory taken from the browser, in bytes
+	heap, sys   uint64
+	heapSampled time.Time
+}
+
+// ToggleDebugOverlay shows or hides the performance overlay
+func (r *Renderer) ToggleDebugOverlay() {
+	r.debug.enabled = !r.debug.enabled
+}
+
+// SetMetrics hands the renderer the engine's metrics for the overlay
+func (r *Renderer) SetMetrics(metrics game.Metrics) {
+	r.debug.metrics = metrics
+}
+
+// frame notes that a frame is being drawn now, sampling memory when it's due
+func (d *debugOverlay) frame(now time.Time) {
+	if !d.lastFrame.IsZero() {
+		elapsed := float64(now.Sub(d.lastFrame)) / float64(time.Millisecond)
+		d.frames[d.next] = elapsed
+		d.next = (d.next + 1) % debugGraphFrames
+		if elapsed > 0 {
+			d.fps += debugFPSSmoothing * (1000/elapsed - d.fps)
+		}
+	}
+	d.lastFrame = now
+
+	if d.enabled && now.Sub(d.heapSampled) >= debugHeapInterval {
+		var stats runtime.MemStats
+		runtime.ReadMemStats(&stats)
+		d.heap, d.sys = stats.HeapAlloc, stats.Sys
+		d.heapSampled = now
+	}
+}
+
+// renderDebugOverlay draws the overlay in the top left of the playfield
+func (r *Renderer) renderDebugOverlay() {
+	d := &r.debug
+	m := d.metrics
+	x, y := debugOverlayMargin, int(hudSafeArea+2*hudLineHeight)+debugOverlayMargin
+
+	r.ctx.Set("globalAlpha", 0.75)
+	r.ctx.Set("fillStyle", r.theme.Shadow)
+	r.ctx.Call("fillRect", x, y, debugPanelWidth, debugPanelHeight)
+	r.ctx.Set("globalAlpha", 1.0)
+
+	last := d.frames[(d.next+debugGraphFrames-1)%debugGraphFrames]
+	lines := []string{
+		fmt.Sprintf("FPS %.0f  FRAME %.1fMS", d.fps, last),
+		fmt.Sprintf("TICK %s  AVG %s  MAX %s", debugMillis(m.TickTime), debugMillis(m.AverageTickTime), debugMillis(m.MaxTickTime)),
+		fmt.Sprintf("ENTITIES %d  INVADERS %d", m.Entities, m.Invaders),
+		fmt.Sprintf("BULLETS %d  PICKUPS %d  DEBRIS %d", m.Bullets, m.Pickups, m.Debris),
+		fmt.Sprintf("COLLISION %d QUERIES  %d CHECKS", m.CollisionQueries, m.CollisionChecks),
+		fmt.Sprintf("HEAP %.1fMB  WASM %.1fMB", float64(d.heap)/(1<<20), float64(d.sys)/(1<<20)),
+	}
+	for i, line := range lines {
+		r.drawText(line, x+8, y+12+i*16, 11, r.theme.Text, "left")
+	}
+
+	r.renderFrameGraph(x+8, y+debugPanelHeight-debugGraphHeight-8, debugPanelWidth-16)
+}
+
+// renderFrameGraph draws recent frame times as bars, oldest at the left,
+// colored by whether they kept to 60 and 30 frames a second, with a line
+// marking the 60fps budget
+func (r *Renderer) renderFrameGraph(x, y, width int) {
+	d := &r.debug
+	bar := float64(width) / debugGraphFrames
+	bottom := float64(y + debugGraphHeight)
+	for i := range debugGraphFrames {
+		elapsed := d.frames[(d.next+i)%debugGraphFrames]
+		color := r.theme.Good
+		if elapsed > 2*debugFrameBudget {
+			color = r.theme.Danger
+		} else if elapsed > debugFrameBudget {
+			color = r.theme.Warning
+		}
+		height := min(elapsed/debugGraphScale, 1) * debugGraphHeight
+		r.ctx.Set("fillStyle", color)
+		r.ctx.Call("fillRect", float64(x)+float64(i)*bar, bottom-height, bar, height)
+	}
+
+	budget := bottom - debugFrameBudget/debugGraphScale*debugGraphHeight
+	r.ctx.Set("fillStyle", r.theme.Prompt)
+	r.ctx.Call("fillRect", x, budget, width, 1)
+}
+
+// debugMillis formats a duration in milliseconds
+func debugMillis(d time.Duration) string {
+	return fmt.Sprintf("%.2f", float64(d)/float64(time.Millisecond))
+}
@@ -0,0 +1,93 @@
+package wasm
+
+import (
+	"fmt"
+
+	"github.com/jonasrmichel/bobn/pkg/game"
+)
+
+// High score table layout, in playfield units from the playfield's center
+const (
+	scoreTableRowHeight = 20
+	scoreTableRank      = -150 // right edge of the rank column
+	scoreTableName      = -110 // left edge of the initials column
+	scoreTableScore     = 70   // right edge of the score column
+	scoreTableWave      = 150  // right edge of the wave column
+)
+
+// renderHighScoreMode renders the high score screen: the initials being
+// entered above the table, with the run's entry ranked in as it stands
+func (r *Renderer) renderHighScoreMode(state *game.GameState) {
+	heading := "GREAT SCORE!"
+	if state.NewHighScore {
+		heading = "NEW HIGH SCORE!"
+	}
+	r.drawStyledText(heading, r.screenWidth/2, 45, r.headingStyle(32, r.theme.Prompt))
+
+	entry := state.HighScoreEntry()
+	r.drawText(fmt.Sprintf("SCORE %06d  WAVE %d  -  ENTER YOUR INITIALS", entry.Score, entry.Wave), r.screenWidth/2, 85, 16, r.theme.Heading, "center")
+	r.drawLetterPicker(state.Initials[:], state.InitialsCursor, 135)
+
+	table, rank := state.HighScores.With(entry)
+	r.renderHighScoreTable(table, rank, 195)
+
+	r.drawHint("UP/DOWN OR HEAD FOR A LETTER, LEFT/RIGHT TO MOVE, ENTER TO ACCEPT", 470)
+}
+
+// renderAttractHighScores renders the best scores between demo games, the
+// last run's entry picked out if it made the table
+func (r *Renderer) renderAttractHighScores(state *game.GameState) {
+	r.drawStyledText("HIGH SCORES", r.screenWidth/2, 80, r.headingStyle(36, r.theme.Heading))
+	r.renderHighScoreTable(state.HighScores, state.HighScoreRank, 130)
+	if state.DailyHighScore > 0 {
+		r.drawText(fmt.Sprintf("DAILY BEST   %06d", state.DailyHighScore), r.screenWidth/2, 370, 16, r.theme.Warning, "center")
+	}
+}
+
+// renderHighScoreTable draws every rank of the table below a header row at
+// y, with the entry at highlight picked out. Ranks nobody has reached yet
+// show as blanks.
+func (r *Renderer) renderHighScoreTable(table game.HighScoreTable, highlight, y int) {
+	center := r.screenWidth / 2
+	r.drawText("RANK", center+scoreTableRank, y, 14, r.theme.Heading, "right")
+	r.drawText("NAME", center+scoreTableName, y, 14, r.theme.Heading, "left")
+	r.drawText("SCORE", center+scoreTableScore, y, 14, r.theme.Heading, "right")
+	r.drawText("WAVE", center+scoreTableWave, y, 14, r.theme.Heading, "right")
+
+	for rank := range game.HighScoreTableSize {
+		rowY := y + (rank+1)*scoreTableRowHeight
+		name, score, wave, color := "---", "------", "--", r.theme.Dim
+		if rank < len(table) {
+			entry := table[rank]
+			name, score, wave, color = entry.Name, fmt.Sprintf("%06d", entry.Score), fmt.Sprintf("%d", entry.Wave), r.theme.Text
+		}
+		if rank == highlight {
+			color = r.theme.Good
+			r.ctx.Set("globalAlpha", 0.3)
+			r.ctx.Set("fillStyle", r.theme.Dim)
+			r.ctx.Call("fillRect", center+scoreTableRank-50, rowY-scoreTableRowHeight/2, scoreTableWave-scoreTableRank+60, scoreTableRowHeight)
+			r.ctx.Set("globalAlpha", 1.0)
+		}
+
+		r.drawText(ordinal(rank+1), center+scoreTableRank, rowY, 16, color, "right")
+		r.drawText(name, center+scoreTableName, rowY, 16, color, "left")
+		r.drawText(score, center+scoreTableScore, rowY, 16, color, "right")
+		r.drawText(wave, center+scoreTableWave, rowY, 16, color, "right")
+	}
+}
+
+// ordinal returns a rank as arcade tables show it: 1ST, 2ND, 3RD, 4TH...
+func ordinal(rank int) string {
+	suffix := "TH"
+	if rank%100 < 11 || rank%100 > 13 {
+		switch rank % 10 {
+		case 1:
+			suffix = "ST"
+		case 2:
+			suffix = "ND"
+		case 3:
+			suffix = "RD"
+		}
+	}
+	return fmt.Sprintf("%d%s", rank, suffix)
+}
@@ -0,0 +1,345 @@
+package wasm
+
+import (
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/jonasrmichel/bobn/pkg/game"
+)
+
+// anchor is the edge or corner of the playfield a piece of the heads-up
+// display is laid out from
+type anchor int
+
+const (
+	anchorTopLeft anchor = iota
+	anchorTopCenter
+	anchorTopRight
+	anchorBottomLeft
+	anchorBottomCenter
+	anchorBottomRight
+)
+
+// align returns the text alignment that keeps text hugging the anchor
+func (a anchor) align() string {
+	switch a {
+	case anchorTopLeft, anchorBottomLeft:
+		return "left"
+	case anchorTopRight, anchorBottomRight:
+		return "right"
+	default:
+		return "center"
+	}
+}
+
+// HUD layout, in playfield units at a scale of 1
+const (
+	hudSafeArea   = 15.0 // inset from every edge, clear of bezels and rounded corners
+	hudLineHeight = 20.0 // distance between rows of the display
+	hudTextSize   = 16
+	maxHUDScale   = 1.5 // largest the display grows on a small canvas
+)
+
+// measureHUDScale returns how much to enlarge the heads-up display. Everything
+// shrinks with the canvas, so on a canvas shown smaller than the playfield
+// the display grows back toward its full size to stay readable.
+func (r *Renderer) measureHUDScale() float64 {
+	scale, _, _ := r.viewport()
+	shown := scale / r.bridge.GetDevicePixelRatio()
+	return math.Max(1, math.Min(1/shown, maxHUDScale))
+}
+
+// hudScale returns the display's scale for this frame
+func (r *Renderer) hudScale() float64 {
+	return r.hud.scale
+}
+
+// hudAt returns the playfield position inward from an anchor by the given
+// distances, which are scaled with the display
+func (r *Renderer) hudAt(a anchor, inwardX, inwardY float64) (int, int) {
+	scale := r.hudScale()
+	width, height := float64(r.screenWidth), float64(r.screenHeight)
+
+	var x, y float64
+	switch a {
+	case anchorTopLeft, anchorBottomLeft:
+		x = hudSafeArea + inwardX*scale
+	case anchorTopRight, anchorBottomRight:
+		x = width - hudSafeArea - inwardX*scale
+	default:
+		x = width/2 + inwardX*scale
+	}
+	switch a {
+	case anchorTopLeft, anchorTopCenter, anchorTopRight:
+		y = hudSafeArea + inwardY*scale
+	default:
+		y = height - hudSafeArea - inwardY*scale
+	}
+	return int(x), int(y)
+}
+
+// hudRow returns the middle of a row of the display counted inward from an
+// anchor, starting at 0
+func (r *Renderer) hudRow(a anchor, inwardX float64, row int) (int, int) {
+	return r.hudAt(a, inwardX, hudLineHeight/2+float64(row)*hudLineHeight)
+}
+
+// hudStyle is the style of the score and status lines, shadowed so they
+// stay readable over the action
+func (r *Renderer) hudStyle(color string, a anchor) TextStyle {
+	return TextStyle{Size: r.hudSize(hudTextSize), Color: color, Align: a.align(), Shadow: r.theme.Shadow}
+}
+
+// hudSize scales a size to the display
+func (r *Renderer) hudSize(size float64) int {
+	return int(math.Round(size * r.hudScale()))
+}
+
+// hudItem is one piece of the heads-up display: a line of text, or one of
+// the little ships counting lives. Items are compared frame to frame, so
+// blinking or typed-out text doesn't belong in the display.
+type hudItem struct {
+	text  string
+	x, y  int
+	style TextStyle
+	ship  bool
+}
+
+// hud is the score and status overlay. Each frame lists what it shows, and
+// the cached layer is only redrawn when the list differs from the last one.
+type hud struct {
+	layer canvasLayer
+	items []hudItem // what this frame shows
+	shown []hudItem // what the layer holds
+	scale float64   // measured once a frame
+}
+
+// hudText adds a line of text to this frame's heads-up display
+func (r *Renderer) hudText(text string, x, y int, style TextStyle) {
+	r.hud.items = append(r.hud.items, hudItem{text: text, x: x, y: y, style: style})
+}
+
+// hudLine adds a line of text on a row of the display
+func (r *Renderer) hudLine(text string, a anchor, inwardX float64, row int, color string) {
+	x, y := r.hudRow(a, inwardX, row)
+	r.hudText(text, x, y, r.hudStyle(color, a))
+}
+
+// hudShip adds a life's ship to this frame's heads-up display
+func (r *Renderer) hudShip(x, y int) {
+	r.hud.items = append(r.hud.items, hudItem{x: x, y: y, ship: true})
+}
+
+// paintHUD draws this frame's heads-up display, redrawing the layer if it
+// has changed since the last frame
+func (r *Renderer) paintHUD() {
+	h := &r.hud
+	if !slices.Equal(h.items, h.shown) {
+		h.shown = append(h.shown[:0], h.items...)
+		h.layer.invalidate()
+	}
+	r.paintLayer(&h.layer, func() {
+		for _, item := range h.shown {
+			if item.ship {
+				r.renderMiniShip(item.x, item.y)
+				continue
+			}
+			r.drawStyledText(item.text, item.x, item.y, item.style)
+		}
+	})
+	h.items = h.items[:0]
+}
+
+// renderUI renders the heads-up display: scores and lives along the top,
+// weapon, wave and combo along the bottom, and gauges for whatever is
+// running out
+func (r *Renderer) renderUI(state *game.GameState) {
+	r.hud.scale = r.measureHUDScale()
+	playing := state.Mode == game.Playing
+
+	// Score, one line per player in hotseat and co-op games
+	if state.IsMultiplayer() {
+		for i := range state.Slots {
+			color := r.theme.Dim
+			if i == state.CurrentSlot {
+				color = r.theme.Text
+			}
+			r.hudLine(fmt.Sprintf("P%d: %06d", i+1, state.SlotScore(i)), anchorTopLeft, 0, i, color)
+		}
+	} else if len(state.Players) > 1 {
+		for i, player := range state.Players {
+			r.hudLine(fmt.Sprintf("P%d: %06d", i+1, player.Score), anchorTopLeft, 0, i, r.theme.Text)
+		}
+	} else if player := state.PrimaryPlayer(); player != nil {
+		r.hudLine(fmt.Sprintf("SCORE: %06d", player.Score), anchorTopLeft, 0, 0, r.theme.Text)
+	}
+
+	// High score, then the objective and the boss's health beneath it
+	if state.Options.Daily {
+		r.hudLine(fmt.Sprintf("DAILY BEST: %06d", state.DailyHighScore), anchorTopCenter, 0, 0, r.theme.Prompt)
+	} else {
+		r.hudLine(fmt.Sprintf("HIGH: %06d", state.HighScore), anchorTopCenter, 0, 0, r.theme.Prompt)
+	}
+	row := 1
+	if playing && state.Challenge != nil && !state.WaveCleared {
+		r.renderObjective(state, row)
+		row++
+	}
+	if boss := state.Boss; playing && boss != nil && boss.Alive {
+		r.renderBossHealth(boss, row)
+	}
+
+	// Lives, one row per ship
+	if state.Options.Practice {
+		r.hudLine("PRACTICE", anchorTopRight, 0, 0, r.theme.Heading)
+	} else {
+		for row, player := range state.Players {
+			label := "LIVES:"
+			if len(state.Players) > 1 {
+				label = fmt.Sprintf("P%d:", row+1)
+			}
+			r.hudLine(label, anchorTopRight, 95, row, r.theme.Text)
+			for i := 0; i < player.Lives; i++ {
+				x, y := r.hudRow(anchorTopRight, 80-float64(i)*25, row)
+				r.hudShip(x, y-5)
+			}
+		}
+	}
+
+	// Wave, with the power-ups running down above it
+	if playing {
+		r.hudLine(fmt.Sprintf("WAVE %d", state.Wave), anchorBottomCenter, 0, 0, r.theme.Heading)
+	}
+
+	// Combo multiplier beneath a meter of the time left to keep it going
+	if playing && state.Combo > 1 {
+		r.hudLine(fmt.Sprintf("COMBO %d  x%d", state.Combo, state.ComboMultiplier()), anchorBottomRight, 0, 0, r.theme.Accent)
+		r.renderComboMeter(state)
+	}
+
+	// Developer cheats, when armed
+	if state.CheatStatus != "" {
+		x, y := r.hudRow(anchorBottomRight, 0, 2)
+		r.hudText(state.CheatStatus, x, y, TextStyle{Size: r.hudSize(12), Color: r.theme.Danger, Align: "right"})
+	}
+
+	// Player one's weapon, smart bombs and gauges
+	if player := state.PrimaryPlayer(); playing && player != nil && player.Ship != nil {
+		ship := player.Ship
+		r.hudLine(fmt.Sprintf("WEAPON: %s", ship.Weapon), anchorBottomLeft, 0, 0, r.theme.Good)
+		r.hudLine(fmt.Sprintf("BOMBS: %d", player.SmartBombs), anchorBottomLeft, 0, 1, r.theme.Warning)
+		r.renderDashPip(ship, 110, 1)
+		if ship.HeatEnabled {
+			r.renderHeatGauge(ship, 2)
+		}
+
+		timer := 1
+		if ship.HasTripleShot() {
+			r.renderPowerUpTimer("TRIPLE", ship.TripleShotTimer/game.TripleShotDuration, timer)
+			timer++
+		}
+		if ship.HasShield() {
+			r.renderPowerUpTimer("SHIELD", ship.ShieldTimer/game.ShieldDuration, timer)
+		}
+	}
+
+	// Text and lives come from the cached layer; the gauges above are live
+	r.paintHUD()
+}
+
+// renderObjective adds the challenge objective text and its status to the
+// heads-up display
+func (r *Renderer) renderObjective(state *game.GameState, row int) {
+	text := "CHALLENGE: " + state.Challenge.Text
+	color := r.theme.Prompt
+	if state.ChallengeStatus == game.ChallengeFailed {
+		text += "  - FAILED"
+		color = r.theme.Danger
+	} else if state.Challenge.Kind == game.ObjectiveTimeLimit {
+		text += fmt.Sprintf("  %.0f", math.Ceil(state.TimeRemaining()))
+	}
+	x, y := r.hudRow(anchorTopCenter, 0, row)
+	r.hudText(text, x, y, TextStyle{Size: r.hudSize(14), Color: color})
+}
+
+// renderHUDBar renders a gauge centered on (x, y) filled to a share of its
+// width
+func (r *Renderer) renderHUDBar(x, y int, width, height, filled float64, color string) {
+	scale := r.hudScale()
+	width, height = width*scale, height*scale
+	left, top := float64(x)-width/2, float64(y)-height/2
+
+	r.ctx.Set("strokeStyle", r.theme.Text)
+	r.ctx.Set("lineWidth", 1)
+	r.ctx.Call("strokeRect", left, top, width, height)
+	r.ctx.Set("fillStyle", color)
+	r.ctx.Call("fillRect", left, top, width*math.Max(math.Min(filled, 1), 0), height)
+}
+
+// renderBossHealth renders the boss's remaining health as a bar across the top
+func (r *Renderer) renderBossHealth(boss *game.Boss, row int) {
+	x, y := r.hudRow(anchorTopCenter, 0, row)
+	r.renderHUDBar(x, y, 200, 8, boss.HealthFraction(), r.theme.Danger)
+}
+
+// renderComboMeter renders the time left before the combo decays beneath
+// the combo count
+func (r *Renderer) renderComboMeter(state *game.GameState) {
+	const width = 100
+	x, y := r.hudRow(anchorBottomRight, width/2, 1)
+	r.renderHUDBar(x, y, width, 4, state.ComboTimer/game.ComboTimeout, r.theme.Accent)
+}
+
+// renderPowerUpTimer renders a power-up's name beside a bar of its
+// remaining time, on a row above the wave number
+func (r *Renderer) renderPowerUpTimer(label string, remaining float64, row int) {
+	const width = 80
+	x, y := r.hudRow(anchorBottomCenter, 0, row)
+	r.hudText(label, x-r.hudSize(width/2+6), y, TextStyle{Size: r.hudSize(12), Color: r.theme.PowerUp, Align: "right"})
+	r.renderHUDBar(x, y, width, 4, remaining, r.theme.PowerUp)
+}
+
+// renderDashPip renders the dash cooldown as a pip that fills as it
+// recharges, at the end of a row of the bottom left
+func (r *Renderer) renderDashPip(player *game.PlayerShip, inwardX float64, row int) {
+	radius := 5 * r.hudScale()
+	x, y := r.hudRow(anchorBottomLeft, inwardX, row)
+
+	r.ctx.Set("strokeStyle", r.theme.Heading)
+	r.ctx.Set("lineWidth", 1)
+	r.ctx.Call("beginPath")
+	r.ctx.Call("arc", x, y, radius, 0, math.Pi*2)
+	r.ctx.Call("stroke")
+
+	charged := 1 - player.DashCooldownTimer/game.DashCooldown
+	if charged <= 0 {
+		return
+	}
+	r.ctx.Set("fillStyle", r.theme.Heading)
+	r.ctx.Call("beginPath")
+	r.ctx.Call("moveTo", x, y)
+	r.ctx.Call("arc", x, y, radius, -math.Pi/2, -math.Pi/2+charged*math.Pi*2)
+	r.ctx.Call("closePath")
+	r.ctx.Call("fill")
+}
+
+// renderHeatGauge renders the weapon heat gauge on a row of the bottom left
+func (r *Renderer) renderHeatGauge(player *game.PlayerShip, row int) {
+	const width = 100
+
+	color := r.theme.Prompt
+	if player.Overheated {
+		color = r.theme.Danger
+	} else if player.Heat < 0.5 {
+		color = r.theme.Good
+	}
+
+	x, y := r.hudRow(anchorBottomLeft, width/2, row)
+	r.renderHUDBar(x, y, width, 8, player.Heat, color)
+
+	if player.Overheated {
+		x, y := r.hudRow(anchorBottomLeft, width+10, row)
+		r.hudText("OVERHEAT", x, y, TextStyle{Size: r.hudSize(12), Color: r.theme.Danger, Align: "left"})
+	}
+}
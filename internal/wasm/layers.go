@@ -0,0 +1,100 @@
+package wasm
+
+import (
+	"slices"
+	"syscall/js"
+
+	"github.com/jonasrmichel/bobn/pkg/game"
+)
+
+// canvasLayer is an offscreen canvas, the size of the playfield on the main
+// canvas, holding part of the frame between redraws. Drawing it back is one
+// image draw, so anything that changes less often than every frame can be
+// cached in one.
+type canvasLayer struct {
+	canvas js.Value
+	ctx    js.Value
+	valid  bool
+}
+
+// invalidate marks the layer to be redrawn before it is next shown
+func (l *canvasLayer) invalidate() {
+	l.valid = false
+}
+
+// layerStale reports whether the layer needs redrawing, because it has been
+// invalidated or hasn't yet been made at the given size in pixels
+func (r *Renderer) layerStale(layer *canvasLayer, width, height int) bool {
+	if !layer.canvas.Truthy() || layer.canvas.Get("width").Int() != width || layer.canvas.Get("height").Int() != height {
+		layer.canvas = r.bridge.NewCanvas(width, height)
+		layer.ctx = layer.canvas.Call("getContext", "2d")
+		layer.ctx.Set("imageSmoothingEnabled", false)
+		layer.valid = false
+	}
+	return !layer.valid
+}
+
+// redrawLayer clears the layer and draws it afresh. draw renders in
+// playfield units, as on the main canvas.
+func (r *Renderer) redrawLayer(layer *canvasLayer, draw func()) {
+	// Sprites go straight onto the layer rather than into the frame's batch
+	main, batch := r.ctx, r.batch
+	r.ctx, r.batch = layer.ctx, &canvasBatch{r: r}
+	r.ctx.Call("setTransform", 1, 0, 0, 1, 0, 0)
+	r.ctx.Call("clearRect", 0, 0, layer.canvas.Get("width"), layer.canvas.Get("height"))
+	r.scaleToCanvas()
+	draw()
+	r.ctx, r.batch = main, batch
+	layer.valid = true
+}
+
+// blitLayer draws the layer over the playfield, shifted down by dy playfield
+// units. It goes through the current transform, so cached layers shake along
+// with everything else.
+func (r *Renderer) blitLayer(layer *canvasLayer, dy float64) {
+	r.ctx.Call("drawImage", layer.canvas, 0, dy, r.screenWidth, r.screenHeight)
+}
+
+// paintLayer draws a layer matching the playfield's pixels on the main
+// canvas over the playfield, redrawing it first if it's stale
+func (r *Renderer) paintLayer(layer *canvasLayer, draw func()) {
+	if width, height := r.playfieldPixels(); r.layerStale(layer, width, height) {
+		r.redrawLayer(layer, draw)
+	}
+	r.blitLayer(layer, 0)
+}
+
+// invalidateLayers has every cached layer redrawn, for when the theme changes
+func (r *Renderer) invalidateLayers() {
+	r.background.invalidate()
+	r.barriers.layer.invalidate()
+	r.hud.layer.invalidate()
+	r.starfield.invalidate()
+}
+
+// barrierCache holds the barriers as they were last drawn. Blocks only
+// change when something hits them, so most frames draw the layer as is.
+type barrierCache struct {
+	layer   canvasLayer
+	blocks  [][]bool
+	origin  game.Vector2
+	size    float64
+	sprites bool // drawn from the sprite sheet rather than as plain blocks
+}
+
+// update notes how the barriers stand now, invalidating the layer if that
+// differs from when it was drawn
+func (c *barrierCache) update(state *game.GameState, sprites bool) {
+	same := sprites == c.sprites && state.BarrierOrigin == c.origin && state.BarrierBlockSize == c.size &&
+		slices.EqualFunc(state.Barriers, c.blocks, slices.Equal)
+	if same {
+		return
+	}
+
+	c.blocks = slices.Grow(c.blocks[:0], len(state.Barriers))[:len(state.Barriers)]
+	for x, column := range state.Barriers {
+		c.blocks[x] = append(c.blocks[x][:0], column...)
+	}
+	c.origin, c.size, c.sprites = state.BarrierOrigin, state.BarrierBlockSize, sprites
+	c.layer.invalidate()
+}
@@ -3,9 +3,12 @@ package wasm
 import (
 	"fmt"
 	"math"
+	"math/rand"
 	"syscall/js"
+	"time"
 
-	"github.com/jonasrmichel/bobn/internal/game"
+	"github.com/jonasrmichel/bobn/assets"
+	"github.com/jonasrmichel/bobn/pkg/game"
 )
 
 // Renderer handles all game rendering to the canvas
@@ -15,17 +18,263 @@ type Renderer struct {
 	pixelSize   int
 	screenWidth int
 	screenHeight int
+
+	// Colors of the active theme
+	theme Theme
+
+	// The backdrop fill, drawn once per canvas size and theme
+	background canvasLayer
+
+	// Drifting stars behind everything
+	starfield *Starfield
+
+	// Barriers, redrawn only when blocks are knocked out or rebuilt
+	barriers barrierCache
+
+	// Score and status overlay, redrawn only when it changes
+	hud hud
+
+	// Fades and wipes between screens
+	transitions Transitions
+
+	// Pixel-art sprites, drawn in place of the built-in shapes once the
+	// sheet has loaded
+	sprites      js.Value
+	spriteFrames map[string]assets.Frame
+
+	// Sprites and particles go through a batch, drawn with WebGL when the
+	// browser supports it unless the player turns it off. The WebGL batch is
+	// set up the first time it's asked for and kept, since browsers only
+	// allow a few contexts.
+	batch   quadBatch
+	glBatch *glBatch
+	glTried bool
+
+	// The transform the frame is drawn through, shake included, and the
+	// vertical flip withFlip has applied on top of it
+	frameTransform js.Value
+	flip           struct {
+		on bool
+		y  float64
+	}
+
+	// Screen shake, in pixels, dying away between frames
+	shakeEnabled bool
+	shake        float64
+	lastFrame    time.Time
+
+	// Retro monitor overlay, drawn once per canvas size and reused
+	crtEnabled bool
+	crtOverlay js.Value
+
+	// Strength of the glow around player shots, from 0 (off) to 1
+	glow float64
+
+	// Mark shots with arrowheads for players and bulbs for enemies, so
+	// they can be told apart without relying on color
+	shotShapes bool
+
+	// Frame rate and engine metrics, shown on demand
+	debug debugOverlay
 }
 
-// NewRenderer creates a new renderer
-func NewRenderer(bridge *JSBridge, screenWidth, screenHeight int) *Renderer {
-	return &Renderer{
+// letterboxColor fills the bars beside or above and below the playfield
+const letterboxColor = "#000000"
+
+// Screen shake tuning
+const (
+	maxShake      = 12.0 // strongest jolt, however many impacts pile up
+	shakeHalfLife = 0.08 // seconds for the jolt to halve
+)
+
+// NewRenderer creates a new renderer. It draws in playfield units, scaled to
+// the size of the canvas each frame.
+func NewRenderer(bridge *JSBridge) *Renderer {
+	r := &Renderer{
 		bridge:       bridge,
 		ctx:          bridge.GetContext(),
 		pixelSize:    2,
-		screenWidth:  screenWidth,
-		screenHeight: screenHeight,
+		screenWidth:  game.PlayfieldWidth,
+		screenHeight: game.PlayfieldHeight,
+		theme:        themes[defaultTheme],
+		starfield:    NewStarfield(game.PlayfieldWidth, game.PlayfieldHeight, time.Now().UnixNano()),
+		shakeEnabled: true,
+		glow:         game.DefaultSettings().Glow,
+	}
+	r.batch = newQuadBatch(r)
+	return r
+}
+
+// SetTheme switches to the named built-in theme, ignoring unknown names
+func (r *Renderer) SetTheme(name string) {
+	if theme, ok := themes[name]; ok {
+		r.theme = theme
+		r.invalidateLayers()
+	}
+}
+
+// Theme returns the colors being drawn with
+func (r *Renderer) Theme() Theme {
+	return r.theme
+}
+
+// SetSpriteSheet sets the image holding the sprites laid out by
+// assets.SheetFrames. Until it loads, entities are drawn as plain shapes.
+func (r *Renderer) SetSpriteSheet(sheet js.Value) {
+	r.sprites = sheet
+	r.spriteFrames = assets.SheetFrames()
+}
+
+// drawSprite draws the named sprite stretched over the bounds, reporting
+// false when there is no sprite sheet to draw from
+func (r *Renderer) drawSprite(name string, bounds game.Bounds) bool {
+	frame, ok := r.spriteFrames[name]
+	if !ok || !ImageReady(r.sprites) {
+		return false
+	}
+
+	r.batch.sprite(frame, bounds)
+	return true
+}
+
+// Shake jolts the screen by up to the given number of pixels. Impacts that
+// land together add up.
+func (r *Renderer) Shake(strength float64) {
+	if r.shakeEnabled {
+		r.shake = math.Min(r.shake+strength, maxShake)
+	}
+}
+
+// SetShakeEnabled turns screen shake on or off
+func (r *Renderer) SetShakeEnabled(enabled bool) {
+	r.shakeEnabled = enabled
+	if !enabled {
+		r.shake = 0
+	}
+}
+
+// applyShake offsets all drawing by a random jitter within the current
+// shake, then lets the shake die away by the time since the last frame
+func (r *Renderer) applyShake() {
+	now := time.Now()
+	elapsed := now.Sub(r.lastFrame).Seconds()
+	r.lastFrame = now
+	if r.shake < 0.1 {
+		r.shake = 0
+		return
+	}
+
+	dx := (rand.Float64()*2 - 1) * r.shake
+	dy := (rand.Float64()*2 - 1) * r.shake
+	r.ctx.Call("translate", dx, dy)
+	r.shake *= math.Pow(0.5, elapsed/shakeHalfLife)
+}
+
+// SetCRTEnabled turns the retro monitor overlay on or off
+func (r *Renderer) SetCRTEnabled(enabled bool) {
+	r.crtEnabled = enabled
+}
+
+// SetGlow sets how strongly player shots glow, from 0 (off) to 1
+func (r *Renderer) SetGlow(glow float64) {
+	r.glow = glow
+}
+
+// SetShotShapes turns shape coding of shots on or off
+func (r *Renderer) SetShotShapes(enabled bool) {
+	r.shotShapes = enabled
+}
+
+// SetWebGL lets the player opt out of WebGL. Enabled, sprites are drawn with
+// WebGL2 where the browser has it, as they are by default; disabled, they go
+// through the 2D canvas like everything else.
+func (r *Renderer) SetWebGL(enabled bool) {
+	if enabled {
+		r.batch = newQuadBatch(r)
+	} else {
+		r.batch = &canvasBatch{r: r}
+	}
+}
+
+// webGL returns the WebGL batch, setting it up the first time it's asked
+// for, or nil when the browser doesn't support WebGL2
+func (r *Renderer) webGL() *glBatch {
+	if !r.glTried {
+		r.glBatch, r.glTried = newGLBatch(r), true
 	}
+	return r.glBatch
+}
+
+// glowLine strokes a line with a soft halo around it. The halo is a few
+// wider, fainter strokes added onto what's beneath, which is far cheaper
+// than shadowBlur on low-end devices.
+func (r *Renderer) glowLine(x1, y1, x2, y2, width float64, color string) {
+	r.ctx.Set("strokeStyle", color)
+	r.ctx.Set("lineCap", "round")
+	if r.glow > 0 {
+		r.ctx.Set("globalCompositeOperation", "lighter")
+		for _, layer := range []struct{ width, alpha float64 }{{4, 0.12}, {2.5, 0.25}} {
+			r.ctx.Set("globalAlpha", layer.alpha*r.glow)
+			r.ctx.Set("lineWidth", width*layer.width)
+			r.ctx.Call("beginPath")
+			r.ctx.Call("moveTo", x1, y1)
+			r.ctx.Call("lineTo", x2, y2)
+			r.ctx.Call("stroke")
+		}
+		r.ctx.Set("globalCompositeOperation", "source-over")
+		r.ctx.Set("globalAlpha", 1.0)
+	}
+
+	r.ctx.Set("lineWidth", width)
+	r.ctx.Call("beginPath")
+	r.ctx.Call("moveTo", x1, y1)
+	r.ctx.Call("lineTo", x2, y2)
+	r.ctx.Call("stroke")
+	r.ctx.Set("lineCap", "butt")
+}
+
+// renderCRT covers the playfield with scanlines, a phosphor mask and a
+// vignette. The overlay is only redrawn when the playfield changes size on
+// the canvas, so the effect costs one image draw a frame.
+func (r *Renderer) renderCRT() {
+	width, height := r.playfieldPixels()
+	overlay := r.crtOverlay
+	if !overlay.Truthy() || overlay.Get("width").Int() != width || overlay.Get("height").Int() != height {
+		overlay = r.drawCRTOverlay(width, height)
+		r.crtOverlay = overlay
+	}
+
+	// The overlay matches the playfield's pixels one for one
+	r.ctx.Call("drawImage", overlay, 0, 0, r.screenWidth, r.screenHeight)
+}
+
+// drawCRTOverlay draws the monitor overlay for a canvas of the given size
+func (r *Renderer) drawCRTOverlay(width, height int) js.Value {
+	overlay := r.bridge.NewCanvas(width, height)
+	ctx := overlay.Call("getContext", "2d")
+
+	// Dark gaps between the scanlines
+	ctx.Set("fillStyle", "rgba(0, 0, 0, 0.3)")
+	for y := 0; y < height; y += 3 {
+		ctx.Call("fillRect", 0, y, width, 1)
+	}
+
+	// Faint red, green and blue phosphor stripes
+	phosphors := []string{"rgba(255, 0, 0, 0.04)", "rgba(0, 255, 0, 0.04)", "rgba(0, 0, 255, 0.04)"}
+	for x := 0; x < width; x++ {
+		ctx.Set("fillStyle", phosphors[x%len(phosphors)])
+		ctx.Call("fillRect", x, 0, 1, height)
+	}
+
+	// A soft glow at the center of the tube, darkening toward the corners
+	cx, cy := float64(width)/2, float64(height)/2
+	gradient := ctx.Call("createRadialGradient", cx, cy, 0, cx, cy, math.Hypot(cx, cy))
+	gradient.Call("addColorStop", 0, "rgba(255, 255, 255, 0.03)")
+	gradient.Call("addColorStop", 0.6, "rgba(0, 0, 0, 0)")
+	gradient.Call("addColorStop", 1, "rgba(0, 0, 0, 0.5)")
+	ctx.Set("fillStyle", gradient)
+	ctx.Call("fillRect", 0, 0, width, height)
+	return overlay
 }
 
 // SetContext sets the rendering context
@@ -33,46 +282,73 @@ func (r *Renderer) SetContext(ctx js.Value) {
 	r.ctx = ctx
 }
 
+// letterbox fits a playfield into a canvas as large as it will go without
+// changing its shape, centered, returning the scale and the offsets of the
+// playfield's corner. The canvas left over either side becomes bars.
+func letterbox(canvasWidth, canvasHeight, width, height float64) (scale, x, y float64) {
+	scale = math.Min(canvasWidth/width, canvasHeight/height)
+	return scale, (canvasWidth - width*scale) / 2, (canvasHeight - height*scale) / 2
+}
+
+// viewport returns where the playfield sits on the current canvas
+func (r *Renderer) viewport() (scale, x, y float64) {
+	canvas := r.ctx.Get("canvas")
+	return letterbox(canvas.Get("width").Float(), canvas.Get("height").Float(), float64(r.screenWidth), float64(r.screenHeight))
+}
+
+// playfieldPixels returns the size of the playfield on the canvas, in pixels
+func (r *Renderer) playfieldPixels() (width, height int) {
+	scale, _, _ := r.viewport()
+	return int(math.Round(float64(r.screenWidth) * scale)), int(math.Round(float64(r.screenHeight) * scale))
+}
+
+// scaleToCanvas maps playfield units onto the canvas, letterboxed
+func (r *Renderer) scaleToCanvas() {
+	scale, x, y := r.viewport()
+	r.ctx.Call("setTransform", scale, 0, 0, scale, x, y)
+}
+
 // Clear clears the canvas
 func (r *Renderer) Clear() {
 	if !r.ctx.Truthy() {
 		return // Context not set
 	}
 
-	r.ctx.Call("clearRect", 0, 0, r.screenWidth, r.screenHeight)
-
-	// Draw starfield background
-	r.ctx.Set("fillStyle", "#000000")
-	r.ctx.Call("fillRect", 0, 0, r.screenWidth, r.screenHeight)
+	// Draw the backdrop, which covers whatever the last frame left
+	r.paintLayer(&r.background, func() {
+		r.ctx.Set("fillStyle", r.theme.Background)
+		r.ctx.Call("fillRect", 0, 0, r.screenWidth, r.screenHeight)
+	})
 
 	// Draw stars
-	r.drawStarfield()
-}
-
-// drawStarfield draws a simple starfield background
-func (r *Renderer) drawStarfield() {
-	r.ctx.Set("fillStyle", "#ffffff")
-	// Static stars for now
-	stars := [][]int{
-		{100, 50}, {200, 80}, {300, 120}, {400, 30}, {500, 90},
-		{150, 200}, {250, 180}, {350, 220}, {450, 150}, {550, 210},
-		{120, 350}, {220, 380}, {320, 320}, {420, 330}, {520, 390},
-	}
-	for _, star := range stars {
-		r.ctx.Set("globalAlpha", 0.5)
-		r.ctx.Call("fillRect", star[0], star[1], 1, 1)
-	}
-	r.ctx.Set("globalAlpha", 1.0)
+	r.starfield.Draw(r, r.theme.Stars)
 }
 
 // RenderGame renders the entire game state
 func (r *Renderer) RenderGame(state *game.GameState) {
+	r.ctx.Call("save")
+	defer r.ctx.Call("restore")
+
+	// Bars fill whatever of the canvas the playfield doesn't, and nothing
+	// drawn in the playfield spills into them
+	r.ctx.Call("setTransform", 1, 0, 0, 1, 0, 0)
+	r.ctx.Set("fillStyle", letterboxColor)
+	r.ctx.Call("fillRect", 0, 0, r.ctx.Get("canvas").Get("width"), r.ctx.Get("canvas").Get("height"))
+	r.scaleToCanvas()
+	r.ctx.Call("beginPath")
+	r.ctx.Call("rect", 0, 0, r.screenWidth, r.screenHeight)
+	r.ctx.Call("clip")
+
+	// Note which screen is showing, for transitions and typed-out text
+	r.transitions.Update(state)
+
 	// Clear and draw background
 	r.Clear()
 
-	// Debug: Draw something to verify renderer works
-	r.ctx.Set("fillStyle", "#00ff00")
-	r.ctx.Call("fillRect", 10, 10, 50, 50)
+	// Everything in front of the backdrop shakes
+	r.applyShake()
+	r.frameTransform = r.ctx.Call("getTransform")
+	r.batch.begin()
 
 	switch state.Mode {
 	case game.AttractMode:
@@ -83,125 +359,621 @@ func (r *Renderer) RenderGame(state *game.GameState) {
 		r.renderGameOverMode(state)
 	case game.HighScore:
 		r.renderHighScoreMode(state)
+	case game.Summary:
+		r.renderSummaryMode(state)
+	case game.SettingsMenu:
+		r.renderSettingsMode(state)
+	case game.Continue:
+		r.renderPlayingMode(state)
+		r.renderContinueMode(state)
 	default:
 		// If no mode, show default screen
 		r.renderAttractMode(state)
 	}
 
+	// Put any sprites still queued beneath the UI
+	r.batch.flush()
+
+	// Collision outlines over the entities they belong to
+	if r.debug.collisions {
+		r.renderCollisionView(state)
+	}
+
 	// Always render UI elements
 	r.renderUI(state)
+
+	// Pause menu over the frozen game
+	if state.Mode == game.Playing && state.Paused {
+		r.renderPauseMenu(state)
+	}
+
+	// Achievement unlock notification
+	if len(state.AchievementToasts) > 0 {
+		r.renderAchievementToast(state.AchievementToasts[0])
+	}
+
+	// Label the attract-mode demo so it isn't mistaken for a real game
+	if state.Demo {
+		r.renderDemoBanner()
+	}
+
+	// Reveal a screen that just changed; the cover doesn't shake
+	r.scaleToCanvas()
+	r.transitions.Draw(r)
+
+	// Retro monitor over everything
+	if r.crtEnabled {
+		r.renderCRT()
+	}
+
+	// Performance overlay, kept clear of the monitor effect to stay legible
+	r.debug.frame(time.Now())
+	if r.debug.enabled {
+		r.renderDebugOverlay()
+	}
+}
+
+// renderPauseMenu renders the pause menu with the highlighted option marked
+func (r *Renderer) renderPauseMenu(state *game.GameState) {
+	r.drawShade()
+	r.drawStyledText("PAUSED", r.screenWidth/2, 160, r.headingStyle(36, r.theme.Prompt))
+
+	items := make([]string, game.PauseOptionCount)
+	for option := range game.PauseOptionCount {
+		items[option] = option.String()
+	}
+	r.drawMenuList(items, int(state.PauseSelection), 230, 40)
+
+	r.drawHint("UP/DOWN OR HEAD TO CHOOSE, ENTER TO SELECT, ESC TO RESUME", 420)
+}
+
+// renderAchievementToast renders an achievement unlock notification
+func (r *Renderer) renderAchievementToast(toast *game.AchievementToast) {
+	const width = 320
+	const height = 48
+	x := (r.screenWidth - width) / 2
+	y := 70
+
+	// Fade out over the last half second
+	r.ctx.Set("globalAlpha", math.Min(toast.Timer*2, 1.0))
+	r.ctx.Set("fillStyle", r.theme.Shadow)
+	r.ctx.Call("fillRect", x, y, width, height)
+	r.ctx.Set("strokeStyle", r.theme.Prompt)
+	r.ctx.Set("lineWidth", 2)
+	r.ctx.Call("strokeRect", x, y, width, height)
+
+	r.drawText("ACHIEVEMENT: "+toast.Achievement.Name, r.screenWidth/2, y+18, 14, r.theme.Prompt, "center")
+	r.drawText(toast.Achievement.Description, r.screenWidth/2, y+36, 12, r.theme.Text, "center")
+	r.ctx.Set("globalAlpha", 1.0)
+}
+
+// renderDemoBanner labels bot-played demo gameplay
+func (r *Renderer) renderDemoBanner() {
+	r.drawStyledText("DEMO", r.screenWidth/2, r.screenHeight/2-80, r.headingStyle(48, r.theme.Accent))
+	r.drawStyledText("PRESS ENTER TO START", r.screenWidth/2, r.screenHeight/2-40, r.promptStyle(20, r.theme.Prompt))
 }
 
 // renderAttractMode renders the attract mode screen
 func (r *Renderer) renderAttractMode(state *game.GameState) {
-	// Title
-	r.drawText("BOBN", r.screenWidth/2, 150, 48, "#00ff00", "center")
-	r.drawText("SPACE INVADERS", r.screenWidth/2, 200, 24, "#00ffff", "center")
+	// A shooting star now and then behind every screen
+	r.starfield.DrawShootingStar(r, r.theme.Stars)
 
-	// Instructions
-	r.drawText("USE ARROW KEYS TO MOVE", r.screenWidth/2, 300, 16, "#ffff00", "center")
-	r.drawText("PRESS SPACE TO FIRE", r.screenWidth/2, 330, 16, "#ffff00", "center")
+	switch state.AttractScreen {
+	case game.AttractHighScores:
+		r.renderAttractHighScores(state)
+	case game.AttractHowToPlay:
+		r.renderHowToPlay()
+	default:
+		r.renderTitle(state)
+	}
 
 	// Blinking insert coin
-	if int(js.Global().Get("Date").New().Call("getTime").Float()/500)%2 == 0 {
-		r.drawText("PRESS ENTER TO START", r.screenWidth/2, 400, 20, "#ff00ff", "center")
+	r.drawStyledText("PRESS ENTER TO START", r.screenWidth/2, 400, r.promptStyle(20, r.theme.Accent))
+}
+
+// renderTitle renders the title screen with the options for the next game
+func (r *Renderer) renderTitle(state *game.GameState) {
+	r.drawStyledText("BOBN", r.screenWidth/2, 150, r.headingStyle(48, r.theme.Title))
+	r.drawStyledText("SPACE INVADERS", r.screenWidth/2, 200, TextStyle{Size: 24, Color: r.theme.Heading, Shadow: r.theme.Shadow, Typewriter: 12})
+
+	// Difficulty selection, fixed to the daily rules in daily mode
+	if state.Options.Daily {
+		r.drawText(fmt.Sprintf("DAILY CHALLENGE %s", game.DailyDate(time.Now())), r.screenWidth/2, 255, 18, r.theme.Warning, "center")
+	} else {
+		r.drawText(fmt.Sprintf("< DIFFICULTY: %s >", state.Options.Difficulty), r.screenWidth/2, 255, 18, r.theme.Warning, "center")
+	}
+
+	// Options switched on for the next game
+	if state.Options.Mirror && state.Options.ScreenWrap {
+		r.drawText("MIRROR MODE, SCREEN WRAP", r.screenWidth/2, 230, 14, r.theme.Heading, "center")
+	} else if state.Options.Mirror {
+		r.drawText("MIRROR MODE", r.screenWidth/2, 230, 14, r.theme.Heading, "center")
+	} else if state.Options.ScreenWrap {
+		r.drawText("SCREEN WRAP", r.screenWidth/2, 230, 14, r.theme.Heading, "center")
+	}
+	if state.Options.Practice {
+		r.drawText("PRACTICE MODE: SCORE NOT RECORDED", r.screenWidth/2, 280, 14, r.theme.Heading, "center")
+	}
+	if state.Options.Coop {
+		r.drawText("CO-OP: P2 USES A/D TO MOVE, W TO FIRE", r.screenWidth/2, 300, 14, r.theme.Heading, "center")
+	} else if state.Options.TwoPlayer {
+		r.drawText("2 PLAYERS", r.screenWidth/2, 300, 14, r.theme.Heading, "center")
 	}
+	if state.Options.Ghost {
+		r.drawText("GHOST ON: RACING YOUR BEST RUN", r.screenWidth/2, 320, 14, r.theme.Heading, "center")
+	}
+	if state.Options.Debris {
+		r.drawText("DEBRIS FIELD ON", r.screenWidth/2, 340, 14, r.theme.Heading, "center")
+	}
+	r.drawText("PRESS O FOR SETTINGS", r.screenWidth/2, 365, 14, r.theme.Prompt, "center")
 
 	// High score
-	r.drawText(fmt.Sprintf("HIGH SCORE: %06d", state.HighScore), r.screenWidth/2, 450, 16, "#ffffff", "center")
+	if state.Options.Daily {
+		r.drawText(fmt.Sprintf("DAILY BEST: %06d", state.DailyHighScore), r.screenWidth/2, 465, 16, r.theme.Text, "center")
+	} else {
+		r.drawText(fmt.Sprintf("HIGH SCORE: %06d", state.HighScore), r.screenWidth/2, 465, 16, r.theme.Text, "center")
+	}
+}
+
+// howToPlay lists the controls, key and action
+var howToPlay = [][2]string{
+	{"HEAD OR ARROWS", "MOVE"},
+	{"SPACE", "FIRE"},
+	{"ESC", "PAUSE"},
+	{"Q", "SWITCH WEAPON"},
+	{"B", "SMART BOMB"},
+	{"T R M V", "DAILY, PRACTICE, MIRROR, WRAP"},
+	{"2 C", "TWO PLAYERS, CO-OP"},
+	{"G X", "GHOST RACE, DEBRIS FIELD"},
+}
+
+// renderHowToPlay renders the controls and what each invader is worth
+func (r *Renderer) renderHowToPlay() {
+	r.drawStyledText("HOW TO PLAY", r.screenWidth/2, 60, r.headingStyle(36, r.theme.Heading))
+
+	for i, control := range howToPlay {
+		y := 105 + i*20
+		r.drawText(control[0], r.screenWidth/2-20, y, 14, r.theme.Prompt, "right")
+		r.drawText(control[1], r.screenWidth/2+20, y, 14, r.theme.Text, "left")
+	}
+
+	// The score advance table, with each invader drawn as it appears in play
+	r.drawText("SCORE ADVANCE TABLE", r.screenWidth/2, 280, 16, r.theme.Heading, "center")
+	types := []game.InvaderType{game.InvaderTypeSmall, game.InvaderTypeMedium, game.InvaderTypeLarge, game.InvaderTypeKamikaze}
+	for i, invaderType := range types {
+		y := 305 + i*22
+		points := game.InvaderPoints(invaderType)
+		r.renderInvader(game.NewInvader(invaderType, float64(r.screenWidth/2-60), float64(y), points))
+		r.drawText(fmt.Sprintf("= %d POINTS", points), r.screenWidth/2-30, y, 14, r.theme.Text, "left")
+	}
+	r.batch.flush()
 }
 
 // renderPlayingMode renders the main game
 func (r *Renderer) renderPlayingMode(state *game.GameState) {
-	// Render player
-	if state.Player != nil {
-		r.renderPlayer(state.Player)
+	// Render barriers
+	r.renderBarriers(state)
+
+	// Sprites face the other way when the playfield is mirrored
+	mirrored := state.Orientation() == game.OrientationMirrored
+
+	// Render the ghost of the best previous run beneath the live ships
+	if state.Ghost != nil {
+		r.renderGhost(state.Ghost, mirrored)
 	}
 
-	// Render invaders
+	// The sprite layer: everything drawn from the sprite sheet, queued and
+	// then put on the canvas in one flush
+	for _, player := range state.Players {
+		if player.Ship != nil {
+			r.withFlip(mirrored, player.Ship.Position.Y, func() { r.renderPlayer(player.Ship) })
+		}
+	}
+	assembled := r.assembledRows(state)
 	for _, invader := range state.Invaders {
-		r.renderInvader(invader)
+		if !assembled(invader) {
+			continue
+		}
+		r.withFlip(mirrored, invader.Position.Y, func() { r.renderInvader(invader) })
 	}
+	for _, invader := range state.DyingInvaders {
+		r.renderSplat(invader)
+	}
+	if state.UFO != nil && state.UFO.Alive {
+		r.renderUFO(state.UFO)
+	}
+	for _, explosion := range state.Explosions {
+		r.RenderExplosion(explosion.Position.X, explosion.Position.Y, explosion.Size, explosion.Anim.Frame())
+	}
+	r.renderParticles(state.Particles)
+	r.batch.flush()
 
-	// Render bullets
+	// The canvas layer over it: shapes drawn on the canvas directly
+	for _, player := range state.Players {
+		if player.Ship != nil {
+			r.withFlip(mirrored, player.Ship.Position.Y, func() { r.renderPlayerOverlays(player.Ship) })
+		}
+		r.renderPlayerStatus(state, player)
+	}
+	if state.Boss != nil && state.Boss.Alive {
+		r.renderBoss(state.Boss)
+	}
 	for _, bullet := range state.Bullets {
 		r.renderBullet(bullet)
 	}
-
-	// Render UFO
+	for _, beam := range state.Beams {
+		r.renderBeam(beam)
+	}
 	if state.UFO != nil && state.UFO.Alive {
-		r.renderUFO(state.UFO)
+		r.renderUFOLights(state.UFO)
+	}
+	for _, debris := range state.Debris {
+		r.renderDebris(debris)
+	}
+	for _, pickup := range state.Pickups {
+		r.renderPickup(pickup)
+	}
+
+	// Render floating score popups
+	for _, popup := range state.ScorePopups {
+		r.renderScorePopup(popup)
+	}
+
+	// Render wave-clear interstitial
+	if state.WaveCleared {
+		r.renderWaveClear(state)
+	}
+
+	// Render the get-ready banner over the assembling wave
+	if state.WaveIntro > 0 {
+		r.renderWaveIntro(state)
+	}
+
+	// Smart bomb flash over the whole playfield
+	if state.BombFlash > 0 {
+		r.ctx.Set("globalAlpha", math.Min(state.BombFlash/game.SmartBombFlashDuration, 1.0)*0.8)
+		r.ctx.Set("fillStyle", r.theme.Flash)
+		r.ctx.Call("fillRect", 0, 0, r.screenWidth, r.screenHeight)
+		r.ctx.Set("globalAlpha", 1.0)
+	}
+}
+
+// renderSplat renders the classic splat where an invader was destroyed
+func (r *Renderer) renderSplat(invader *game.Invader) {
+	if r.drawSprite(assets.ExplosionFrame(0), invader.Bounds) {
+		return
+	}
+
+	// Short rays bursting out from the middle, in the invader's color
+	r.ctx.Set("strokeStyle", r.theme.invader(invader.Type))
+	r.ctx.Set("lineWidth", 2)
+	radius := math.Min(invader.Bounds.Width, invader.Bounds.Height) / 2
+	for i := 0; i < 8; i++ {
+		angle := float64(i) * math.Pi / 4
+		r.ctx.Call("beginPath")
+		r.ctx.Call("moveTo", invader.Position.X+math.Cos(angle)*radius*0.4, invader.Position.Y+math.Sin(angle)*radius*0.4)
+		r.ctx.Call("lineTo", invader.Position.X+math.Cos(angle)*radius, invader.Position.Y+math.Sin(angle)*radius)
+		r.ctx.Call("stroke")
+	}
+}
+
+// renderParticles renders particles as squares that fade as they die
+func (r *Renderer) renderParticles(particles []game.Particle) {
+	for _, particle := range particles {
+		color := r.theme.Flash
+		switch particle.Kind {
+		case game.ParticleSpark:
+			color = r.theme.invader(game.InvaderType(particle.Variant))
+		case game.ParticleRubble:
+			color = r.theme.Barrier
+		case game.ParticleExhaust:
+			color = r.theme.Exhaust
+		case game.ParticleWreckage:
+			color = r.theme.Player
+		case game.ParticleTrail:
+			color = r.theme.Trail
+		}
+
+		half := particle.Size / 2
+		r.batch.rect(particle.Position.X-half, particle.Position.Y-half, particle.Size, particle.Size, color, particle.Fade())
+	}
+	r.ctx.Set("globalAlpha", 1.0)
+}
+
+// renderGhost renders the replayed ship and its shots translucently
+func (r *Renderer) renderGhost(ghost *game.Ghost, mirrored bool) {
+	r.ctx.Set("globalAlpha", 0.35)
+
+	r.ctx.Set("strokeStyle", r.theme.Ghost)
+	r.ctx.Set("lineWidth", 2)
+	for _, shot := range ghost.Shots {
+		r.ctx.Call("beginPath")
+		r.ctx.Call("moveTo", shot.X, shot.Y-4)
+		r.ctx.Call("lineTo", shot.X, shot.Y+4)
+		r.ctx.Call("stroke")
+	}
+
+	if frame, ok := ghost.Current(); ok && frame.Alive {
+		ship := game.NewPlayerShip(frame.X, frame.Y)
+		r.withFlip(mirrored, frame.Y, func() { r.renderPlayer(ship) })
+		r.drawText("GHOST", int(frame.X), int(frame.Y)+28, 10, r.theme.Ghost, "center")
 	}
 
-	// Barriers not implemented yet - TODO: Add barriers later
+	r.ctx.Set("globalAlpha", 1.0)
+}
+
+// withFlip runs draw with the canvas flipped vertically about y when flip is set
+func (r *Renderer) withFlip(flip bool, y float64, draw func()) {
+	if !flip {
+		draw()
+		return
+	}
+	r.ctx.Call("save")
+	r.ctx.Call("translate", 0, 2*y)
+	r.ctx.Call("scale", 1, -1)
+	r.flip.on, r.flip.y = true, y
+	draw()
+	r.flip.on = false
+	r.ctx.Call("restore")
+}
+
+// renderWaveClear renders the wave-clear interstitial with the accuracy bonus
+func (r *Renderer) renderWaveClear(state *game.GameState) {
+	r.drawStyledText(fmt.Sprintf("WAVE %d CLEARED", state.Wave), r.screenWidth/2, r.screenHeight/2-40, r.headingStyle(32, r.theme.Good))
+	r.drawText(fmt.Sprintf("ACCURACY: %d%%  (%d/%d)", int(state.WaveAccuracy()*100), state.WaveShotsHit, state.WaveShotsFired), r.screenWidth/2, r.screenHeight/2+10, 18, r.theme.Text, "center")
+	r.drawText(fmt.Sprintf("BONUS: %d", state.AccuracyBonus), r.screenWidth/2, r.screenHeight/2+40, 18, r.theme.Prompt, "center")
+
+	switch state.ChallengeStatus {
+	case game.ChallengePassed:
+		r.drawText(fmt.Sprintf("OBJECTIVE COMPLETE: %d", state.ChallengeBonus), r.screenWidth/2, r.screenHeight/2+70, 18, r.theme.Good, "center")
+	case game.ChallengeFailed:
+		r.drawText("OBJECTIVE FAILED", r.screenWidth/2, r.screenHeight/2+70, 18, r.theme.Danger, "center")
+	}
+}
+
+// Wave intro pacing, as fractions of the intro
+const (
+	introAssembly = 0.6  // the formation has filled in by this point
+	introSlide    = 0.15 // the banner takes this long to slide in, and out
+)
+
+// assembledRows returns whether an invader's formation row has filled in
+// yet. A new wave assembles row by row from the back during its intro.
+func (r *Renderer) assembledRows(state *game.GameState) func(*game.Invader) bool {
+	if state.WaveIntro <= 0 || len(state.Invaders) == 0 {
+		return func(*game.Invader) bool { return true }
+	}
+
+	top, bottom := math.Inf(1), math.Inf(-1)
+	for _, invader := range state.Invaders {
+		top = math.Min(top, invader.Home.Y)
+		bottom = math.Max(bottom, invader.Home.Y)
+	}
+	progress := (1 - state.WaveIntro/game.WaveIntroDuration) / introAssembly
+	backward := state.Orientation().Advance() < 0
+	return func(invader *game.Invader) bool {
+		if bottom == top {
+			return true
+		}
+		depth := (invader.Home.Y - top) / (bottom - top)
+		if backward {
+			depth = 1 - depth
+		}
+		return depth <= progress
+	}
+}
+
+// renderWaveIntro slides the get-ready banner in from the left, holds it,
+// then slides it out to the right
+func (r *Renderer) renderWaveIntro(state *game.GameState) {
+	progress := 1 - state.WaveIntro/game.WaveIntroDuration
+	center := float64(r.screenWidth) / 2
+	offset := 0.0
+	switch {
+	case progress < introSlide:
+		offset = -center * (1 - progress/introSlide)
+	case progress > 1-introSlide:
+		offset = center * (progress - (1 - introSlide)) / introSlide
+	}
+
+	x := int(center + offset)
+	r.drawStyledText(fmt.Sprintf("WAVE %d", state.Wave), x, r.screenHeight/2-10, r.headingStyle(36, r.theme.Heading))
+	r.drawText("GET READY", x, r.screenHeight/2+30, 20, r.theme.Prompt, "center")
+}
+
+// renderBarriers renders the remaining barrier blocks, so shots and bombs
+// leave holes where they knocked blocks out
+func (r *Renderer) renderBarriers(state *game.GameState) {
+	r.barriers.update(state, ImageReady(r.sprites))
+	r.paintLayer(&r.barriers.layer, func() {
+		r.ctx.Set("fillStyle", r.theme.Barrier)
+		for x, column := range state.Barriers {
+			for y, solid := range column {
+				if !solid {
+					continue
+				}
+				block := state.BarrierBlockBounds(x, y)
+				if !r.drawSprite("barrier", block) {
+					r.ctx.Call("fillRect", block.X, block.Y, block.Width, block.Height)
+				}
+			}
+		}
+	})
 }
 
 // renderGameOverMode renders the game over screen
 func (r *Renderer) renderGameOverMode(state *game.GameState) {
-	r.drawText("GAME OVER", r.screenWidth/2, r.screenHeight/2-50, 48, "#ff0000", "center")
-	r.drawText(fmt.Sprintf("FINAL SCORE: %06d", state.Score), r.screenWidth/2, r.screenHeight/2+20, 24, "#ffffff", "center")
+	r.drawStyledText("GAME OVER", r.screenWidth/2, r.screenHeight/2-50, r.headingStyle(48, r.theme.Danger))
+	if len(state.Players) > 1 {
+		for i, player := range state.Players {
+			r.drawText(fmt.Sprintf("P%d FINAL SCORE: %06d", i+1, player.Score), r.screenWidth/2, r.screenHeight/2+10+i*28, 24, r.theme.Text, "center")
+		}
+	} else if player := state.PrimaryPlayer(); player != nil {
+		r.drawText(fmt.Sprintf("FINAL SCORE: %06d", player.Score), r.screenWidth/2, r.screenHeight/2+20, 24, r.theme.Text, "center")
 
-	if state.Score > state.HighScore {
-		r.drawText("NEW HIGH SCORE!", r.screenWidth/2, r.screenHeight/2+60, 20, "#ffff00", "center")
+		if state.NewHighScore {
+			r.drawText("NEW HIGH SCORE!", r.screenWidth/2, r.screenHeight/2+60, 20, r.theme.Prompt, "center")
+		}
 	}
 
-	if int(js.Global().Get("Date").New().Call("getTime").Float()/500)%2 == 0 {
-		r.drawText("PRESS ENTER TO CONTINUE", r.screenWidth/2, r.screenHeight/2+120, 16, "#00ff00", "center")
-	}
+	r.drawStyledText("PRESS ENTER TO CONTINUE", r.screenWidth/2, r.screenHeight/2+120, r.promptStyle(16, r.theme.Good))
 }
 
-// renderHighScoreMode renders the high score entry screen
-func (r *Renderer) renderHighScoreMode(state *game.GameState) {
-	r.drawText("NEW HIGH SCORE!", r.screenWidth/2, r.screenHeight/2-50, 36, "#ffff00", "center")
-	r.drawText(fmt.Sprintf("SCORE: %06d", state.Score), r.screenWidth/2, r.screenHeight/2, 24, "#ffffff", "center")
-	r.drawText("PRESS ENTER TO CONTINUE", r.screenWidth/2, r.screenHeight/2+80, 16, "#00ff00", "center")
+// renderContinueMode renders the continue countdown over the frozen game
+func (r *Renderer) renderContinueMode(state *game.GameState) {
+	r.ctx.Set("globalAlpha", 0.7)
+	r.ctx.Set("fillStyle", r.theme.Shadow)
+	r.ctx.Call("fillRect", 0, 0, r.screenWidth, r.screenHeight)
+	r.ctx.Set("globalAlpha", 1.0)
+
+	r.drawStyledText("CONTINUE?", r.screenWidth/2, 170, r.headingStyle(36, r.theme.Accent))
+	r.drawText(fmt.Sprintf("%d", int(math.Ceil(state.ContinueTimer))), r.screenWidth/2, 250, 64, r.theme.Prompt, "center")
+	r.drawText(fmt.Sprintf("CREDITS: %d", state.Credits), r.screenWidth/2, 310, 18, r.theme.Text, "center")
+
+	r.drawStyledText("PRESS SPACE TO CONTINUE - SCORE HALVED", r.screenWidth/2, 370, r.promptStyle(16, r.theme.Good))
 }
 
-// renderUI renders the UI elements (score, lives, etc.)
-func (r *Renderer) renderUI(state *game.GameState) {
-	// Score
-	r.drawText(fmt.Sprintf("SCORE: %06d", state.Score), 10, 30, 16, "#ffffff", "left")
+// renderSummaryMode renders the end-of-game results breakdown
+func (r *Renderer) renderSummaryMode(state *game.GameState) {
+	stats := state.Stats
+	minutes := int(stats.PlayTime) / 60
+	seconds := int(stats.PlayTime) % 60
 
-	// High Score
-	r.drawText(fmt.Sprintf("HIGH: %06d", state.HighScore), r.screenWidth/2, 30, 16, "#ffff00", "center")
+	r.drawStyledText("RESULTS", r.screenWidth/2, 120, r.headingStyle(36, r.theme.Heading))
 
-	// Lives
-	r.drawText("LIVES:", r.screenWidth-150, 30, 16, "#ffffff", "left")
-	for i := 0; i < state.Lives; i++ {
-		r.renderMiniShip(r.screenWidth-90+i*25, 25)
+	lines := []string{
+		fmt.Sprintf("WAVES CLEARED   %d", stats.WavesCleared),
+		fmt.Sprintf("ACCURACY        %d%%  (%d/%d)", int(stats.Accuracy()*100), stats.ShotsHit, stats.ShotsFired),
+		fmt.Sprintf("BEST COMBO      %d", stats.BestCombo),
+		fmt.Sprintf("UFOS HIT        %d", stats.UFOsHit),
+		fmt.Sprintf("TOTAL TIME      %d:%02d", minutes, seconds),
 	}
+	for i, line := range lines {
+		r.drawText(line, r.screenWidth/2-150, 190+i*36, 20, r.theme.Text, "left")
+	}
+
+	r.drawStyledText("PRESS ENTER TO CONTINUE", r.screenWidth/2, 420, r.promptStyle(16, r.theme.Good))
+}
+
+// renderSettingsMode renders the settings screen with the highlighted entry marked
+func (r *Renderer) renderSettingsMode(state *game.GameState) {
+	r.drawStyledText("SETTINGS", r.screenWidth/2, 90, r.headingStyle(36, r.theme.Heading))
+
+	for option := range game.SettingsOptionCount {
+		y := 125 + int(option)*21
+		selected := option == state.SettingsSelection
+		value := option.Value(state.Settings)
 
-	// Wave
-	if state.Mode == game.Playing {
-		r.drawText(fmt.Sprintf("WAVE %d", state.Wave), r.screenWidth/2, r.screenHeight-20, 16, "#00ffff", "center")
+		if option == game.SettingBack {
+			r.drawButtonRow(option.String(), selected, y)
+		} else if level, ok := option.Level(state.Settings); ok {
+			r.drawSliderRow(option.String(), value, level, selected, y)
+		} else if on, ok := option.Switch(state.Settings); ok {
+			r.drawToggleRow(option.String(), on, selected, y)
+		} else {
+			r.drawChoiceRow(option.String(), value, selected, y)
+		}
 	}
+
+	r.drawHint("UP/DOWN OR HEAD TO CHOOSE, LEFT/RIGHT TO CHANGE, ESC TO GO BACK", 445)
+}
+
+// shipVisible reports whether the ship shows this frame. It blinks while
+// invulnerable.
+func shipVisible(player *game.PlayerShip) bool {
+	return player.Alive && !(player.IsInvulnerable() && int(player.InvulnerableTimer*10)%2 == 0)
 }
 
 // renderPlayer renders the player ship
 func (r *Renderer) renderPlayer(player *game.PlayerShip) {
-	if !player.Alive {
+	if !shipVisible(player) {
 		return
 	}
 
-	// Draw ship body (triangle shape)
-	r.ctx.Set("fillStyle", "#00ff00")
+	if !r.drawSprite("player", player.Bounds) {
+		// Draw ship body (triangle shape)
+		r.ctx.Set("fillStyle", r.theme.Player)
+		r.ctx.Call("beginPath")
+		r.ctx.Call("moveTo", player.Position.X, player.Position.Y)
+		r.ctx.Call("lineTo", player.Position.X-15, player.Position.Y+20)
+		r.ctx.Call("lineTo", player.Position.X+15, player.Position.Y+20)
+		r.ctx.Call("closePath")
+		r.ctx.Call("fill")
+
+		// Draw cockpit
+		r.ctx.Set("fillStyle", r.theme.Cockpit)
+		r.ctx.Call("beginPath")
+		r.ctx.Call("arc", player.Position.X, player.Position.Y+5, 4, 0, math.Pi*2)
+		r.ctx.Call("fill")
+	}
+}
+
+// renderPlayerOverlays renders what is drawn over the ship: the charging
+// beam and the shield
+func (r *Renderer) renderPlayerOverlays(player *game.PlayerShip) {
+	if !shipVisible(player) {
+		return
+	}
+
+	if player.IsCharging() {
+		r.renderBeamCharge(player)
+	}
+
+	if player.HasShield() {
+		r.renderShield(player)
+	}
+}
+
+// renderBeamCharge renders the beam gathering at the ship's nose
+func (r *Renderer) renderBeamCharge(player *game.PlayerShip) {
+	radius := 2 + 6*player.ChargeProgress()
+	r.ctx.Set("fillStyle", r.theme.Beam)
 	r.ctx.Call("beginPath")
-	r.ctx.Call("moveTo", player.Position.X, player.Position.Y)
-	r.ctx.Call("lineTo", player.Position.X-15, player.Position.Y+20)
-	r.ctx.Call("lineTo", player.Position.X+15, player.Position.Y+20)
-	r.ctx.Call("closePath")
+	r.ctx.Call("arc", player.Position.X, player.Position.Y-2, radius, 0, math.Pi*2)
 	r.ctx.Call("fill")
+}
+
+// renderShield renders the bubble shield, flickering as it runs out
+func (r *Renderer) renderShield(player *game.PlayerShip) {
+	if player.ShieldTimer < 2 && int(player.ShieldTimer*8)%2 == 0 {
+		return
+	}
 
-	// Draw cockpit
-	r.ctx.Set("fillStyle", "#00ffff")
+	r.ctx.Set("globalAlpha", 0.25)
+	r.ctx.Set("fillStyle", r.theme.Shield)
 	r.ctx.Call("beginPath")
-	r.ctx.Call("arc", player.Position.X, player.Position.Y+5, 4, 0, math.Pi*2)
+	r.ctx.Call("arc", player.Position.X, player.Position.Y+10, 24, 0, math.Pi*2)
 	r.ctx.Call("fill")
+	r.ctx.Set("globalAlpha", 1.0)
+
+	r.ctx.Set("strokeStyle", r.theme.ShieldEdge)
+	r.ctx.Set("lineWidth", 2)
+	r.ctx.Call("stroke")
+}
+
+// renderPlayerStatus renders a player's respawn countdown. The explosion
+// while dying is one of the game's explosions.
+func (r *Renderer) renderPlayerStatus(state *game.GameState, player *game.Player) {
+	switch player.Status {
+	case game.PlayerRespawning:
+		countdown := int(math.Ceil(player.StatusTimer))
+
+		// Co-op players count down over their own spawn column
+		x := int(state.SpawnX(player.Index))
+		if len(state.Players) == 1 {
+			x = r.screenWidth / 2
+		}
+		if state.IsMultiplayer() {
+			r.drawText(fmt.Sprintf("PLAYER %d", state.CurrentSlot+1), x, r.screenHeight/2-36, 24, r.theme.Prompt, "center")
+		}
+		r.drawText("GET READY", x, r.screenHeight/2, 24, r.theme.Good, "center")
+		r.drawText(fmt.Sprintf("%d", countdown), x, r.screenHeight/2+36, 24, r.theme.Text, "center")
+	}
 }
 
 // renderMiniShip renders a small ship for lives display
 func (r *Renderer) renderMiniShip(x, y int) {
-	r.ctx.Set("fillStyle", "#00ff00")
+	r.ctx.Set("fillStyle", r.theme.Player)
 	r.ctx.Call("beginPath")
 	r.ctx.Call("moveTo", x, y)
 	r.ctx.Call("lineTo", x-8, y+10)
@@ -216,14 +988,13 @@ func (r *Renderer) renderInvader(invader *game.Invader) {
 		return
 	}
 
-	color := "#ffffff"
-	switch invader.Type {
-	case game.InvaderTypeSmall:
-		color = "#ff00ff"
-	case game.InvaderTypeMedium:
-		color = "#ffff00"
-	case game.InvaderTypeLarge:
-		color = "#00ffff"
+	if invader.Type == game.InvaderTypeKamikaze {
+		r.renderKamikaze(invader)
+		return
+	}
+	color := r.theme.invader(invader.Type)
+	if r.drawSprite(assets.InvaderFrame(int(invader.Type), invader.Anim.Frame()), invader.Bounds) {
+		return
 	}
 
 	// Simple invader shape
@@ -234,35 +1005,95 @@ func (r *Renderer) renderInvader(invader *game.Invader) {
 
 	// Arms (animate)
 	armOffset := 0
-	if invader.AnimFrame > 0 {
+	if invader.Anim.Frame() > 0 {
 		armOffset = 3
 	}
 	r.ctx.Call("fillRect", invader.Position.X-15, invader.Position.Y, 5, 5+armOffset)
 	r.ctx.Call("fillRect", invader.Position.X+10, invader.Position.Y, 5, 5+armOffset)
 
 	// Eyes
-	r.ctx.Set("fillStyle", "#000000")
+	r.ctx.Set("fillStyle", r.theme.Shadow)
 	r.ctx.Call("fillRect", invader.Position.X-6, invader.Position.Y-2, 3, 3)
 	r.ctx.Call("fillRect", invader.Position.X+3, invader.Position.Y-2, 3, 3)
 }
 
+// renderKamikaze renders a kamikaze invader as a downward-pointing spike
+func (r *Renderer) renderKamikaze(invader *game.Invader) {
+	x, y := invader.Position.X, invader.Position.Y
+
+	color := r.theme.Kamikaze
+	if invader.MoveState == game.InvaderCharging && invader.Anim.Frame() > 0 {
+		color = r.theme.Flash // Flash while charging
+	}
+
+	r.ctx.Set("fillStyle", color)
+	r.ctx.Call("beginPath")
+	r.ctx.Call("moveTo", x-10, y-8)
+	r.ctx.Call("lineTo", x+10, y-8)
+	r.ctx.Call("lineTo", x, y+10)
+	r.ctx.Call("closePath")
+	r.ctx.Call("fill")
+
+	// Eye
+	r.ctx.Set("fillStyle", r.theme.Shadow)
+	r.ctx.Call("fillRect", x-2, y-4, 4, 4)
+}
+
+// renderBeam renders a charged beam as a bright column that fades out
+func (r *Renderer) renderBeam(beam *game.Beam) {
+	top := math.Min(beam.StartY, beam.EndY)
+	height := math.Abs(beam.EndY - beam.StartY)
+	fade := math.Max(beam.Timer/game.BeamDuration, 0)
+
+	// Outer glow
+	r.ctx.Set("globalAlpha", 0.4*fade)
+	r.ctx.Set("fillStyle", r.theme.Beam)
+	r.ctx.Call("fillRect", beam.X-8, top, 16, height)
+
+	// Wide halo lighting up what's behind the beam
+	if r.glow > 0 {
+		r.ctx.Set("globalCompositeOperation", "lighter")
+		r.ctx.Set("globalAlpha", 0.15*fade*r.glow)
+		r.ctx.Call("fillRect", beam.X-20, top, 40, height)
+		r.ctx.Set("globalCompositeOperation", "source-over")
+	}
+
+	// Hot core
+	r.ctx.Set("globalAlpha", fade)
+	r.ctx.Set("fillStyle", r.theme.Flash)
+	r.ctx.Call("fillRect", beam.X-2, top, 4, height)
+	r.ctx.Set("globalAlpha", 1.0)
+}
+
 // renderBullet renders a bullet
 func (r *Renderer) renderBullet(bullet *game.Bullet) {
 	if !bullet.Alive {
 		return
 	}
 
-	if bullet.IsPlayerBullet {
-		// Player bullet - vertical line
-		r.ctx.Set("strokeStyle", "#00ff00")
-		r.ctx.Set("lineWidth", 2)
+	if bullet.Piercing {
+		// Laser bolt - long bright beam
+		r.glowLine(bullet.Position.X, bullet.Position.Y-bullet.Bounds.Height/2,
+			bullet.Position.X, bullet.Position.Y+bullet.Bounds.Height/2, 3, r.theme.Laser)
+	} else if bullet.Homing {
+		// Homing bullet - orange diamond
+		r.ctx.Set("fillStyle", r.theme.Homing)
 		r.ctx.Call("beginPath")
-		r.ctx.Call("moveTo", bullet.Position.X, bullet.Position.Y)
-		r.ctx.Call("lineTo", bullet.Position.X, bullet.Position.Y+8)
-		r.ctx.Call("stroke")
+		r.ctx.Call("moveTo", bullet.Position.X, bullet.Position.Y-5)
+		r.ctx.Call("lineTo", bullet.Position.X+4, bullet.Position.Y)
+		r.ctx.Call("lineTo", bullet.Position.X, bullet.Position.Y+5)
+		r.ctx.Call("lineTo", bullet.Position.X-4, bullet.Position.Y)
+		r.ctx.Call("closePath")
+		r.ctx.Call("fill")
+	} else if bullet.IsPlayerBullet {
+		// Player bullet - vertical line
+		r.glowLine(bullet.Position.X, bullet.Position.Y, bullet.Position.X, bullet.Position.Y+8, 2, r.theme.PlayerShot)
+		if r.shotShapes {
+			r.renderShotArrowhead(bullet)
+		}
 	} else {
 		// Enemy bullet - zigzag
-		r.ctx.Set("strokeStyle", "#ff0000")
+		r.ctx.Set("strokeStyle", r.theme.EnemyShot)
 		r.ctx.Set("lineWidth", 2)
 		r.ctx.Call("beginPath")
 		r.ctx.Call("moveTo", bullet.Position.X-2, bullet.Position.Y)
@@ -270,62 +1101,259 @@ func (r *Renderer) renderBullet(bullet *game.Bullet) {
 		r.ctx.Call("lineTo", bullet.Position.X-2, bullet.Position.Y+6)
 		r.ctx.Call("lineTo", bullet.Position.X+2, bullet.Position.Y+9)
 		r.ctx.Call("stroke")
+		if r.shotShapes {
+			r.renderShotBulb(bullet)
+		}
 	}
 }
 
+// shotLead returns the end of a shot in its direction of travel, and which
+// way along y that is
+func shotLead(bullet *game.Bullet, length float64) (x, y, heading float64) {
+	heading = 1.0
+	if bullet.Velocity.Y < 0 {
+		heading = -1
+	}
+	y = bullet.Position.Y
+	if heading > 0 {
+		y += length
+	}
+	return bullet.Position.X, y, heading
+}
+
+// renderShotArrowhead caps a player shot with an arrowhead pointing the way
+// it flies
+func (r *Renderer) renderShotArrowhead(bullet *game.Bullet) {
+	x, y, heading := shotLead(bullet, 8)
+	r.ctx.Set("strokeStyle", r.theme.PlayerShot)
+	r.ctx.Set("lineWidth", 2)
+	r.ctx.Call("beginPath")
+	r.ctx.Call("moveTo", x-4, y-4*heading)
+	r.ctx.Call("lineTo", x, y)
+	r.ctx.Call("lineTo", x+4, y-4*heading)
+	r.ctx.Call("stroke")
+}
+
+// renderShotBulb caps an enemy shot with a hollow bulb at its leading end
+func (r *Renderer) renderShotBulb(bullet *game.Bullet) {
+	x, y, _ := shotLead(bullet, 9)
+	r.ctx.Set("strokeStyle", r.theme.EnemyShot)
+	r.ctx.Set("lineWidth", 1.5)
+	r.ctx.Call("beginPath")
+	r.ctx.Call("arc", x, y, 3, 0, math.Pi*2)
+	r.ctx.Call("stroke")
+}
+
 // renderUFO renders the UFO
 func (r *Renderer) renderUFO(ufo *game.UFO) {
 	if !ufo.Alive {
 		return
 	}
+	if !r.drawSprite("ufo", ufo.Bounds) {
+		// UFO body
+		r.ctx.Set("fillStyle", r.theme.UFO)
+		r.ctx.Call("beginPath")
+		r.ctx.Call("ellipse", ufo.Position.X, ufo.Position.Y, 20, 8, 0, 0, math.Pi*2)
+		r.ctx.Call("fill")
+
+		// Dome
+		r.ctx.Set("fillStyle", r.theme.UFODome)
+		r.ctx.Call("beginPath")
+		r.ctx.Call("arc", ufo.Position.X, ufo.Position.Y-5, 8, math.Pi, 0)
+		r.ctx.Call("fill")
+	}
+}
+
+// renderUFOLights renders the lights chasing around the UFO's rim, one lit
+// at a time in the direction of travel
+func (r *Renderer) renderUFOLights(ufo *game.UFO) {
+	lit := ufo.Anim.Frame()
+	if ufo.Direction < 0 {
+		lit = 3 - lit
+	}
+	for i := 0; i < 4; i++ {
+		r.ctx.Set("fillStyle", r.theme.UFOLight)
+		if i == lit {
+			r.ctx.Set("fillStyle", r.theme.Flash)
+		}
+		r.ctx.Call("beginPath")
+		r.ctx.Call("arc", ufo.Position.X-15+float64(i)*10, ufo.Position.Y, 2, 0, math.Pi*2)
+		r.ctx.Call("fill")
+	}
+}
+
+// renderScorePopup renders a floating score. The UFO's mystery score
+// blinks between colors as it rises.
+func (r *Renderer) renderScorePopup(popup *game.ScorePopup) {
+	color := r.theme.Accent
+	if popup.Flash && int(popup.Timer/0.1)%2 == 0 {
+		color = r.theme.Flash
+	}
+	r.drawText(fmt.Sprintf("%d", popup.Points), int(popup.Position.X), int(popup.Position.Y), 16, color, "center")
+}
+
+// renderDebris renders a debris chunk as a rough rock that shrinks as it erodes
+func (r *Renderer) renderDebris(debris *game.Debris) {
+	if !debris.Alive {
+		return
+	}
 
-	// UFO body
-	r.ctx.Set("fillStyle", "#ff00ff")
+	x, y := debris.Position.X, debris.Position.Y
+	radius := debris.Bounds.Width / 2
+
+	// Jagged outline from a fixed set of vertex offsets
+	offsets := []float64{1.0, 0.8, 0.95, 0.75, 1.0, 0.85, 0.9, 0.7}
+	r.ctx.Set("fillStyle", r.theme.Debris)
 	r.ctx.Call("beginPath")
-	r.ctx.Call("ellipse", ufo.Position.X, ufo.Position.Y, 20, 8, 0, 0, math.Pi*2)
+	for i, offset := range offsets {
+		angle := float64(i) / float64(len(offsets)) * math.Pi * 2
+		px := x + math.Cos(angle)*radius*offset
+		py := y + math.Sin(angle)*radius*offset
+		if i == 0 {
+			r.ctx.Call("moveTo", px, py)
+		} else {
+			r.ctx.Call("lineTo", px, py)
+		}
+	}
+	r.ctx.Call("closePath")
 	r.ctx.Call("fill")
 
-	// Dome
-	r.ctx.Set("fillStyle", "#ffff00")
+	// Crater
+	r.ctx.Set("fillStyle", r.theme.DebrisCrater)
 	r.ctx.Call("beginPath")
-	r.ctx.Call("arc", ufo.Position.X, ufo.Position.Y-5, 8, math.Pi, 0)
+	r.ctx.Call("arc", x-radius*0.25, y-radius*0.2, radius*0.25, 0, math.Pi*2)
 	r.ctx.Call("fill")
+}
 
-	// Lights
-	r.ctx.Set("fillStyle", "#ffffff")
-	for i := -15; i <= 15; i += 10 {
-		if int(js.Global().Get("Date").New().Call("getTime").Float()/200)%2 == 0 {
-			r.ctx.Call("beginPath")
-			r.ctx.Call("arc", ufo.Position.X+float64(i), ufo.Position.Y, 2, 0, math.Pi*2)
-			r.ctx.Call("fill")
-		}
+// renderPickup renders a bonus token as a pulsing diamond, gold for points,
+// blue for shields and pink for triple shot
+func (r *Renderer) renderPickup(pickup *game.Pickup) {
+	if !pickup.Alive {
+		return
 	}
+
+	x, y := pickup.Position.X, pickup.Position.Y
+	size := pickup.Bounds.Width / 2
+	if pickup.Anim.Frame() == 0 {
+		size -= 2
+	}
+
+	color := r.theme.PickupPoints
+	switch pickup.Kind {
+	case game.PickupShield:
+		color = r.theme.PickupShield
+	case game.PickupTripleShot:
+		color = r.theme.PowerUp
+	}
+	r.ctx.Set("fillStyle", color)
+	r.ctx.Call("beginPath")
+	r.ctx.Call("moveTo", x, y-size)
+	r.ctx.Call("lineTo", x+size, y)
+	r.ctx.Call("lineTo", x, y+size)
+	r.ctx.Call("lineTo", x-size, y)
+	r.ctx.Call("closePath")
+	r.ctx.Call("fill")
+
+	r.ctx.Set("fillStyle", r.theme.Flash)
+	r.ctx.Call("fillRect", x-1, y-1, 2, 2)
 }
 
+// renderBoss renders the boss and its health bar
+func (r *Renderer) renderBoss(boss *game.Boss) {
+	x, y := boss.Position.X, boss.Position.Y
+	halfWidth := boss.Bounds.Width / 2
+	halfHeight := boss.Bounds.Height / 2
 
-// drawText renders text to the canvas
-func (r *Renderer) drawText(text string, x, y int, size int, color, align string) {
-	if !r.ctx.Truthy() {
-		return
+	color := r.theme.Boss
+	if boss.HitTimer > 0 {
+		color = r.theme.Flash // Flash when hit
 	}
 
-	// Use a fallback font that's guaranteed to work
-	r.ctx.Set("font", fmt.Sprintf("%dpx monospace", size))
+	// Hull
 	r.ctx.Set("fillStyle", color)
-	r.ctx.Set("textAlign", align)
-	r.ctx.Set("textBaseline", "middle")
-	r.ctx.Call("fillText", text, x, y)
+	r.ctx.Call("beginPath")
+	r.ctx.Call("moveTo", x-halfWidth, y)
+	r.ctx.Call("lineTo", x-halfWidth/2, y-halfHeight)
+	r.ctx.Call("lineTo", x+halfWidth/2, y-halfHeight)
+	r.ctx.Call("lineTo", x+halfWidth, y)
+	r.ctx.Call("lineTo", x+halfWidth/2, y+halfHeight)
+	r.ctx.Call("lineTo", x-halfWidth/2, y+halfHeight)
+	r.ctx.Call("closePath")
+	r.ctx.Call("fill")
+
+	// Weak points: the cannons and the eye
+	for _, point := range boss.WeakPoints {
+		r.renderWeakPoint(point, boss.Anim.Frame())
+	}
+
+}
+
+// renderWeakPoint renders a boss weak point colored by its damage: yellow
+// while healthy, through orange to red, and dark once knocked out
+func (r *Renderer) renderWeakPoint(point *game.WeakPoint, animFrame int) {
+	b := point.Bounds
+	centerX, centerY := b.X+b.Width/2, b.Y+b.Height/2
+
+	color := r.theme.WeakPoint
+	switch health := point.HealthFraction(); {
+	case point.Destroyed():
+		color = r.theme.WeakPointDead
+	case point.HitTimer > 0:
+		color = r.theme.Flash // Flash when hit
+	case health <= 0.25:
+		color = r.theme.WeakPointCritical
+	case health <= 0.5:
+		color = r.theme.WeakPointHurt
+	}
+	r.ctx.Set("fillStyle", color)
+
+	switch point.Kind {
+	case game.WeakPointCore:
+		r.ctx.Call("beginPath")
+		r.ctx.Call("arc", centerX, centerY, b.Width/2, 0, math.Pi*2)
+		r.ctx.Call("fill")
+		if point.Destroyed() {
+			// Cracked shut
+			r.ctx.Set("strokeStyle", r.theme.Shadow)
+			r.ctx.Set("lineWidth", 2)
+			r.ctx.Call("beginPath")
+			r.ctx.Call("moveTo", b.X+3, b.Y+3)
+			r.ctx.Call("lineTo", b.X+b.Width-3, b.Y+b.Height-3)
+			r.ctx.Call("moveTo", b.X+b.Width-3, b.Y+3)
+			r.ctx.Call("lineTo", b.X+3, b.Y+b.Height-3)
+			r.ctx.Call("stroke")
+		}
+	case game.WeakPointCannon:
+		if point.Destroyed() {
+			// Blown off, leaving a stub
+			r.ctx.Call("fillRect", b.X, centerY-b.Height/4, b.Width, b.Height/2)
+			return
+		}
+		// Recoil with the animation
+		recoil := 0.0
+		if animFrame > 0 {
+			recoil = 3
+		}
+		r.ctx.Call("fillRect", b.X, b.Y+recoil, b.Width, b.Height-recoil)
+	}
 }
 
-// RenderExplosion renders an explosion effect
-func (r *Renderer) RenderExplosion(x, y float64, frame int) {
+// RenderExplosion renders an explosion effect the given width
+func (r *Renderer) RenderExplosion(x, y, size float64, frame int) {
 	if frame >= 10 {
 		return
 	}
 
+	// The sprite's frames each cover a stretch of the ten-frame burst
+	sprite := assets.ExplosionFrame(frame * assets.ExplosionFrames / 10)
+	height := size * 25 / 30
+	if r.drawSprite(sprite, game.Bounds{X: x - size/2, Y: y - height/2, Width: size, Height: height}) {
+		return
+	}
+
 	// Expanding circle of particles
-	r.ctx.Set("fillStyle", "#ff0000")
-	radius := float64(frame * 3)
+	r.ctx.Set("fillStyle", r.theme.Explosion)
+	radius := float64(frame) * size / 10
 	alpha := 1.0 - float64(frame)/10.0
 	r.ctx.Set("globalAlpha", alpha)
 
@@ -340,4 +1368,4 @@ func (r *Renderer) RenderExplosion(x, y float64, frame int) {
 	}
 
 	r.ctx.Set("globalAlpha", 1.0)
-}
\ No newline at end of file
+}
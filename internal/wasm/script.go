@@ -0,0 +1,133 @@
+package wasm
+
+import (
+	"syscall/js"
+
+	"github.com/jonasrmichel/bobn/pkg/game"
+)
+
+// JSScript drives wave and boss behavior from JavaScript hooks, so designers
+// can change enemy behavior by editing a script instead of rebuilding the
+// WASM binary. The hooks are methods on a plain object, all optional:
+//
+//	onWaveStart({wave, seed, boss, invaders})   -> {speedScale, shootScale}
+//	onInvaderKilled({x, y, type, points, wave, player})
+//	moveInvader({id, type, x, y, homeX, homeY, targetX, state}, dt) -> {x, y}
+//	steerBoss({x, y, vx, vy, health, pattern}, dt) -> {vx, vy, pattern}
+//
+// Hooks that return nothing leave the built-in behavior in place.
+type JSScript struct {
+	game.NoScript
+	hooks js.Value
+}
+
+// LoadScript returns the script defined by the global object with the given
+// name, or nil when the page defines none
+func LoadScript(name string) *JSScript {
+	hooks := js.Global().Get(name)
+	if hooks.IsUndefined() || hooks.IsNull() {
+		return nil
+	}
+	return &JSScript{hooks: hooks}
+}
+
+// call invokes the named hook, reporting false when the script lacks it
+func (s *JSScript) call(name string, args ...any) (js.Value, bool) {
+	hook := s.hooks.Get(name)
+	if hook.Type() != js.TypeFunction {
+		return js.Undefined(), false
+	}
+	return hook.Invoke(args...), true
+}
+
+// OnWaveStart lets the script retune the wave's speed and fire rate
+func (s *JSScript) OnWaveStart(gs *game.GameState) {
+	invaders := make([]any, len(gs.Invaders))
+	for i, invader := range gs.Invaders {
+		invaders[i] = invaderObject(invader)
+	}
+	result, ok := s.call("onWaveStart", map[string]any{
+		"wave":     gs.Wave,
+		"seed":     float64(gs.Seed),
+		"boss":     gs.Boss != nil,
+		"invaders": invaders,
+	})
+	if !ok || result.Type() != js.TypeObject {
+		return
+	}
+
+	if speed := result.Get("speedScale"); speed.Type() == js.TypeNumber {
+		gs.WaveSpec.SpeedScale *= speed.Float()
+	}
+	if shoot := result.Get("shootScale"); shoot.Type() == js.TypeNumber {
+		for _, invader := range gs.Invaders {
+			invader.ShootChance *= shoot.Float()
+		}
+	}
+}
+
+// OnInvaderKilled passes the kill to the script
+func (s *JSScript) OnInvaderKilled(gs *game.GameState, event game.Event) {
+	s.call("onInvaderKilled", map[string]any{
+		"x":      event.Position.X,
+		"y":      event.Position.Y,
+		"type":   int(event.Invader),
+		"points": event.Points,
+		"wave":   event.Wave,
+		"player": event.Player,
+	})
+}
+
+// MoveInvader places a detached invader where the script says
+func (s *JSScript) MoveInvader(gs *game.GameState, invader *game.Invader, deltaTime float64) bool {
+	result, ok := s.call("moveInvader", invaderObject(invader), deltaTime)
+	if !ok || result.Type() != js.TypeObject {
+		return false
+	}
+	x, y := result.Get("x"), result.Get("y")
+	if x.Type() != js.TypeNumber || y.Type() != js.TypeNumber {
+		return false
+	}
+	invader.Position.X = x.Float()
+	invader.Position.Y = y.Float()
+	return true
+}
+
+// SteerBoss applies the velocity and attack pattern the script picks
+func (s *JSScript) SteerBoss(gs *game.GameState, boss *game.Boss, deltaTime float64) {
+	result, ok := s.call("steerBoss", map[string]any{
+		"x":       boss.Position.X,
+		"y":       boss.Position.Y,
+		"vx":      boss.Velocity.X,
+		"vy":      boss.Velocity.Y,
+		"health":  boss.HealthFraction(),
+		"pattern": int(boss.Pattern),
+	}, deltaTime)
+	if !ok || result.Type() != js.TypeObject {
+		return
+	}
+
+	if vx := result.Get("vx"); vx.Type() == js.TypeNumber {
+		boss.Velocity.X = vx.Float()
+	}
+	if vy := result.Get("vy"); vy.Type() == js.TypeNumber {
+		boss.Velocity.Y = vy.Float()
+	}
+	if pattern := result.Get("pattern"); pattern.Type() == js.TypeNumber && pattern.Int() >= 0 && pattern.Int() <= int(game.BossPatternBarrage) {
+		boss.Pattern = game.BossAttackPattern(pattern.Int())
+	}
+}
+
+// invaderObject describes an invader to the script
+func invaderObject(invader *game.Invader) map[string]any {
+	return map[string]any{
+		"id":      float64(invader.ID),
+		"type":    int(invader.Type),
+		"x":       invader.Position.X,
+		"y":       invader.Position.Y,
+		"homeX":   invader.Home.X,
+		"homeY":   invader.Home.Y,
+		"targetX": invader.DiveTarget,
+		"state":   int(invader.MoveState),
+	}
+}
@@ -0,0 +1,201 @@
+package wasm
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// star is one point of light in a starfield layer, in playfield units
+type star struct {
+	x, y float64
+}
+
+// starGroup is a share of a layer's stars that twinkle together. The group
+// is drawn once to a tile and the tile is faded in and out, so twinkling
+// costs an image draw per group instead of a fill per star. Each group has
+// its own rate and phase so the field never pulses in step.
+type starGroup struct {
+	stars   []star
+	twinkle float64 // radians per second
+	phase   float64
+	tile    canvasLayer
+}
+
+// twinkleGroups is how many groups each layer's stars are split between
+const twinkleGroups = 3
+
+// shootingStar is a streak across the sky, in playfield units
+type shootingStar struct {
+	active   bool
+	x, y     float64
+	vx, vy   float64
+	life     float64 // seconds left
+	lifespan float64
+}
+
+// Shooting star tuning
+const (
+	shootingStarGapMin = 3.0 // seconds between shooting stars, at least
+	shootingStarGapMax = 8.0
+	shootingStarSpeed  = 420.0
+	shootingStarTail   = 0.08 // seconds of travel the tail trails behind
+)
+
+// starLayer is a band of stars at one depth. Nearer layers hold fewer,
+// bigger, brighter stars and drift faster, which gives the backdrop depth.
+type starLayer struct {
+	groups []starGroup
+	speed  float64 // downward drift, in playfield units per second
+	size   float64
+	alpha  float64
+}
+
+// starLayers sets out the depths from farthest to nearest
+var starLayers = []struct {
+	count int
+	speed float64
+	size  float64
+	alpha float64
+}{
+	{count: 60, speed: 4, size: 1, alpha: 0.35},
+	{count: 30, speed: 10, size: 1, alpha: 0.6},
+	{count: 12, speed: 22, size: 2, alpha: 0.9},
+}
+
+// Starfield is the drifting background behind every screen. The stars are
+// placed once per session and drawn to tiles once per theme, so drawing a
+// frame allocates nothing.
+type Starfield struct {
+	layers []starLayer
+	width  float64
+	height float64
+	start  time.Time
+	rng    *rand.Rand
+	color  string // the color the tiles were drawn in
+
+	shooting  shootingStar
+	nextShot  float64 // render clock time the next shooting star appears
+	lastFrame float64
+}
+
+// NewStarfield scatters stars over a playfield of the given size, placed
+// by the seed
+func NewStarfield(width, height float64, seed int64) *Starfield {
+	rng := rand.New(rand.NewSource(seed))
+	field := &Starfield{width: width, height: height, start: time.Now(), rng: rng}
+	for _, depth := range starLayers {
+		layer := starLayer{speed: depth.speed, size: depth.size, alpha: depth.alpha}
+		layer.groups = make([]starGroup, twinkleGroups)
+		for i := range layer.groups {
+			layer.groups[i].twinkle = 1 + rng.Float64()*3
+			layer.groups[i].phase = rng.Float64() * 2 * math.Pi
+		}
+		for i := range depth.count {
+			group := &layer.groups[i%twinkleGroups]
+			group.stars = append(group.stars, star{x: rng.Float64() * width, y: rng.Float64() * height})
+		}
+		field.layers = append(field.layers, layer)
+	}
+	field.nextShot = field.shotGap()
+	return field
+}
+
+// shotGap returns a random wait before the next shooting star
+func (f *Starfield) shotGap() float64 {
+	return shootingStarGapMin + f.rng.Float64()*(shootingStarGapMax-shootingStarGapMin)
+}
+
+// invalidate has the tiles redrawn before they're next shown
+func (f *Starfield) invalidate() {
+	for i := range f.layers {
+		for j := range f.layers[i].groups {
+			f.layers[i].groups[j].tile.invalidate()
+		}
+	}
+}
+
+// Draw draws the stars where they have drifted to, wrapping around the
+// bottom of the playfield, in the given color
+func (f *Starfield) Draw(r *Renderer, color string) {
+	if color != f.color {
+		f.color = color
+		f.invalidate()
+	}
+
+	// Tiles are drawn at playfield resolution; the stars are solid squares,
+	// so scaling them up unsmoothed loses nothing
+	elapsed := time.Since(f.start).Seconds()
+	for i := range f.layers {
+		layer := &f.layers[i]
+		offset := math.Mod(elapsed*layer.speed, f.height)
+		for j := range layer.groups {
+			group := &layer.groups[j]
+			if r.layerStale(&group.tile, int(f.width), int(f.height)) {
+				r.redrawLayer(&group.tile, func() { f.drawGroup(r, group, layer.size) })
+			}
+			brightness := 0.65 + 0.35*math.Sin(elapsed*group.twinkle+group.phase)
+			r.ctx.Set("globalAlpha", layer.alpha*brightness)
+			r.blitLayer(&group.tile, offset)
+			r.blitLayer(&group.tile, offset-f.height)
+		}
+	}
+	r.ctx.Set("globalAlpha", 1.0)
+}
+
+// drawGroup draws a group's stars to its tile
+func (f *Starfield) drawGroup(r *Renderer, group *starGroup, size float64) {
+	r.ctx.Set("fillStyle", f.color)
+	for _, s := range group.stars {
+		r.ctx.Call("fillRect", s.x, s.y, size, size)
+	}
+}
+
+// DrawShootingStar now and then streaks a star across the sky, moving it
+// along by the render clock
+func (f *Starfield) DrawShootingStar(r *Renderer, color string) {
+	now := time.Since(f.start).Seconds()
+	dt := math.Min(now-f.lastFrame, 0.1)
+	f.lastFrame = now
+
+	shot := &f.shooting
+	if !shot.active {
+		if now < f.nextShot {
+			return
+		}
+
+		// Start near the top and streak down and across
+		direction := 1.0
+		if f.rng.Intn(2) == 0 {
+			direction = -1
+		}
+		angle := math.Pi/8 + f.rng.Float64()*math.Pi/8
+		*shot = shootingStar{
+			active:   true,
+			x:        f.rng.Float64() * f.width,
+			y:        f.rng.Float64() * f.height / 3,
+			vx:       direction * math.Cos(angle) * shootingStarSpeed,
+			vy:       math.Sin(angle) * shootingStarSpeed,
+			lifespan: 0.6 + f.rng.Float64()*0.4,
+		}
+		shot.life = shot.lifespan
+	}
+
+	shot.x += shot.vx * dt
+	shot.y += shot.vy * dt
+	shot.life -= dt
+	if shot.life <= 0 {
+		shot.active = false
+		f.nextShot = now + f.shotGap()
+		return
+	}
+
+	r.ctx.Set("globalAlpha", shot.life/shot.lifespan)
+	r.ctx.Set("strokeStyle", color)
+	r.ctx.Set("lineWidth", 1.5)
+	r.ctx.Call("beginPath")
+	r.ctx.Call("moveTo", shot.x, shot.y)
+	r.ctx.Call("lineTo", shot.x-shot.vx*shootingStarTail, shot.y-shot.vy*shootingStarTail)
+	r.ctx.Call("stroke")
+	r.ctx.Set("globalAlpha", 1.0)
+}
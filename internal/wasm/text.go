@@ -0,0 +1,83 @@
+package wasm
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// TextStyle is how a piece of text is drawn. The zero value of each effect
+// turns it off.
+type TextStyle struct {
+	Size       int
+	Color      string
+	Align      string  // "left", "center" or "right"; centered when empty
+	Outline    string  // color of a stroke around each letter
+	Shadow     string  // color of a drop shadow below and to the right
+	Blink      float64 // times a second the text blinks off and on
+	Typewriter float64 // letters typed out a second after the screen appears
+}
+
+// drawStyledText renders text in the given style
+func (r *Renderer) drawStyledText(text string, x, y int, style TextStyle) {
+	if !r.ctx.Truthy() {
+		return
+	}
+	if style.Blink > 0 && !blinkOn(style.Blink) {
+		return
+	}
+	if style.Typewriter > 0 {
+		letters := []rune(text)
+		typed := int(r.transitions.ScreenTime() * style.Typewriter)
+		if typed < len(letters) {
+			text = string(letters[:max(typed, 0)])
+		}
+	}
+	align := style.Align
+	if align == "" {
+		align = "center"
+	}
+
+	// Use a fallback font that's guaranteed to work
+	r.ctx.Set("font", fmt.Sprintf("%dpx monospace", style.Size))
+	r.ctx.Set("textAlign", align)
+	r.ctx.Set("textBaseline", "middle")
+
+	if style.Shadow != "" {
+		offset := math.Max(1, float64(style.Size)/12)
+		r.ctx.Set("fillStyle", style.Shadow)
+		r.ctx.Call("fillText", text, float64(x)+offset, float64(y)+offset)
+	}
+	if style.Outline != "" {
+		r.ctx.Set("strokeStyle", style.Outline)
+		r.ctx.Set("lineWidth", math.Max(2, float64(style.Size)/10))
+		r.ctx.Set("lineJoin", "round")
+		r.ctx.Call("strokeText", text, x, y)
+	}
+	r.ctx.Set("fillStyle", style.Color)
+	r.ctx.Call("fillText", text, x, y)
+}
+
+// drawText renders plain text
+func (r *Renderer) drawText(text string, x, y int, size int, color, align string) {
+	r.drawStyledText(text, x, y, TextStyle{Size: size, Color: color, Align: align})
+}
+
+// blinkOn reports whether text blinking at the given rate is showing. Every
+// blinking text shares the wall clock, so prompts blink in step.
+func blinkOn(rate float64) bool {
+	return int(float64(time.Now().UnixMilli())*rate*2/1000)%2 == 0
+}
+
+// promptBlink is how fast "press a key" prompts blink
+const promptBlink = 1.0
+
+// headingStyle is the style of a screen's heading
+func (r *Renderer) headingStyle(size int, color string) TextStyle {
+	return TextStyle{Size: size, Color: color, Shadow: r.theme.Shadow, Outline: r.theme.Dim}
+}
+
+// promptStyle is the style of a blinking "press a key" prompt
+func (r *Renderer) promptStyle(size int, color string) TextStyle {
+	return TextStyle{Size: size, Color: color, Blink: promptBlink}
+}
@@ -0,0 +1,133 @@
+package wasm
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/jonasrmichel/bobn/pkg/game"
+)
+
+// Theme is every color the renderer draws with, as CSS color strings
+type Theme struct {
+	// Backdrop
+	Background string `json:"background"`
+	Stars      string `json:"stars"`
+
+	// Text and interface
+	Text    string `json:"text"`    // ordinary text
+	Dim     string `json:"dim"`     // inactive entries
+	Title   string `json:"title"`   // the game's name
+	Heading string `json:"heading"` // screen headings and status
+	Prompt  string `json:"prompt"`  // instructions and highlights
+	Accent  string `json:"accent"`  // calls to action and combos
+	Warning string `json:"warning"` // options and smart bombs
+	Danger  string `json:"danger"`  // failures, overheating and game over
+	Good    string `json:"good"`    // selections and success
+	Shadow  string `json:"shadow"`  // panels behind text, and eyes
+	Flash   string `json:"flash"`   // things flashing when hit, and hot cores
+
+	// The player
+	Player     string `json:"player"`
+	Cockpit    string `json:"cockpit"`
+	Shield     string `json:"shield"`
+	ShieldEdge string `json:"shieldEdge"`
+	PlayerShot string `json:"playerShot"`
+	Laser      string `json:"laser"`
+	Homing     string `json:"homing"`
+	Beam       string `json:"beam"`
+	PowerUp    string `json:"powerUp"` // triple shot, as a pickup and on the HUD
+	Ghost      string `json:"ghost"`
+
+	// Enemies
+	InvaderSmall      string `json:"invaderSmall"`
+	InvaderMedium     string `json:"invaderMedium"`
+	InvaderLarge      string `json:"invaderLarge"`
+	Kamikaze          string `json:"kamikaze"`
+	EnemyShot         string `json:"enemyShot"`
+	UFO               string `json:"ufo"`
+	UFODome           string `json:"ufoDome"`
+	UFOLight          string `json:"ufoLight"` // unlit; lit lights flash
+	Boss              string `json:"boss"`
+	WeakPoint         string `json:"weakPoint"`
+	WeakPointHurt     string `json:"weakPointHurt"`
+	WeakPointCritical string `json:"weakPointCritical"`
+	WeakPointDead     string `json:"weakPointDead"`
+
+	// The field
+	Barrier      string `json:"barrier"`
+	Debris       string `json:"debris"`
+	DebrisCrater string `json:"debrisCrater"`
+	PickupPoints string `json:"pickupPoints"`
+	PickupShield string `json:"pickupShield"`
+	Explosion    string `json:"explosion"`
+	Exhaust      string `json:"exhaust"`
+	Trail        string `json:"trail"`
+}
+
+// invader returns the color of an invader type
+func (t Theme) invader(invaderType game.InvaderType) string {
+	switch invaderType {
+	case game.InvaderTypeSmall:
+		return t.InvaderSmall
+	case game.InvaderTypeMedium:
+		return t.InvaderMedium
+	case game.InvaderTypeLarge:
+		return t.InvaderLarge
+	case game.InvaderTypeKamikaze:
+		return t.Kamikaze
+	default:
+		return t.Text
+	}
+}
+
+//go:embed themes/*.json
+var themeFiles embed.FS
+
+// defaultTheme is the theme used for anything a theme leaves out
+const defaultTheme = "classic"
+
+// themes holds the built-in themes, one for each of game.Themes
+var themes = loadThemes()
+
+// loadThemes parses the built-in themes
+func loadThemes() map[string]Theme {
+	loaded := make(map[string]Theme)
+	base, err := themeFiles.ReadFile(path.Join("themes", defaultTheme+".json"))
+	if err != nil {
+		panic(err)
+	}
+	var fallback Theme
+	if err := json.Unmarshal(base, &fallback); err != nil {
+		panic(fmt.Sprintf("failed to parse %s theme: %v", defaultTheme, err))
+	}
+
+	entries, err := themeFiles.ReadDir("themes")
+	if err != nil {
+		panic(err)
+	}
+	for _, entry := range entries {
+		data, err := themeFiles.ReadFile(path.Join("themes", entry.Name()))
+		if err != nil {
+			panic(err)
+		}
+		theme, err := ParseTheme(data, fallback)
+		if err != nil {
+			panic(fmt.Sprintf("failed to parse %s: %v", entry.Name(), err))
+		}
+		loaded[strings.TrimSuffix(entry.Name(), ".json")] = theme
+	}
+	return loaded
+}
+
+// ParseTheme parses a theme from JSON. Colors the theme leaves out are
+// taken from the fallback.
+func ParseTheme(data []byte, fallback Theme) (Theme, error) {
+	theme := fallback
+	if err := json.Unmarshal(data, &theme); err != nil {
+		return fallback, err
+	}
+	return theme, nil
+}
@@ -0,0 +1,119 @@
+package wasm
+
+import (
+	"time"
+
+	"github.com/jonasrmichel/bobn/pkg/game"
+)
+
+// scene is a screen the transitions animate between
+type scene int
+
+const (
+	sceneNone scene = iota // screens that switch instantly, like menus
+	sceneAttract
+	scenePlaying
+	sceneIntermission
+	sceneGameOver
+)
+
+// sceneOf returns the scene the state shows
+func sceneOf(state *game.GameState) scene {
+	switch state.Mode {
+	case game.AttractMode:
+		return sceneAttract
+	case game.Playing, game.Continue:
+		if state.WaveCleared {
+			return sceneIntermission
+		}
+		return scenePlaying
+	case game.GameOver:
+		return sceneGameOver
+	default:
+		return sceneNone
+	}
+}
+
+// transitionStyle is how a new scene is revealed
+type transitionStyle int
+
+const (
+	transitionFade transitionStyle = iota // in from the background color
+	transitionWipe                        // a curtain drawn off to the side
+)
+
+// transitionDuration is how long revealing a scene takes, in seconds
+const transitionDuration = 0.5
+
+// Transitions reveals each new scene gradually instead of popping straight
+// to it. The renderer only ever sees the current state, so a transition
+// covers the new scene and uncovers it, rather than blending from the old.
+type Transitions struct {
+	scene  scene
+	style  transitionStyle
+	start  time.Time
+	active bool
+
+	// The screen on show and when it appeared, menus included
+	mode    game.GameMode
+	cleared bool
+	shown   time.Time
+}
+
+// Update notices when the state moves to another scene and starts revealing
+// it. Menus and other screens outside the animated scenes switch instantly.
+func (t *Transitions) Update(state *game.GameState) {
+	if t.shown.IsZero() || state.Mode != t.mode || state.WaveCleared != t.cleared {
+		t.mode, t.cleared = state.Mode, state.WaveCleared
+		t.shown = time.Now()
+	}
+
+	next := sceneOf(state)
+	if next == t.scene {
+		return
+	}
+	previous := t.scene
+	t.scene = next
+	if previous == sceneNone || next == sceneNone {
+		t.active = false
+		return
+	}
+
+	t.style = transitionFade
+	if previous == sceneIntermission || next == sceneIntermission {
+		t.style = transitionWipe
+	}
+	t.start = time.Now()
+	t.active = true
+}
+
+// ScreenTime returns how long the current screen has been showing, in seconds
+func (t *Transitions) ScreenTime() float64 {
+	if t.shown.IsZero() {
+		return 0
+	}
+	return time.Since(t.shown).Seconds()
+}
+
+// Draw covers whatever of the new scene hasn't been revealed yet
+func (t *Transitions) Draw(r *Renderer) {
+	if !t.active {
+		return
+	}
+	progress := time.Since(t.start).Seconds() / transitionDuration
+	if progress >= 1 {
+		t.active = false
+		return
+	}
+
+	width, height := float64(r.screenWidth), float64(r.screenHeight)
+	r.ctx.Set("fillStyle", r.theme.Background)
+	switch t.style {
+	case transitionFade:
+		r.ctx.Set("globalAlpha", 1-progress)
+		r.ctx.Call("fillRect", 0, 0, width, height)
+		r.ctx.Set("globalAlpha", 1.0)
+	case transitionWipe:
+		r.ctx.Call("fillRect", width*progress, 0, width*(1-progress), height)
+	}
+}
@@ -0,0 +1,300 @@
+package wasm
+
+import (
+	"encoding/binary"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"syscall/js"
+
+	"github.com/jonasrmichel/bobn/assets"
+	"github.com/jonasrmichel/bobn/pkg/game"
+)
+
+// Batch sizing. Each quad is two triangles of six vertices; each vertex is
+// a position, a sprite sheet coordinate and a color, all 32-bit floats.
+const (
+	maxBatchQuads = 2048
+	quadVertices  = 6
+	vertexFloats  = 8
+	vertexBytes   = vertexFloats * 4
+	quadBytes     = quadVertices * vertexBytes
+)
+
+// quadVertexShader places vertices given in playfield units
+const quadVertexShader = `#version 300 es
+in vec2 a_position;
+in vec2 a_uv;
+in vec4 a_color;
+uniform vec2 u_view;
+out vec2 v_uv;
+out vec4 v_color;
+void main() {
+	vec2 clip = a_position / u_view * 2.0 - 1.0;
+	gl_Position = vec4(clip.x, -clip.y, 0.0, 1.0);
+	v_uv = a_uv;
+	v_color = a_color;
+}`
+
+// quadFragmentShader samples the sheet, or fills with the color when the
+// sheet coordinate is negative. Output is premultiplied, as the canvas
+// expects.
+const quadFragmentShader = `#version 300 es
+precision mediump float;
+uniform sampler2D u_sheet;
+in vec2 v_uv;
+in vec4 v_color;
+out vec4 outColor;
+void main() {
+	if (v_uv.x < 0.0) {
+		outColor = vec4(v_color.rgb * v_color.a, v_color.a);
+	} else {
+		outColor = texture(u_sheet, v_uv) * v_color.a;
+	}
+}`
+
+// glBatch queues quads and draws them with WebGL2 onto an offscreen canvas
+// the size of the playfield on the main one, which is then laid over the frame in a single
+// image draw. The sprite sheet is the one texture, so a whole batch of
+// sprites and rectangles draws in one call however many there are.
+type glBatch struct {
+	r       *Renderer
+	canvas  js.Value
+	gl      js.Value
+	buffer  js.Value
+	texture js.Value
+	view    js.Value // the u_view uniform
+
+	// The sheet uploaded to the texture, and its size in pixels
+	sheet         js.Value
+	sheetWidth    float64
+	sheetHeight   float64
+	sheetUploaded bool
+
+	vertices []byte   // queued vertex data, little-endian like typed arrays
+	upload   js.Value // a Uint8Array the vertex data is copied through
+	quads    int
+
+	colors map[string][4]float32 // parsed CSS colors
+}
+
+// newGLBatch sets up WebGL2 drawing, returning nil when the browser doesn't
+// support it so the renderer can fall back to the 2D canvas
+func newGLBatch(r *Renderer) *glBatch {
+	canvas := r.bridge.NewCanvas(game.PlayfieldWidth, game.PlayfieldHeight)
+	gl := canvas.Call("getContext", "webgl2", map[string]any{"premultipliedAlpha": true, "antialias": false})
+	if !gl.Truthy() {
+		return nil
+	}
+
+	program, err := linkQuadProgram(gl)
+	if err != nil {
+		log.Printf("WebGL unavailable, drawing with the 2D canvas: %v", err)
+		return nil
+	}
+	gl.Call("useProgram", program)
+
+	b := &glBatch{
+		r:        r,
+		canvas:   canvas,
+		gl:       gl,
+		view:     gl.Call("getUniformLocation", program, "u_view"),
+		vertices: make([]byte, 0, maxBatchQuads*quadBytes),
+		upload:   js.Global().Get("Uint8Array").New(maxBatchQuads * quadBytes),
+		colors:   make(map[string][4]float32),
+	}
+
+	b.buffer = gl.Call("createBuffer")
+	gl.Call("bindBuffer", gl.Get("ARRAY_BUFFER"), b.buffer)
+	gl.Call("bufferData", gl.Get("ARRAY_BUFFER"), maxBatchQuads*quadBytes, gl.Get("DYNAMIC_DRAW"))
+	for _, attribute := range []struct {
+		name   string
+		size   int
+		offset int
+	}{
+		{"a_position", 2, 0},
+		{"a_uv", 2, 8},
+		{"a_color", 4, 16},
+	} {
+		location := gl.Call("getAttribLocation", program, attribute.name)
+		gl.Call("enableVertexAttribArray", location)
+		gl.Call("vertexAttribPointer", location, attribute.size, gl.Get("FLOAT"), false, vertexBytes, attribute.offset)
+	}
+
+	b.texture = gl.Call("createTexture")
+	gl.Call("bindTexture", gl.Get("TEXTURE_2D"), b.texture)
+	for _, parameter := range []string{"TEXTURE_MIN_FILTER", "TEXTURE_MAG_FILTER"} {
+		gl.Call("texParameteri", gl.Get("TEXTURE_2D"), gl.Get(parameter), gl.Get("NEAREST"))
+	}
+	for _, parameter := range []string{"TEXTURE_WRAP_S", "TEXTURE_WRAP_T"} {
+		gl.Call("texParameteri", gl.Get("TEXTURE_2D"), gl.Get(parameter), gl.Get("CLAMP_TO_EDGE"))
+	}
+	gl.Call("pixelStorei", gl.Get("UNPACK_PREMULTIPLY_ALPHA_WEBGL"), true)
+
+	gl.Call("enable", gl.Get("BLEND"))
+	gl.Call("blendFunc", gl.Get("ONE"), gl.Get("ONE_MINUS_SRC_ALPHA"))
+	gl.Call("clearColor", 0, 0, 0, 0)
+	log.Println("Drawing sprites with WebGL2")
+	return b
+}
+
+// linkQuadProgram compiles and links the quad shaders
+func linkQuadProgram(gl js.Value) (js.Value, error) {
+	program := gl.Call("createProgram")
+	for _, shader := range []struct {
+		kind   string
+		source string
+	}{
+		{"VERTEX_SHADER", quadVertexShader},
+		{"FRAGMENT_SHADER", quadFragmentShader},
+	} {
+		compiled := gl.Call("createShader", gl.Get(shader.kind))
+		gl.Call("shaderSource", compiled, shader.source)
+		gl.Call("compileShader", compiled)
+		if !gl.Call("getShaderParameter", compiled, gl.Get("COMPILE_STATUS")).Bool() {
+			return js.Value{}, fmt.Errorf("failed to compile %s: %s", shader.kind, gl.Call("getShaderInfoLog", compiled).String())
+		}
+		gl.Call("attachShader", program, compiled)
+	}
+	gl.Call("linkProgram", program)
+	if !gl.Call("getProgramParameter", program, gl.Get("LINK_STATUS")).Bool() {
+		return js.Value{}, fmt.Errorf("failed to link shaders: %s", gl.Call("getProgramInfoLog", program).String())
+	}
+	return program, nil
+}
+
+// begin matches the offscreen canvas to the playfield on the main one and
+// clears it
+func (b *glBatch) begin() {
+	gl := b.gl
+	width, height := b.r.playfieldPixels()
+	if b.canvas.Get("width").Int() != width || b.canvas.Get("height").Int() != height {
+		b.canvas.Set("width", width)
+		b.canvas.Set("height", height)
+	}
+	gl.Call("viewport", 0, 0, width, height)
+	gl.Call("uniform2f", b.view, b.r.screenWidth, b.r.screenHeight)
+	gl.Call("clear", gl.Get("COLOR_BUFFER_BIT"))
+	b.vertices = b.vertices[:0]
+	b.quads = 0
+}
+
+// sprite queues a sprite, flipped and faded to match what the canvas would
+// do with its current transform and opacity
+func (b *glBatch) sprite(frame assets.Frame, bounds game.Bounds) {
+	if !b.uploadSheet() {
+		return
+	}
+
+	u0 := float64(frame.X) / b.sheetWidth
+	u1 := float64(frame.X+frame.Width) / b.sheetWidth
+	v0 := float64(frame.Y) / b.sheetHeight
+	v1 := float64(frame.Y+frame.Height) / b.sheetHeight
+	if flip := b.r.flip; flip.on {
+		bounds.Y = 2*flip.y - bounds.Y - bounds.Height
+		v0, v1 = v1, v0
+	}
+	alpha := b.r.ctx.Get("globalAlpha").Float()
+	b.quad(bounds.X, bounds.Y, bounds.Width, bounds.Height, u0, v0, u1, v1, [4]float32{1, 1, 1, float32(alpha)})
+}
+
+// rect queues a solid rectangle
+func (b *glBatch) rect(x, y, width, height float64, color string, alpha float64) {
+	rgba, ok := b.colors[color]
+	if !ok {
+		rgba = parseHexColor(color)
+		b.colors[color] = rgba
+	}
+	rgba[3] *= float32(alpha)
+	b.quad(x, y, width, height, -1, -1, -1, -1, rgba)
+}
+
+// quad queues the two triangles covering a rectangle, flushing first if the
+// batch is full
+func (b *glBatch) quad(x, y, width, height, u0, v0, u1, v1 float64, color [4]float32) {
+	if b.quads == maxBatchQuads {
+		b.flush()
+	}
+	x1, y1 := x+width, y+height
+	corners := [quadVertices][4]float64{
+		{x, y, u0, v0}, {x1, y, u1, v0}, {x, y1, u0, v1},
+		{x, y1, u0, v1}, {x1, y, u1, v0}, {x1, y1, u1, v1},
+	}
+	for _, corner := range corners {
+		for _, value := range corner {
+			b.vertices = binary.LittleEndian.AppendUint32(b.vertices, math.Float32bits(float32(value)))
+		}
+		for _, value := range color {
+			b.vertices = binary.LittleEndian.AppendUint32(b.vertices, math.Float32bits(value))
+		}
+	}
+	b.quads++
+}
+
+// flush draws the queued quads and lays them over the frame
+func (b *glBatch) flush() {
+	if b.quads == 0 {
+		return
+	}
+	gl := b.gl
+	js.CopyBytesToJS(b.upload, b.vertices)
+	gl.Call("bufferSubData", gl.Get("ARRAY_BUFFER"), 0, b.upload, 0, len(b.vertices))
+	gl.Call("drawArrays", gl.Get("TRIANGLES"), 0, b.quads*quadVertices)
+
+	// Lay the batch over the frame through the frame's transform, so it
+	// shakes with everything else but isn't flipped a second time
+	ctx := b.r.ctx
+	ctx.Call("save")
+	ctx.Call("setTransform", b.r.frameTransform)
+	ctx.Set("globalAlpha", 1.0)
+	ctx.Call("drawImage", b.canvas, 0, 0, b.r.screenWidth, b.r.screenHeight)
+	ctx.Call("restore")
+
+	gl.Call("clear", gl.Get("COLOR_BUFFER_BIT"))
+	b.vertices = b.vertices[:0]
+	b.quads = 0
+}
+
+// uploadSheet copies the sprite sheet into the texture the first time it's
+// needed, reporting whether it's there to draw from
+func (b *glBatch) uploadSheet() bool {
+	sheet := b.r.sprites
+	if b.sheetUploaded && sheet.Equal(b.sheet) {
+		return true
+	}
+	if !ImageReady(sheet) {
+		return false
+	}
+
+	gl := b.gl
+	gl.Call("bindTexture", gl.Get("TEXTURE_2D"), b.texture)
+	gl.Call("texImage2D", gl.Get("TEXTURE_2D"), 0, gl.Get("RGBA"), gl.Get("RGBA"), gl.Get("UNSIGNED_BYTE"), sheet)
+	b.sheet = sheet
+	b.sheetWidth = sheet.Get("naturalWidth").Float()
+	b.sheetHeight = sheet.Get("naturalHeight").Float()
+	b.sheetUploaded = true
+	return true
+}
+
+// parseHexColor reads a #rgb or #rrggbb color, as the themes use, into
+// components from 0 to 1. Anything else comes out white.
+func parseHexColor(color string) [4]float32 {
+	digits := color
+	if len(digits) > 0 && digits[0] == '#' {
+		digits = digits[1:]
+	}
+	if len(digits) == 3 {
+		digits = string([]byte{digits[0], digits[0], digits[1], digits[1], digits[2], digits[2]})
+	}
+	value, err := strconv.ParseUint(digits, 16, 32)
+	if len(digits) != 6 || err != nil {
+		return [4]float32{1, 1, 1, 1}
+	}
+	return [4]float32{
+		float32(value>>16&0xff) / 255,
+		float32(value>>8&0xff) / 255,
+		float32(value&0xff) / 255,
+		1,
+	}
+}
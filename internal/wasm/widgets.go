@@ -0,0 +1,146 @@
+package wasm
+
+// The menus are built from a few immediate-mode widgets drawn straight onto
+// the canvas. Each call draws one control as the engine's state has it now,
+// so a screen lays itself out afresh every frame and keyboard, gamepad and
+// head pointing all steer it through the same selection.
+
+// Widget sizing, in playfield units
+const (
+	widgetTextSize = 20
+	widgetRowWidth = 360 // from a row's label to the far edge of its value
+	sliderWidth    = 100
+	sliderHeight   = 8
+	toggleWidth    = 36
+	toggleHeight   = 16
+	letterBoxSize  = 40
+	letterSpacing  = 52
+)
+
+// widgetColor is the color of a control, picked out when it's selected
+func (r *Renderer) widgetColor(selected bool) string {
+	if selected {
+		return r.theme.Good
+	}
+	return r.theme.Text
+}
+
+// drawShade darkens the frame behind a menu laid over it
+func (r *Renderer) drawShade() {
+	r.ctx.Set("globalAlpha", 0.7)
+	r.ctx.Set("fillStyle", r.theme.Shadow)
+	r.ctx.Call("fillRect", 0, 0, r.screenWidth, r.screenHeight)
+	r.ctx.Set("globalAlpha", 1.0)
+}
+
+// drawHint draws the line explaining a screen's controls
+func (r *Renderer) drawHint(text string, y int) {
+	r.drawText(text, r.screenWidth/2, y, 12, r.theme.Prompt, "center")
+}
+
+// drawMenuList draws a column of centered entries, marking the selected one
+func (r *Renderer) drawMenuList(items []string, selected, y, spacing int) {
+	for i, item := range items {
+		if i == selected {
+			item = "> " + item + " <"
+		}
+		r.drawText(item, r.screenWidth/2, y+i*spacing, widgetTextSize, r.widgetColor(i == selected), "center")
+	}
+}
+
+// rowEdges returns the left and right edges of a settings row
+func (r *Renderer) rowEdges() (left, right int) {
+	return r.screenWidth/2 - widgetRowWidth/2, r.screenWidth/2 + widgetRowWidth/2
+}
+
+// drawButtonRow draws a row with nothing to adjust, such as BACK
+func (r *Renderer) drawButtonRow(label string, selected bool, y int) {
+	r.drawText(label, r.screenWidth/2, y, widgetTextSize, r.widgetColor(selected), "center")
+}
+
+// drawChoiceRow draws a row that steps through a list of choices, with
+// arrows around the value while it's selected
+func (r *Renderer) drawChoiceRow(label, value string, selected bool, y int) {
+	left, right := r.rowEdges()
+	color := r.widgetColor(selected)
+	if selected {
+		value = "< " + value + " >"
+	}
+	r.drawText(label, left, y, widgetTextSize, color, "left")
+	r.drawText(value, right, y, widgetTextSize, color, "right")
+}
+
+// drawSliderRow draws a row with a range as a bar filled to its level,
+// from 0 to 1, with the value beside it
+func (r *Renderer) drawSliderRow(label, value string, level float64, selected bool, y int) {
+	left, right := r.rowEdges()
+	color := r.widgetColor(selected)
+	r.drawText(label, left, y, widgetTextSize, color, "left")
+
+	x := float64(right - sliderWidth)
+	top := float64(y) - sliderHeight/2
+	r.ctx.Set("fillStyle", r.theme.Dim)
+	r.ctx.Call("fillRect", x, top, sliderWidth, sliderHeight)
+	r.ctx.Set("fillStyle", color)
+	r.ctx.Call("fillRect", x, top, sliderWidth*max(0, min(level, 1)), sliderHeight)
+	if selected {
+		r.ctx.Set("strokeStyle", color)
+		r.ctx.Set("lineWidth", 1)
+		r.ctx.Call("strokeRect", x-2, top-2, sliderWidth+4, sliderHeight+4)
+	}
+
+	r.drawText(value, right-sliderWidth-12, y, widgetTextSize-4, color, "right")
+}
+
+// drawToggleRow draws a row with an on/off switch
+func (r *Renderer) drawToggleRow(label string, on, selected bool, y int) {
+	left, right := r.rowEdges()
+	color := r.widgetColor(selected)
+	r.drawText(label, left, y, widgetTextSize, color, "left")
+
+	x := float64(right - toggleWidth)
+	top := float64(y) - toggleHeight/2
+	track, state := r.theme.Dim, "OFF"
+	if on {
+		track, state = color, "ON"
+	}
+	r.ctx.Set("fillStyle", track)
+	r.ctx.Call("fillRect", x, top, toggleWidth, toggleHeight)
+	knob := x + 2
+	if on {
+		knob = x + toggleWidth/2
+	}
+	r.ctx.Set("fillStyle", r.theme.Shadow)
+	r.ctx.Call("fillRect", knob, top+2, toggleWidth/2-2, toggleHeight-4)
+	if selected {
+		r.ctx.Set("strokeStyle", color)
+		r.ctx.Set("lineWidth", 1)
+		r.ctx.Call("strokeRect", x-2, top-2, toggleWidth+4, toggleHeight+4)
+	}
+
+	r.drawText(state, right-toggleWidth-12, y, widgetTextSize-4, color, "right")
+}
+
+// drawLetterPicker draws letters in a row of boxes, the one under the
+// cursor picked out with arrows to show it can be changed
+func (r *Renderer) drawLetterPicker(letters []byte, cursor, y int) {
+	first := r.screenWidth/2 - (len(letters)-1)*letterSpacing/2
+	for i, letter := range letters {
+		x := first + i*letterSpacing
+		selected := i == cursor
+		color := r.widgetColor(selected)
+
+		r.ctx.Set("strokeStyle", color)
+		r.ctx.Set("lineWidth", 2)
+		r.ctx.Call("strokeRect", x-letterBoxSize/2, y-letterBoxSize/2, letterBoxSize, letterBoxSize)
+		if letter == ' ' {
+			letter = '_'
+		}
+		r.drawText(string(letter), x, y, 28, color, "center")
+
+		if selected {
+			r.drawText("▲", x, y-letterBoxSize/2-12, 14, color, "center")
+			r.drawText("▼", x, y+letterBoxSize/2+12, 14, color, "center")
+		}
+	}
+}
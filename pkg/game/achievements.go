@@ -0,0 +1,93 @@
+package game
+
+// AchievementID identifies an achievement
+type AchievementID string
+
+const (
+	AchievementPerfectWave   AchievementID = "perfect_wave"   // clear a wave without missing
+	AchievementMaxUFO        AchievementID = "max_ufo"        // hit the UFO for its top score
+	AchievementWave10        AchievementID = "wave_10"        // survive to wave 10
+	AchievementMaxCombo      AchievementID = "max_combo"      // reach the top combo multiplier
+	AchievementInvaderHunter AchievementID = "invader_hunter" // destroy 500 invaders
+)
+
+// achievementToastDuration is how long an unlock notification stays up, in seconds
+const achievementToastDuration = 3.0
+
+// Achievement describes an achievement and the progress needed to unlock it
+type Achievement struct {
+	ID          AchievementID
+	Name        string
+	Description string
+	Goal        int // progress needed; 1 for one-off feats
+}
+
+// Achievements lists every achievement in display order
+var Achievements = []Achievement{
+	{ID: AchievementPerfectWave, Name: "SHARPSHOOTER", Description: "Clear a wave without missing", Goal: 1},
+	{ID: AchievementMaxUFO, Name: "JACKPOT", Description: "Hit the UFO for 300 points", Goal: 1},
+	{ID: AchievementWave10, Name: "VETERAN", Description: "Survive to wave 10", Goal: 1},
+	{ID: AchievementMaxCombo, Name: "ON FIRE", Description: "Reach the top combo multiplier", Goal: 1},
+	{ID: AchievementInvaderHunter, Name: "EXTERMINATOR", Description: "Destroy 500 invaders", Goal: 500},
+}
+
+// FindAchievement returns the definition for the given ID
+func FindAchievement(id AchievementID) (Achievement, bool) {
+	for _, achievement := range Achievements {
+		if achievement.ID == id {
+			return achievement, true
+		}
+	}
+	return Achievement{}, false
+}
+
+// AchievementToast is an on-screen unlock notification
+type AchievementToast struct {
+	Achievement Achievement
+	Timer       float64 // seconds left on screen
+}
+
+// AdvanceAchievement adds progress toward an achievement and unlocks it once
+// the goal is reached. Only competitive play counts.
+func (gs *GameState) AdvanceAchievement(id AchievementID, amount int) {
+	if !gs.IsCompetitive() || gs.AchievementUnlocked[id] {
+		return
+	}
+	achievement, ok := FindAchievement(id)
+	if !ok {
+		return
+	}
+
+	if gs.AchievementProgress == nil {
+		gs.AchievementProgress = make(map[AchievementID]int)
+	}
+	gs.AchievementProgress[id] += amount
+	if gs.AchievementProgress[id] < achievement.Goal {
+		return
+	}
+
+	if gs.AchievementUnlocked == nil {
+		gs.AchievementUnlocked = make(map[AchievementID]bool)
+	}
+	gs.AchievementUnlocked[id] = true
+	gs.AchievementToasts = append(gs.AchievementToasts, &AchievementToast{
+		Achievement: achievement,
+		Timer:       achievementToastDuration,
+	})
+}
+
+// UnlockAchievement completes a one-off achievement
+func (gs *GameState) UnlockAchievement(id AchievementID) {
+	gs.AdvanceAchievement(id, 1)
+}
+
+// UpdateAchievementToasts shows each unlock notification in turn
+func (gs *GameState) UpdateAchievementToasts(deltaTime float64) {
+	if len(gs.AchievementToasts) == 0 {
+		return
+	}
+	gs.AchievementToasts[0].Timer -= deltaTime
+	if gs.AchievementToasts[0].Timer <= 0 {
+		gs.AchievementToasts = gs.AchievementToasts[1:]
+	}
+}
@@ -0,0 +1,70 @@
+package game
+
+// Adaptive difficulty bounds and step sizes
+const (
+	minAggression      = 0.6
+	maxAggression      = 1.5
+	aggressionStepUp   = 0.1
+	aggressionStepDown = 0.15
+)
+
+// AdaptiveDifficulty tracks player performance and scales invader aggression
+// between waves. It is optional; when disabled the aggression stays at 1.
+type AdaptiveDifficulty struct {
+	Enabled    bool
+	Aggression float64 // multiplier for formation speed and shoot chance
+
+	// Performance during the current wave
+	WaveDeaths int
+	WaveTime   float64
+}
+
+// NewAdaptiveDifficulty creates an adaptive difficulty tracker at neutral aggression
+func NewAdaptiveDifficulty(enabled bool) AdaptiveDifficulty {
+	return AdaptiveDifficulty{
+		Enabled:    enabled,
+		Aggression: 1.0,
+	}
+}
+
+// Scale returns the aggression multiplier to apply, or 1 when disabled
+func (a *AdaptiveDifficulty) Scale() float64 {
+	if !a.Enabled || a.Aggression == 0 {
+		return 1.0
+	}
+	return a.Aggression
+}
+
+// Update accumulates time spent in the current wave
+func (a *AdaptiveDifficulty) Update(deltaTime float64) {
+	a.WaveTime += deltaTime
+}
+
+// RecordDeath notes that the player lost a life this wave
+func (a *AdaptiveDifficulty) RecordDeath() {
+	a.WaveDeaths++
+}
+
+// EndWave adjusts aggression from the finished wave's performance and resets
+// the per-wave counters
+func (a *AdaptiveDifficulty) EndWave(accuracy float64) {
+	if a.Enabled {
+		struggling := a.WaveDeaths >= 2 || accuracy < 0.3 || a.WaveTime > 120
+		cruising := a.WaveDeaths == 0 && accuracy > 0.6 && a.WaveTime < 45
+
+		if struggling {
+			a.Aggression -= aggressionStepDown
+		} else if cruising {
+			a.Aggression += aggressionStepUp
+		}
+
+		if a.Aggression < minAggression {
+			a.Aggression = minAggression
+		} else if a.Aggression > maxAggression {
+			a.Aggression = maxAggression
+		}
+	}
+
+	a.WaveDeaths = 0
+	a.WaveTime = 0
+}
@@ -0,0 +1,94 @@
+package game
+
+// AnimationID names one of the built-in animation clips
+type AnimationID int
+
+const (
+	AnimNone         AnimationID = iota // no animation; always frame 0
+	AnimShipThrust                      // the ship's engine flicker
+	AnimInvaderMarch                    // invaders stepping in formation
+	AnimBossPulse                       // the boss's weak points pulsing
+	AnimPickupPulse                     // power-ups throbbing as they fall
+	AnimExplosion                       // a ship blowing up, once
+	AnimBlast                           // an enemy blowing up, once
+	AnimUFOLights                       // the UFO's lights chasing around its rim
+)
+
+// AnimationClip is a list of frames, each shown for the same time. A frame
+// changes on the first update after its time has passed, so at the fixed
+// tick rate frames last a whole number of ticks.
+type AnimationClip struct {
+	Frames    []int   // sprite frames in play order
+	FrameTime float64 // seconds each frame is shown, at least
+	Loop      bool    // start over after the last frame; otherwise hold it
+}
+
+// animationClips holds every built-in clip
+var animationClips = map[AnimationID]AnimationClip{
+	AnimShipThrust:   {Frames: []int{0, 1}, FrameTime: 0.1, Loop: true},
+	AnimInvaderMarch: {Frames: []int{0, 1}, FrameTime: 0.5, Loop: true},
+	AnimBossPulse:    {Frames: []int{0, 1}, FrameTime: 0.25, Loop: true},
+	AnimPickupPulse:  {Frames: []int{0, 1}, FrameTime: 0.15, Loop: true},
+	AnimExplosion:    {Frames: []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, FrameTime: 0.08}, // two ticks a frame, filling the death sequence
+	AnimBlast:        {Frames: []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, FrameTime: 0.03}, // a frame a tick
+	AnimUFOLights:    {Frames: []int{0, 1, 2, 3}, FrameTime: 0.1, Loop: true},
+}
+
+// Clip returns the clip the ID names
+func (id AnimationID) Clip() AnimationClip {
+	return animationClips[id]
+}
+
+// Animation plays a clip. The engine advances it with the simulation, so
+// every renderer shows the same frame and animations that matter to play,
+// like the boss's alternating barrage, stay deterministic.
+type Animation struct {
+	ID    AnimationID `json:"id"`
+	Index int         `json:"index"` // position in the clip's frame list
+	Timer float64     `json:"timer"` // seconds into the current frame
+	Done  bool        `json:"done"`  // a one-shot clip has reached its last frame
+}
+
+// NewAnimation starts the clip from its first frame
+func NewAnimation(id AnimationID) Animation {
+	return Animation{ID: id}
+}
+
+// Update advances the animation by the elapsed time
+func (a *Animation) Update(deltaTime float64) {
+	clip := a.ID.Clip()
+	if len(clip.Frames) == 0 || a.Done {
+		return
+	}
+
+	a.Timer += deltaTime
+	if a.Timer > clip.FrameTime {
+		a.Timer = 0
+		switch {
+		case a.Index+1 < len(clip.Frames):
+			a.Index++
+		case clip.Loop:
+			a.Index = 0
+		default:
+			a.Done = true
+		}
+	}
+}
+
+// Frame returns the sprite frame to show
+func (a Animation) Frame() int {
+	clip := a.ID.Clip()
+	if a.Index >= len(clip.Frames) {
+		return 0
+	}
+	return clip.Frames[a.Index]
+}
+
+// Progress returns how far through the clip the animation is, from 0 to 1
+func (a Animation) Progress() float64 {
+	clip := a.ID.Clip()
+	if a.Done || len(clip.Frames) == 0 {
+		return 1
+	}
+	return (float64(a.Index) + min(a.Timer/clip.FrameTime, 1)) / float64(len(clip.Frames))
+}
@@ -0,0 +1,59 @@
+package game
+
+// AttractScreen is one of the screens attract mode cycles through between
+// demo games, as an arcade cabinet does while nobody is playing
+type AttractScreen int
+
+const (
+	AttractTitle      AttractScreen = iota // Title and options; the demo follows it
+	AttractHighScores                      // The best scores so far
+	AttractHowToPlay                       // Controls and what each invader is worth
+	attractScreenCount
+)
+
+// String returns the name of the attract screen
+func (s AttractScreen) String() string {
+	switch s {
+	case AttractTitle:
+		return "Title"
+	case AttractHighScores:
+		return "HighScores"
+	case AttractHowToPlay:
+		return "HowToPlay"
+	default:
+		return "Unknown"
+	}
+}
+
+// attractScreenTimes is how long each attract screen shows, in seconds.
+// The title waits demoDelay before the demo starts; the other screens each
+// hand on to the next.
+var attractScreenTimes = [attractScreenCount]float64{
+	AttractTitle:      demoDelay,
+	AttractHighScores: 6.0,
+	AttractHowToPlay:  8.0,
+}
+
+// updateAttractMode moves attract mode on to its next screen, or to a demo
+// game after the title, once the current one has shown for long enough
+func (e *Engine) updateAttractMode(deltaTime float64) {
+	e.attractTimer += deltaTime
+	screen := e.state.AttractScreen
+	if e.attractTimer < attractScreenTimes[screen] {
+		return
+	}
+
+	e.attractTimer = 0
+	if screen == AttractTitle {
+		e.startDemo()
+		return
+	}
+	e.state.AttractScreen = (screen + 1) % attractScreenCount
+}
+
+// showTitle puts attract mode back on the title screen, where the options
+// show, with a full wait before the demo
+func (e *Engine) showTitle() {
+	e.state.AttractScreen = AttractTitle
+	e.attractTimer = 0
+}
@@ -0,0 +1,188 @@
+package game
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// BarrierShape is the outline each barrier is built in
+type BarrierShape string
+
+const (
+	BarrierShapeClassic BarrierShape = "classic" // Hollow block with a firing slot in the middle
+	BarrierShapeSolid   BarrierShape = "solid"   // Filled rectangle
+	BarrierShapeArch    BarrierShape = "arch"    // Arcade bunker with sloped corners and a notch underneath
+)
+
+// BarrierConfig describes the barriers laid out in front of the player
+type BarrierConfig struct {
+	Count      int          // barriers spread evenly across the screen; 0 disables them
+	Width      int          // barrier width in blocks
+	Height     int          // barrier height in blocks
+	BlockSize  float64      // size of a single block in pixels
+	Elevation  float64      // gap between the ship line and the barriers' near edge, in pixels
+	Shape      BarrierShape // outline of each barrier
+	Regenerate float64      // share of destroyed blocks rebuilt between waves, 0 to 1
+}
+
+// DefaultBarrierConfig returns the classic four-barrier layout, which never regenerates
+func DefaultBarrierConfig() BarrierConfig {
+	return BarrierConfig{
+		Count:     4,
+		Width:     22,
+		Height:    16,
+		BlockSize: 3,
+		Elevation: 32,
+		Shape:     BarrierShapeClassic,
+	}
+}
+
+// barrierGap is the fewest clear blocks kept either side of each barrier,
+// so neighbouring barriers never touch and none runs off the screen
+const barrierGap = 1
+
+// validate checks the configuration for unusable values
+func (c BarrierConfig) validate() error {
+	if c.Count < 0 {
+		return fmt.Errorf("barrier count must not be negative")
+	}
+	if c.Width <= 0 || c.Height <= 0 || c.BlockSize <= 0 {
+		return fmt.Errorf("barrier width, height and block size must be positive")
+	}
+
+	// Each barrier is centered in an equal share of the playfield's columns
+	if c.Count > 0 {
+		columns := int(PlayfieldWidth / c.BlockSize)
+		if spacing := columns / c.Count; c.Width+2*barrierGap > spacing {
+			return fmt.Errorf("%d barriers %d blocks wide don't fit across %d columns", c.Count, c.Width, columns)
+		}
+	}
+	if c.Regenerate < 0 || c.Regenerate > 1 {
+		return fmt.Errorf("barrier regeneration must be between 0 and 1")
+	}
+	switch c.Shape {
+	case BarrierShapeClassic, BarrierShapeSolid, BarrierShapeArch:
+		return nil
+	default:
+		return fmt.Errorf("unknown barrier shape %q", c.Shape)
+	}
+}
+
+// Includes reports whether the block at (x, y) of a w x h barrier is solid
+func (s BarrierShape) Includes(x, y, w, h int) bool {
+	switch s {
+	case BarrierShapeSolid:
+		return true
+	case BarrierShapeArch:
+		corner := h / 4
+		if x+y < corner || (w-1-x)+y < corner {
+			return false // Sloped top corners
+		}
+		center := float64(w-1) / 2
+		notch := float64(w) / 5
+		return !(y >= h*2/3 && float64(x) > center-notch && float64(x) < center+notch)
+	default:
+		if y < 3 || y > h-4 || x < 3 || x > w-4 {
+			return false // Leave edges open
+		}
+		return !(y > h/2 && y < h*3/4 && x > w/2-3 && x < w/2+3) // Leave center gap
+	}
+}
+
+// SetBarrierConfig changes the barrier layout used from the next game. The
+// game in progress keeps the layout it started with.
+func (e *Engine) SetBarrierConfig(config BarrierConfig) error {
+	if err := config.validate(); err != nil {
+		return err
+	}
+	e.barrierConfig = config
+	return nil
+}
+
+// initializeBarriers creates the defensive barriers
+func (gs *GameState) initializeBarriers() {
+	gs.Barriers, gs.BarrierOrigin, gs.BarrierBlockSize = gs.buildBarriers()
+}
+
+// buildBarriers lays out intact barriers, returning the grid with where it
+// sits and the size of its blocks. The grid spans the full screen width so
+// block indices map directly to world coordinates, with its near edge held
+// a fixed distance above the ship line.
+func (gs *GameState) buildBarriers() (barriers [][]bool, origin Vector2, blockSize float64) {
+	config := gs.BarrierConfig
+	blockSize = config.BlockSize
+	height := float64(config.Height) * blockSize
+	origin = Vector2{
+		X: 0,
+		Y: gs.Orientation().SpanTop(gs.shipLine()-config.Elevation-height, height, gs.ScreenHeight),
+	}
+
+	columns := int(float64(gs.ScreenWidth) / blockSize)
+	barriers = make([][]bool, columns)
+	for i := range barriers {
+		barriers[i] = make([]bool, config.Height)
+	}
+	if config.Count == 0 {
+		return barriers, origin, blockSize
+	}
+
+	// Spread the barriers evenly across the screen
+	spacing := columns / config.Count
+	for barrier := 0; barrier < config.Count; barrier++ {
+		startX := barrier*spacing + (spacing-config.Width)/2
+
+		for x := 0; x < config.Width; x++ {
+			column := startX + x
+			if column < 0 || column >= columns {
+				continue
+			}
+			for y := 0; y < config.Height; y++ {
+				// Mirrored barriers are built upside down so they face the invaders
+				row := y
+				if gs.Orientation() == OrientationMirrored {
+					row = config.Height - 1 - y
+				}
+				barriers[column][row] = config.Shape.Includes(x, y, config.Width, config.Height)
+			}
+		}
+	}
+	return barriers, origin, blockSize
+}
+
+// regenerateBarriers rebuilds a share of the destroyed barrier blocks between
+// waves. The blocks chosen follow the run seed.
+func (gs *GameState) regenerateBarriers() {
+	share := gs.BarrierConfig.Regenerate
+	if share <= 0 || len(gs.Barriers) == 0 {
+		return
+	}
+
+	// Only a grid laid out the way the intact one would be can be patched
+	// from it block for block
+	intact, origin, blockSize := gs.buildBarriers()
+	if origin != gs.BarrierOrigin || blockSize != gs.BarrierBlockSize || !sameGridShape(intact, gs.Barriers) {
+		return
+	}
+
+	rng := rand.New(rand.NewSource(gs.Seed*17 + int64(gs.Wave)))
+	for x, column := range intact {
+		for y, solid := range column {
+			if solid && !gs.Barriers[x][y] && rng.Float64() < share {
+				gs.Barriers[x][y] = true
+			}
+		}
+	}
+}
+
+// sameGridShape reports whether two barrier grids have the same dimensions
+func sameGridShape(a, b [][]bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for x := range a {
+		if len(a[x]) != len(b[x]) {
+			return false
+		}
+	}
+	return true
+}
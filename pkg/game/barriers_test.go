@@ -0,0 +1,71 @@
+package game
+
+import "testing"
+
+func TestBarrierConfigWaitsForNextGame(t *testing.T) {
+	e := NewEngine()
+	e.GetState().Options = GameOptions{Seed: 1, Practice: true}
+	e.StartNewGame()
+	gs := e.GetState()
+	started := DefaultBarrierConfig()
+	columns := len(gs.Barriers)
+
+	// Knock out a block so regeneration has something to rebuild
+	gs.Barriers[columns/2][gs.BarrierConfig.Height/2] = false
+
+	config := DefaultBarrierConfig()
+	config.BlockSize = 2
+	config.Regenerate = 0.5
+	if err := e.SetBarrierConfig(config); err != nil {
+		t.Fatal(err)
+	}
+
+	// The game in progress keeps its layout through the next wave
+	gs.NextWave()
+	if gs.BarrierConfig != started || gs.BarrierBlockSize != started.BlockSize || len(gs.Barriers) != columns {
+		t.Errorf("layout changed mid-game: config %+v, block size %v, %d columns", gs.BarrierConfig, gs.BarrierBlockSize, len(gs.Barriers))
+	}
+
+	// The next game builds the new layout
+	e.StartNewGame()
+	gs = e.GetState()
+	if gs.BarrierBlockSize != 2 || len(gs.Barriers) != int(float64(gs.ScreenWidth)/2) {
+		t.Errorf("new game kept the old layout: block size %v, %d columns", gs.BarrierBlockSize, len(gs.Barriers))
+	}
+}
+
+func TestRegenerateSkipsMismatchedGrid(t *testing.T) {
+	e := NewEngine()
+	e.GetState().Options = GameOptions{Seed: 1, Practice: true}
+	e.StartNewGame()
+	gs := e.GetState()
+
+	// A config that no longer matches the grid in play leaves it alone
+	gs.BarrierConfig.BlockSize = 2
+	gs.BarrierConfig.Regenerate = 1
+	gs.Barriers[0] = nil
+	gs.regenerateBarriers()
+	if gs.Barriers[0] != nil {
+		t.Errorf("regenerated a grid laid out differently")
+	}
+}
+
+func TestBarrierConfigMustFitPlayfield(t *testing.T) {
+	e := NewEngine()
+	if err := e.SetBarrierConfig(DefaultBarrierConfig()); err != nil {
+		t.Errorf("default layout rejected: %v", err)
+	}
+
+	overlapping := DefaultBarrierConfig()
+	overlapping.Count = 12
+	if err := e.SetBarrierConfig(overlapping); err == nil {
+		t.Error("accepted 12 barriers that overlap their neighbours")
+	}
+
+	wide := DefaultBarrierConfig()
+	wide.Count = 1
+	wide.Width = 300
+	if err := e.SetBarrierConfig(wide); err == nil {
+		t.Error("accepted a barrier wider than the playfield")
+	}
+}
@@ -0,0 +1,129 @@
+package game
+
+import (
+	"fmt"
+	"math"
+)
+
+// ObjectiveKind identifies the rule a challenge stage checks
+type ObjectiveKind string
+
+const (
+	ObjectiveTimeLimit ObjectiveKind = "time_limit" // clear the wave within Seconds
+	ObjectiveNoMiss    ObjectiveKind = "no_miss"    // every shot must hit
+	ObjectiveNoMove    ObjectiveKind = "no_move"    // the ship may not leave its spawn column
+)
+
+// noMoveTolerance is how far the ship may drift before a no-move objective fails
+const noMoveTolerance = 1.0
+
+// Challenge declares the objective for a scripted challenge stage
+type Challenge struct {
+	Wave    int           `json:"wave"`
+	Kind    ObjectiveKind `json:"kind"`
+	Seconds float64       `json:"seconds,omitempty"`
+	Text    string        `json:"text"`
+	Bonus   int           `json:"bonus"`
+}
+
+// ChallengeStatus is the pass/fail state of the current objective
+type ChallengeStatus int
+
+const (
+	ChallengeNone   ChallengeStatus = iota // No objective this wave
+	ChallengeActive                        // Objective is still achievable
+	ChallengePassed                        // Wave cleared with the objective met
+	ChallengeFailed                        // Objective was broken
+)
+
+// String returns the string representation of the challenge status
+func (cs ChallengeStatus) String() string {
+	switch cs {
+	case ChallengeNone:
+		return "None"
+	case ChallengeActive:
+		return "Active"
+	case ChallengePassed:
+		return "Passed"
+	case ChallengeFailed:
+		return "Failed"
+	default:
+		return "Unknown"
+	}
+}
+
+// validate checks a challenge for unusable values
+func (c Challenge) validate() error {
+	if c.Wave < 1 {
+		return fmt.Errorf("wave must be at least 1")
+	}
+	if c.Text == "" {
+		return fmt.Errorf("text is empty")
+	}
+	switch c.Kind {
+	case ObjectiveTimeLimit:
+		if c.Seconds <= 0 {
+			return fmt.Errorf("time_limit needs positive seconds")
+		}
+	case ObjectiveNoMiss, ObjectiveNoMove:
+	default:
+		return fmt.Errorf("unknown objective kind %q", c.Kind)
+	}
+	return nil
+}
+
+// TimeRemaining returns the seconds left on a time-limit objective
+func (gs *GameState) TimeRemaining() float64 {
+	if gs.Challenge == nil || gs.Challenge.Kind != ObjectiveTimeLimit {
+		return 0
+	}
+	return math.Max(0, gs.Challenge.Seconds-gs.ChallengeTime)
+}
+
+// startChallenge sets up the objective for the current wave, if any
+func (gs *GameState) startChallenge() {
+	gs.Challenge = gs.WaveConfig.ChallengeForWave(gs.Wave)
+	gs.ChallengeTime = 0
+	gs.ChallengeStatus = ChallengeNone
+	if gs.Challenge != nil {
+		gs.ChallengeStatus = ChallengeActive
+	}
+}
+
+// FailChallenge marks the active objective as failed
+func (gs *GameState) FailChallenge() {
+	if gs.ChallengeStatus == ChallengeActive {
+		gs.ChallengeStatus = ChallengeFailed
+	}
+}
+
+// UpdateChallenge advances the objective timer and checks continuous rules
+func (gs *GameState) UpdateChallenge(deltaTime float64) {
+	if gs.ChallengeStatus != ChallengeActive {
+		return
+	}
+	gs.ChallengeTime += deltaTime
+
+	switch gs.Challenge.Kind {
+	case ObjectiveTimeLimit:
+		if gs.ChallengeTime > gs.Challenge.Seconds {
+			gs.FailChallenge()
+		}
+	case ObjectiveNoMove:
+		// Every ship must hold its spawn column
+		for _, player := range gs.Players {
+			if player.Ship != nil && math.Abs(player.Ship.Position.X-gs.SpawnX(player.Index)) > noMoveTolerance {
+				gs.FailChallenge()
+			}
+		}
+	}
+}
+
+// CompleteChallenge evaluates the objective at wave clear and returns the bonus earned
+func (gs *GameState) CompleteChallenge() int {
+	if gs.ChallengeStatus != ChallengeActive {
+		return 0
+	}
+	gs.ChallengeStatus = ChallengePassed
+	return gs.Challenge.Bonus
+}
@@ -0,0 +1,52 @@
+package game
+
+// observed holds the values change events were last sent for
+type observed struct {
+	started   bool
+	mode      GameMode
+	wave      int
+	highScore int
+	scores    []int
+	lives     []int
+}
+
+// emitChanges sends change events for the score, lives, wave and mode
+// values that differ from the last time they were checked, so front-ends
+// can update their displays without polling the state every frame. The
+// first check reports every value.
+func (e *Engine) emitChanges() {
+	gs, last := e.state, &e.observed
+	first := !last.started
+	last.started = true
+
+	if first || gs.Mode != last.mode {
+		last.mode = gs.Mode
+		e.emit(Event{Type: EventModeChanged, Mode: gs.Mode})
+	}
+	if first || gs.Wave != last.wave {
+		last.wave = gs.Wave
+		e.emit(Event{Type: EventWaveChanged, Value: gs.Wave})
+	}
+	if first || gs.HighScore != last.highScore {
+		last.highScore = gs.HighScore
+		e.emit(Event{Type: EventHighScoreChanged, Value: gs.HighScore})
+	}
+
+	// A new game may bring a different number of players
+	if len(last.scores) != len(gs.Players) {
+		last.scores = make([]int, len(gs.Players))
+		last.lives = make([]int, len(gs.Players))
+		first = true
+	}
+	for i, player := range gs.Players {
+		if first || player.Score != last.scores[i] {
+			gained := player.Score - last.scores[i]
+			last.scores[i] = player.Score
+			e.emit(Event{Type: EventScoreChanged, Player: i, Points: gained, Value: player.Score})
+		}
+		if first || player.Lives != last.lives[i] {
+			last.lives[i] = player.Lives
+			e.emit(Event{Type: EventLivesChanged, Player: i, Value: player.Lives})
+		}
+	}
+}
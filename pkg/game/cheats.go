@@ -0,0 +1,99 @@
+//go:build cheats
+
+package game
+
+// CheatsEnabled reports whether the developer cheat layer is compiled in
+const CheatsEnabled = true
+
+// cheatCode is the key sequence that switches the cheat keys on and off
+var cheatCode = []string{"ArrowUp", "ArrowUp", "ArrowDown", "ArrowDown", "ArrowLeft", "ArrowRight", "ArrowLeft", "ArrowRight"}
+
+// Cheat keys, live once the cheat code has been typed
+const (
+	cheatKeyGod   = "Digit7" // toggle god mode
+	cheatKeySkip  = "Digit8" // clear the current wave
+	cheatKeyUFO   = "Digit9" // send a UFO across now
+	cheatKeyScore = "Digit0" // add cheatScore points
+)
+
+// cheatScore is how many points the add score cheat awards
+const cheatScore = 1000
+
+// cheatState tracks the cheat code and the active cheats
+type cheatState struct {
+	typed []string // the most recent keys, to spot the cheat code
+	armed bool     // cheat keys are live
+	god   bool     // enemy fire and collisions pass through the ships
+}
+
+// ProcessCheatKey feeds a key press, by its code, to the cheat layer
+func (e *Engine) ProcessCheatKey(code string) {
+	if e.matchCheatCode(code) {
+		e.cheats.armed = !e.cheats.armed
+		e.updateCheatStatus()
+		return
+	}
+	if !e.cheats.armed || e.state.Mode != Playing || e.state.Demo {
+		return
+	}
+
+	switch code {
+	case cheatKeyGod:
+		e.cheats.god = !e.cheats.god
+	case cheatKeySkip:
+		if e.state.WaveCleared {
+			return
+		}
+		e.state.Invaders = nil
+		e.state.Boss = nil
+	case cheatKeyUFO:
+		if e.state.UFO == nil {
+			e.ufoTimer = 0
+		}
+	case cheatKeyScore:
+		if player := e.state.PrimaryPlayer(); player != nil {
+			e.state.AddScore(player, cheatScore)
+		}
+	default:
+		return
+	}
+
+	// Scores from cheated games never count
+	e.state.Cheated = true
+	e.updateCheatStatus()
+}
+
+// matchCheatCode records the key and reports whether it completes the cheat code
+func (e *Engine) matchCheatCode(code string) bool {
+	e.cheats.typed = append(e.cheats.typed, code)
+	if len(e.cheats.typed) > len(cheatCode) {
+		e.cheats.typed = e.cheats.typed[1:]
+	}
+	if len(e.cheats.typed) < len(cheatCode) {
+		return false
+	}
+	for i, key := range cheatCode {
+		if e.cheats.typed[i] != key {
+			return false
+		}
+	}
+	e.cheats.typed = nil
+	return true
+}
+
+// godMode reports whether the ships ignore enemy fire and collisions
+func (e *Engine) godMode() bool {
+	return e.cheats.god
+}
+
+// updateCheatStatus describes the active cheats for the HUD
+func (e *Engine) updateCheatStatus() {
+	switch {
+	case !e.cheats.armed:
+		e.state.CheatStatus = ""
+	case e.cheats.god:
+		e.state.CheatStatus = "CHEATS: GOD"
+	default:
+		e.state.CheatStatus = "CHEATS"
+	}
+}
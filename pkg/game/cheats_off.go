@@ -0,0 +1,18 @@
+//go:build !cheats
+
+package game
+
+// CheatsEnabled reports whether the developer cheat layer is compiled in.
+// Build with -tags cheats to include it.
+const CheatsEnabled = false
+
+// cheatState is empty when the cheat layer is compiled out
+type cheatState struct{}
+
+// ProcessCheatKey ignores key presses when the cheat layer is compiled out
+func (e *Engine) ProcessCheatKey(code string) {}
+
+// godMode is always off when the cheat layer is compiled out
+func (e *Engine) godMode() bool {
+	return false
+}
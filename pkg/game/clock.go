@@ -0,0 +1,40 @@
+package game
+
+// Clock tells the simulation what time it is, in seconds of game time. The
+// engine advances its clock by the fixed timestep, so anything timed against
+// it pauses with the game and plays out the same way every run.
+type Clock interface {
+	Now() float64            // seconds of game time since the clock started
+	Advance(seconds float64) // move the clock forward
+}
+
+// GameClock is a Clock that only moves when advanced
+type GameClock struct {
+	elapsed float64
+}
+
+// NewGameClock creates a clock starting at zero
+func NewGameClock() *GameClock {
+	return &GameClock{}
+}
+
+// Now returns the seconds of game time since the clock started
+func (c *GameClock) Now() float64 {
+	return c.elapsed
+}
+
+// Advance moves the clock forward by the given number of seconds
+func (c *GameClock) Advance(seconds float64) {
+	c.elapsed += seconds
+}
+
+// SetClock replaces the engine's clock, for example with one shared with a
+// replay or a test harness
+func (e *Engine) SetClock(clock Clock) {
+	e.clock = clock
+}
+
+// Clock returns the engine's game clock
+func (e *Engine) Clock() Clock {
+	return e.clock
+}
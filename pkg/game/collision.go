@@ -0,0 +1,568 @@
+package game
+
+import (
+	"math"
+	"slices"
+)
+
+// DefaultCellSize is the spatial hash cell size, in pixels. It is a little
+// larger than an invader plus its spacing, so most entities touch few cells.
+const DefaultCellSize = 48.0
+
+// CollisionSystem handles all collision detection and response. Entities are
+// inserted into a uniform grid covering the playfield each step, so a query
+// only checks the entities in the cells it overlaps instead of every entity.
+// Anything beyond the playfield edges falls into the outermost cells.
+type CollisionSystem struct {
+	cellSize   float64
+	cols, rows int
+	cells      [][]int // entity ids by the cells their bounds touch
+
+	// Query scratch space, reused between queries to avoid allocations
+	marks   []int // last query each id was returned by
+	query   int
+	results []int
+
+	// Work done since the counters were last reset, for engine metrics
+	queries int // broad-phase grid queries
+	checks  int // exact checks between pairs of entities
+}
+
+// NewCollisionSystem creates a collision system for a playfield of the given
+// size with the default cell size
+func NewCollisionSystem(width, height float64) *CollisionSystem {
+	return NewCollisionSystemWithCellSize(width, height, DefaultCellSize)
+}
+
+// NewCollisionSystemWithCellSize creates a collision system for a playfield
+// of the given size whose grid cells are cellSize pixels square
+func NewCollisionSystemWithCellSize(width, height, cellSize float64) *CollisionSystem {
+	cols := int(math.Ceil(width/cellSize)) + 1
+	rows := int(math.Ceil(height/cellSize)) + 1
+	return &CollisionSystem{
+		cellSize: cellSize,
+		cols:     cols,
+		rows:     rows,
+		cells:    make([][]int, cols*rows),
+	}
+}
+
+// Reset empties the grid ready for a new set of entities, keeping its memory
+func (cs *CollisionSystem) Reset() {
+	for i := range cs.cells {
+		cs.cells[i] = cs.cells[i][:0]
+	}
+}
+
+// Insert adds an entity's bounds to the grid under the given id. Ids should
+// be small and dense, such as slice indices, and inserted in ascending order.
+func (cs *CollisionSystem) Insert(id int, bounds Bounds) {
+	minX, minY, maxX, maxY := cs.cellRange(bounds)
+	for y := minY; y <= maxY; y++ {
+		for x := minX; x <= maxX; x++ {
+			cell := y*cs.cols + x
+			cs.cells[cell] = append(cs.cells[cell], id)
+		}
+	}
+	for id >= len(cs.marks) {
+		cs.marks = append(cs.marks, 0)
+	}
+}
+
+// Query returns the ids of entities sharing a cell with the bounds, in
+// ascending order. They are candidates only and still need an exact check.
+// The returned slice is reused by the next query.
+func (cs *CollisionSystem) Query(bounds Bounds) []int {
+	cs.query++
+	cs.queries++
+	cs.results = cs.results[:0]
+
+	minX, minY, maxX, maxY := cs.cellRange(bounds)
+	for y := minY; y <= maxY; y++ {
+		for x := minX; x <= maxX; x++ {
+			for _, id := range cs.cells[y*cs.cols+x] {
+				if cs.marks[id] != cs.query {
+					cs.marks[id] = cs.query
+					cs.results = append(cs.results, id)
+				}
+			}
+		}
+	}
+
+	// Each cell is already in order, so only merged cells need sorting
+	if minX != maxX || minY != maxY {
+		slices.Sort(cs.results)
+	}
+	return cs.results
+}
+
+// cellRange returns the range of cells the bounds overlap, clamped to the grid
+func (cs *CollisionSystem) cellRange(bounds Bounds) (minX, minY, maxX, maxY int) {
+	minX = cs.clampCell(bounds.X, cs.cols)
+	minY = cs.clampCell(bounds.Y, cs.rows)
+	maxX = cs.clampCell(bounds.X+bounds.Width, cs.cols)
+	maxY = cs.clampCell(bounds.Y+bounds.Height, cs.rows)
+	return minX, minY, maxX, maxY
+}
+
+// clampCell returns the cell index of a coordinate along an axis of n cells
+func (cs *CollisionSystem) clampCell(coordinate float64, n int) int {
+	cell := int(math.Floor(coordinate / cs.cellSize))
+	return max(0, min(cell, n-1))
+}
+
+// Counts returns the grid queries and exact checks made since the last
+// ResetCounts
+func (cs *CollisionSystem) Counts() (queries, checks int) {
+	return cs.queries, cs.checks
+}
+
+// ResetCounts zeroes the query and check counters
+func (cs *CollisionSystem) ResetCounts() {
+	cs.queries = 0
+	cs.checks = 0
+}
+
+// Overlaps reports whether two bounds overlap
+func (cs *CollisionSystem) Overlaps(a, b Bounds) bool {
+	return CheckAABBCollision(a, b)
+}
+
+// Details returns how two bounds overlap, with the penetration depth and
+// contact point when they do
+func (cs *CollisionSystem) Details(a, b Bounds) CollisionResult {
+	return CheckAABBCollisionWithDetails(a, b)
+}
+
+// BulletHitsInvader reports whether a live bullet hits a live invader
+func (cs *CollisionSystem) BulletHitsInvader(bullet *Bullet, invader *Invader) bool {
+	cs.checks++
+	return CheckBulletInvaderCollision(bullet, invader)
+}
+
+// BulletHitsPlayer reports whether a live enemy bullet hits a live ship
+func (cs *CollisionSystem) BulletHitsPlayer(bullet *Bullet, player *PlayerShip) bool {
+	cs.checks++
+	return CheckBulletPlayerCollision(bullet, player)
+}
+
+// BulletHitsUFO reports whether a live player bullet hits the UFO
+func (cs *CollisionSystem) BulletHitsUFO(bullet *Bullet, ufo *UFO) bool {
+	cs.checks++
+	return CheckBulletUFOCollision(bullet, ufo)
+}
+
+// BulletHitsBoss reports whether a live player bullet hits the boss's hull
+func (cs *CollisionSystem) BulletHitsBoss(bullet *Bullet, boss *Boss) bool {
+	cs.checks++
+	return bullet.Alive && boss.Alive && bullet.IsPlayerBullet && CheckAABBCollision(bullet.Bounds, boss.Bounds)
+}
+
+// BulletWeakPoint returns the boss weak point a bullet hits, or nil
+func (cs *CollisionSystem) BulletWeakPoint(bullet *Bullet, boss *Boss) *WeakPoint {
+	cs.checks++
+	return CheckBulletWeakPointCollision(bullet, boss)
+}
+
+// BulletHitsDebris reports whether a live bullet hits live debris
+func (cs *CollisionSystem) BulletHitsDebris(bullet *Bullet, debris *Debris) bool {
+	cs.checks++
+	return bullet.Alive && debris.Alive && CheckAABBCollision(bullet.Bounds, debris.Bounds)
+}
+
+// PlayerHitsInvader reports whether a live ship collides with a live invader
+func (cs *CollisionSystem) PlayerHitsInvader(player *PlayerShip, invader *Invader) bool {
+	cs.checks++
+	return CheckPlayerInvaderCollision(player, invader)
+}
+
+// PlayerCatchesPickup reports whether a live ship touches a falling pickup
+func (cs *CollisionSystem) PlayerCatchesPickup(player *PlayerShip, pickup *Pickup) bool {
+	cs.checks++
+	return player.Alive && pickup.Alive && CheckAABBCollision(player.Bounds, pickup.Bounds)
+}
+
+// BeamHits reports whether a beam passes through the bounds
+func (cs *CollisionSystem) BeamHits(beam *Beam, bounds Bounds) bool {
+	cs.checks++
+	return beam.Hits(bounds)
+}
+
+// BulletHitsBarrier returns the first solid barrier block a live bullet hits
+func (cs *CollisionSystem) BulletHitsBarrier(bullet *Bullet, gs *GameState) (bool, int, int) {
+	cs.checks++
+	return CheckBulletBarrierCollision(bullet, gs.Barriers, gs.BarrierOrigin, gs.BarrierBlockSize)
+}
+
+// BoundsHitBarrier returns the first solid barrier block the bounds overlap
+func (cs *CollisionSystem) BoundsHitBarrier(bounds Bounds, gs *GameState) (bool, int, int) {
+	cs.checks++
+	return CheckBoundsBarrierCollision(bounds, gs.Barriers, gs.BarrierOrigin, gs.BarrierBlockSize)
+}
+
+// CheckAABBCollision performs Axis-Aligned Bounding Box collision detection
+func CheckAABBCollision(a, b Bounds) bool {
+	return a.X < b.X+b.Width &&
+		a.X+a.Width > b.X &&
+		a.Y < b.Y+b.Height &&
+		a.Y+a.Height > b.Y
+}
+
+// CheckPointInBounds checks if a point is within bounds
+func CheckPointInBounds(x, y float64, bounds Bounds) bool {
+	return x >= bounds.X && x < bounds.X+bounds.Width &&
+		y >= bounds.Y && y < bounds.Y+bounds.Height
+}
+
+// CheckCircleCollision performs circle-to-circle collision detection
+func CheckCircleCollision(x1, y1, r1, x2, y2, r2 float64) bool {
+	dx := x1 - x2
+	dy := y1 - y2
+	distance := math.Sqrt(dx*dx + dy*dy)
+	return distance < (r1 + r2)
+}
+
+// CheckCircleRectCollision checks collision between a circle and rectangle
+func CheckCircleRectCollision(circleX, circleY, radius float64, rect Bounds) bool {
+	// Find the closest point on the rectangle to the circle center
+	closestX := math.Max(rect.X, math.Min(circleX, rect.X+rect.Width))
+	closestY := math.Max(rect.Y, math.Min(circleY, rect.Y+rect.Height))
+
+	// Calculate the distance between the circle center and the closest point
+	dx := circleX - closestX
+	dy := circleY - closestY
+
+	// Check if the distance is less than the circle radius
+	return (dx*dx + dy*dy) < (radius * radius)
+}
+
+// CollisionResult represents the result of a collision check
+type CollisionResult struct {
+	Collided      bool
+	PenetrationX  float64
+	PenetrationY  float64
+	ContactPointX float64
+	ContactPointY float64
+}
+
+// CheckAABBCollisionWithDetails performs AABB collision with detailed results
+func CheckAABBCollisionWithDetails(a, b Bounds) CollisionResult {
+	result := CollisionResult{}
+
+	// Check if collision occurs
+	if !CheckAABBCollision(a, b) {
+		return result
+	}
+
+	result.Collided = true
+
+	// Calculate overlap amounts
+	overlapX1 := (a.X + a.Width) - b.X
+	overlapX2 := (b.X + b.Width) - a.X
+	overlapY1 := (a.Y + a.Height) - b.Y
+	overlapY2 := (b.Y + b.Height) - a.Y
+
+	// Choose minimum overlap for separation
+	if overlapX1 < overlapX2 {
+		result.PenetrationX = overlapX1
+	} else {
+		result.PenetrationX = -overlapX2
+	}
+
+	if overlapY1 < overlapY2 {
+		result.PenetrationY = overlapY1
+	} else {
+		result.PenetrationY = -overlapY2
+	}
+
+	// Calculate contact point (center of overlapping area)
+	overlapLeft := math.Max(a.X, b.X)
+	overlapRight := math.Min(a.X+a.Width, b.X+b.Width)
+	overlapTop := math.Max(a.Y, b.Y)
+	overlapBottom := math.Min(a.Y+a.Height, b.Y+b.Height)
+
+	result.ContactPointX = (overlapLeft + overlapRight) / 2
+	result.ContactPointY = (overlapTop + overlapBottom) / 2
+
+	return result
+}
+
+// CheckBulletInvaderCollision checks collision between a bullet and invader with pixel-perfect detection
+func CheckBulletInvaderCollision(bullet *Bullet, invader *Invader) bool {
+	if !bullet.Alive || !invader.Alive {
+		return false
+	}
+
+	// First do AABB check for early rejection
+	if !CheckAABBCollision(bullet.Bounds, invader.Bounds) {
+		return false
+	}
+
+	// For Space Invaders, AABB is usually sufficient since bullets are small
+	// and invaders are relatively large. More detailed collision detection
+	// could be added here if needed (e.g., pixel-perfect collision).
+	return true
+}
+
+// CheckBulletPlayerCollision checks collision between a bullet and player
+func CheckBulletPlayerCollision(bullet *Bullet, player *PlayerShip) bool {
+	if !bullet.Alive || !player.Alive || bullet.IsPlayerBullet {
+		return false
+	}
+
+	return CheckAABBCollision(bullet.Bounds, player.Bounds)
+}
+
+// CheckBulletUFOCollision checks collision between a bullet and UFO
+func CheckBulletUFOCollision(bullet *Bullet, ufo *UFO) bool {
+	if !bullet.Alive || !ufo.Alive || !bullet.IsPlayerBullet {
+		return false
+	}
+
+	return CheckAABBCollision(bullet.Bounds, ufo.Bounds)
+}
+
+// CheckBulletWeakPointCollision returns the boss weak point a bullet hits, or
+// nil when it misses them all
+func CheckBulletWeakPointCollision(bullet *Bullet, boss *Boss) *WeakPoint {
+	if !bullet.Alive || !boss.Alive || !bullet.IsPlayerBullet {
+		return nil
+	}
+
+	for _, point := range boss.WeakPoints {
+		if !point.Destroyed() && CheckAABBCollision(bullet.Bounds, point.Bounds) {
+			return point
+		}
+	}
+	return nil
+}
+
+// CheckPlayerInvaderCollision checks direct collision between player and invader
+func CheckPlayerInvaderCollision(player *PlayerShip, invader *Invader) bool {
+	if !player.Alive || !invader.Alive {
+		return false
+	}
+
+	return CheckAABBCollision(player.Bounds, invader.Bounds)
+}
+
+// CheckBoundaryCollision checks if an entity is within screen boundaries
+func CheckBoundaryCollision(bounds Bounds, screenWidth, screenHeight float64) (left, right, top, bottom bool) {
+	left = bounds.X < 0
+	right = bounds.X+bounds.Width > screenWidth
+	top = bounds.Y < 0
+	bottom = bounds.Y+bounds.Height > screenHeight
+	return
+}
+
+// KeepInBounds constrains bounds within screen boundaries
+func KeepInBounds(bounds *Bounds, screenWidth, screenHeight float64) {
+	if bounds.X < 0 {
+		bounds.X = 0
+	}
+	if bounds.X+bounds.Width > screenWidth {
+		bounds.X = screenWidth - bounds.Width
+	}
+	if bounds.Y < 0 {
+		bounds.Y = 0
+	}
+	if bounds.Y+bounds.Height > screenHeight {
+		bounds.Y = screenHeight - bounds.Height
+	}
+}
+
+// LineIntersection checks if two line segments intersect
+func LineIntersection(x1, y1, x2, y2, x3, y3, x4, y4 float64) (bool, float64, float64) {
+	// Calculate the denominator
+	denom := (x1-x2)*(y3-y4) - (y1-y2)*(x3-x4)
+	if math.Abs(denom) < 1e-10 {
+		return false, 0, 0 // Lines are parallel
+	}
+
+	// Calculate intersection parameters
+	t := ((x1-x3)*(y3-y4) - (y1-y3)*(x3-x4)) / denom
+	u := -((x1-x2)*(y1-y3) - (y1-y2)*(x1-x3)) / denom
+
+	// Check if intersection occurs within both line segments
+	if t >= 0 && t <= 1 && u >= 0 && u <= 1 {
+		// Calculate intersection point
+		intersectX := x1 + t*(x2-x1)
+		intersectY := y1 + t*(y2-y1)
+		return true, intersectX, intersectY
+	}
+
+	return false, 0, 0
+}
+
+// LineIntersectsBounds checks if a line segment touches an axis-aligned box
+func LineIntersectsBounds(x1, y1, x2, y2 float64, b Bounds) bool {
+	if b.Contains(x1, y1) || b.Contains(x2, y2) {
+		return true
+	}
+
+	left, right := b.X, b.X+b.Width
+	top, bottom := b.Y, b.Y+b.Height
+	edges := [][4]float64{
+		{left, top, right, top},
+		{right, top, right, bottom},
+		{left, bottom, right, bottom},
+		{left, top, left, bottom},
+	}
+	for _, edge := range edges {
+		if hit, _, _ := LineIntersection(x1, y1, x2, y2, edge[0], edge[1], edge[2], edge[3]); hit {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckBulletBarrierCollision checks collision between bullet and barrier.
+// origin is the world position of barrier block [0][0].
+func CheckBulletBarrierCollision(bullet *Bullet, barriers [][]bool, origin Vector2, barrierBlockSize float64) (bool, int, int) {
+	if !bullet.Alive || len(barriers) == 0 || barrierBlockSize <= 0 {
+		return false, -1, -1
+	}
+
+	return CheckBoundsBarrierCollision(bullet.Bounds, barriers, origin, barrierBlockSize)
+}
+
+// CheckBoundsBarrierCollision returns the first solid barrier block overlapped by bounds
+func CheckBoundsBarrierCollision(bounds Bounds, barriers [][]bool, origin Vector2, barrierBlockSize float64) (bool, int, int) {
+	if len(barriers) == 0 || barrierBlockSize <= 0 {
+		return false, -1, -1
+	}
+
+	// Calculate which barrier blocks the bounds overlap
+	left := int(math.Floor((bounds.X - origin.X) / barrierBlockSize))
+	right := int(math.Floor((bounds.X + bounds.Width - origin.X) / barrierBlockSize))
+	top := int(math.Floor((bounds.Y - origin.Y) / barrierBlockSize))
+	bottom := int(math.Floor((bounds.Y + bounds.Height - origin.Y) / barrierBlockSize))
+
+	// Clamp to barrier array bounds
+	if left < 0 {
+		left = 0
+	}
+	if right >= len(barriers) {
+		right = len(barriers) - 1
+	}
+	if top < 0 {
+		top = 0
+	}
+	if bottom >= len(barriers[0]) {
+		bottom = len(barriers[0]) - 1
+	}
+
+	// Check for collision with barrier blocks
+	for x := left; x <= right; x++ {
+		for y := top; y <= bottom; y++ {
+			if barriers[x][y] {
+				return true, x, y
+			}
+		}
+	}
+
+	return false, -1, -1
+}
+
+// DestroyBarrierBlock destroys a barrier block and surrounding blocks for impact effect
+func DestroyBarrierBlock(barriers [][]bool, x, y int, radius int) {
+	if x < 0 || x >= len(barriers) || y < 0 || y >= len(barriers[0]) {
+		return
+	}
+
+	// Destroy blocks in a circular pattern
+	for dx := -radius; dx <= radius; dx++ {
+		for dy := -radius; dy <= radius; dy++ {
+			newX := x + dx
+			newY := y + dy
+
+			if newX >= 0 && newX < len(barriers) && newY >= 0 && newY < len(barriers[0]) {
+				// Use circular destruction pattern
+				distance := math.Sqrt(float64(dx*dx + dy*dy))
+				if distance <= float64(radius) {
+					barriers[newX][newY] = false
+				}
+			}
+		}
+	}
+}
+
+// GetClosestPoint returns the closest point on a rectangle to a given point
+func GetClosestPoint(pointX, pointY float64, rect Bounds) (float64, float64) {
+	closestX := math.Max(rect.X, math.Min(pointX, rect.X+rect.Width))
+	closestY := math.Max(rect.Y, math.Min(pointY, rect.Y+rect.Height))
+	return closestX, closestY
+}
+
+// GetDistance calculates the distance between two points
+func GetDistance(x1, y1, x2, y2 float64) float64 {
+	dx := x1 - x2
+	dy := y1 - y2
+	return math.Sqrt(dx*dx + dy*dy)
+}
+
+// GetDistanceSquared calculates the squared distance between two points (faster than GetDistance)
+func GetDistanceSquared(x1, y1, x2, y2 float64) float64 {
+	dx := x1 - x2
+	dy := y1 - y2
+	return dx*dx + dy*dy
+}
+
+// NormalizeVector normalizes a vector to unit length
+func NormalizeVector(x, y float64) (float64, float64) {
+	length := math.Sqrt(float64(x*x) + float64(y*y))
+	if length == 0 {
+		return 0, 0
+	}
+	return x / length, y / length
+}
+
+// ReflectVector reflects a vector off a surface with the given normal
+func ReflectVector(vecX, vecY, normalX, normalY float64) (float64, float64) {
+	// Reflection formula: r = d - 2(d·n)n
+	// where d is the incident vector, n is the normal, r is the reflected vector
+	dotProduct := vecX*normalX + vecY*normalY
+	reflectedX := vecX - 2*dotProduct*normalX
+	reflectedY := vecY - 2*dotProduct*normalY
+	return reflectedX, reflectedY
+}
+
+// SeparateEntities separates two overlapping entities
+func SeparateEntities(bounds1, bounds2 *Bounds, mass1, mass2 float64) {
+	if !CheckAABBCollision(*bounds1, *bounds2) {
+		return
+	}
+
+	// Calculate overlap
+	overlapX := math.Min(bounds1.X+bounds1.Width, bounds2.X+bounds2.Width) - math.Max(bounds1.X, bounds2.X)
+	overlapY := math.Min(bounds1.Y+bounds1.Height, bounds2.Y+bounds2.Height) - math.Max(bounds1.Y, bounds2.Y)
+
+	// Determine separation direction (choose minimum overlap)
+	if overlapX < overlapY {
+		// Separate horizontally
+		totalMass := mass1 + mass2
+		separation1 := overlapX * (mass2 / totalMass)
+		separation2 := overlapX * (mass1 / totalMass)
+
+		if bounds1.X < bounds2.X {
+			bounds1.X -= separation1
+			bounds2.X += separation2
+		} else {
+			bounds1.X += separation1
+			bounds2.X -= separation2
+		}
+	} else {
+		// Separate vertically
+		totalMass := mass1 + mass2
+		separation1 := overlapY * (mass2 / totalMass)
+		separation2 := overlapY * (mass1 / totalMass)
+
+		if bounds1.Y < bounds2.Y {
+			bounds1.Y -= separation1
+			bounds2.Y += separation2
+		} else {
+			bounds1.Y += separation1
+			bounds2.Y -= separation2
+		}
+	}
+}
\ No newline at end of file
@@ -0,0 +1,212 @@
+package game
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+// benchmarkBulletCounts are the bullet counts each collision benchmark runs with
+var benchmarkBulletCounts = []int{10, 100, 1000}
+
+// benchmarkScene returns a fresh wave one formation and n player bullets
+// scattered below it, so they never hit and the scene stays the same
+func benchmarkScene(n int) (*Engine, []*Bullet) {
+	e := NewEngine()
+	e.StartNewGame()
+
+	rng := rand.New(rand.NewSource(1))
+	bullets := make([]*Bullet, n)
+	for i := range bullets {
+		bullets[i] = NewBullet(rng.Float64()*800, 300+rng.Float64()*150, 0, -400, true)
+	}
+	return e, bullets
+}
+
+func BenchmarkBruteForceCollisions(b *testing.B) {
+	for _, n := range benchmarkBulletCounts {
+		b.Run(fmt.Sprintf("bullets=%d", n), func(b *testing.B) {
+			e, bullets := benchmarkScene(n)
+			invaders := e.state.Invaders
+			b.ResetTimer()
+
+			hits := 0
+			for i := 0; i < b.N; i++ {
+				for _, bullet := range bullets {
+					for _, invader := range invaders {
+						if bullet.Bounds.Intersects(invader.Bounds) {
+							hits++
+						}
+					}
+				}
+			}
+			_ = hits
+		})
+	}
+}
+
+func BenchmarkSpatialHashCollisions(b *testing.B) {
+	for _, n := range benchmarkBulletCounts {
+		b.Run(fmt.Sprintf("bullets=%d", n), func(b *testing.B) {
+			e, bullets := benchmarkScene(n)
+			invaders := e.state.Invaders
+			cs := NewCollisionSystem(800, 500)
+			b.ResetTimer()
+
+			hits := 0
+			for i := 0; i < b.N; i++ {
+				cs.Reset()
+				for id, invader := range invaders {
+					cs.Insert(id, invader.Bounds)
+				}
+				for _, bullet := range bullets {
+					for _, id := range cs.Query(bullet.Bounds) {
+						if bullet.Bounds.Intersects(invaders[id].Bounds) {
+							hits++
+						}
+					}
+				}
+			}
+			_ = hits
+		})
+	}
+}
+
+func BenchmarkHandlePlayerBulletCollisions(b *testing.B) {
+	for _, n := range benchmarkBulletCounts {
+		b.Run(fmt.Sprintf("bullets=%d", n), func(b *testing.B) {
+			e, bullets := benchmarkScene(n)
+			e.state.Bullets = bullets
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				e.handlePlayerBulletCollisions()
+			}
+		})
+	}
+}
+
+// worstCaseBulletCounts are the bullets in flight for the worst-case
+// benchmarks, split evenly between player and invader shots
+var worstCaseBulletCounts = []int{100, 300, 600}
+
+// worstCaseScene returns a practice game with the full wave one formation,
+// intact barriers and debris, and n bullets scattered over the whole
+// playfield: every other one a player shot climbing, the rest invader shots
+// falling
+func worstCaseScene(n int) *Engine {
+	e := NewEngine()
+	e.GetState().Options = GameOptions{Seed: 1, Practice: true, Debris: true}
+	e.StartNewGame()
+
+	rng := rand.New(rand.NewSource(1))
+	for i := range n {
+		x, y := rng.Float64()*PlayfieldWidth, rng.Float64()*PlayfieldHeight
+		if i%2 == 0 {
+			e.state.Bullets = append(e.state.Bullets, NewBullet(x, y, 0, -400, true))
+		} else {
+			e.state.Bullets = append(e.state.Bullets, NewBullet(x, y, 0, 200, false))
+		}
+	}
+	track(&e.state.Entities, e.state.Bullets...)
+	return e
+}
+
+// benchmarkWorstCase times fn against the worst-case scene, restoring the
+// scene before each run since collisions destroy what they touch
+func benchmarkWorstCase(b *testing.B, fn func(e *Engine)) {
+	for _, n := range worstCaseBulletCounts {
+		b.Run(fmt.Sprintf("bullets=%d", n), func(b *testing.B) {
+			e := worstCaseScene(n)
+			scene := e.Snapshot()
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				e.Restore(scene)
+				b.StartTimer()
+				fn(e)
+			}
+		})
+	}
+}
+
+func BenchmarkHandleCollisionsWorstCase(b *testing.B) {
+	benchmarkWorstCase(b, (*Engine).handleCollisions)
+}
+
+func BenchmarkBarrierCollisionsWorstCase(b *testing.B) {
+	benchmarkWorstCase(b, (*Engine).handleBarrierCollisions)
+}
+
+func BenchmarkEnemyBulletCollisionsWorstCase(b *testing.B) {
+	benchmarkWorstCase(b, (*Engine).handleEnemyBulletCollisions)
+}
+
+func BenchmarkDebrisCollisionsWorstCase(b *testing.B) {
+	benchmarkWorstCase(b, (*Engine).handleDebrisCollisions)
+}
+
+// splitBullets separates a scene's bullets into player and invader shots
+func splitBullets(bullets []*Bullet) (player, enemy []*Bullet) {
+	for _, bullet := range bullets {
+		if bullet.IsPlayerBullet {
+			player = append(player, bullet)
+		} else {
+			enemy = append(enemy, bullet)
+		}
+	}
+	return player, enemy
+}
+
+// The bullet-vs-bullet benchmarks compare checking every player shot against
+// every invader shot with hashing the invader shots first
+func BenchmarkBruteForceBulletVsBullet(b *testing.B) {
+	for _, n := range worstCaseBulletCounts {
+		b.Run(fmt.Sprintf("bullets=%d", n), func(b *testing.B) {
+			player, enemy := splitBullets(worstCaseScene(n).state.Bullets)
+			b.ResetTimer()
+
+			hits := 0
+			for i := 0; i < b.N; i++ {
+				for _, shot := range player {
+					for _, other := range enemy {
+						if shot.Bounds.Intersects(other.Bounds) {
+							hits++
+						}
+					}
+				}
+			}
+			_ = hits
+		})
+	}
+}
+
+func BenchmarkSpatialHashBulletVsBullet(b *testing.B) {
+	for _, n := range worstCaseBulletCounts {
+		b.Run(fmt.Sprintf("bullets=%d", n), func(b *testing.B) {
+			player, enemy := splitBullets(worstCaseScene(n).state.Bullets)
+			cs := NewCollisionSystem(PlayfieldWidth, PlayfieldHeight)
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			hits := 0
+			for i := 0; i < b.N; i++ {
+				cs.Reset()
+				for id, other := range enemy {
+					cs.Insert(id, other.Bounds)
+				}
+				for _, shot := range player {
+					for _, id := range cs.Query(shot.Bounds) {
+						if shot.Bounds.Intersects(enemy[id].Bounds) {
+							hits++
+						}
+					}
+				}
+			}
+			_ = hits
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package game
+
+import "math"
+
+// steerDeadZone is how far the move axis must lean before a steering ship
+// turns, so a resting gamepad stick doesn't drift
+const steerDeadZone = 0.25
+
+// Commands is what one player asks of the game for a tick. Keyboards,
+// gamepads, touch screens, the camera and the network all turn their input
+// into commands, so the engine handles every device the same way.
+type Commands struct {
+	MoveAxis    float64 // from -1 (left) to 1 (right)
+	Aim         bool    // MoveAxis is where the ship should be rather than which way to steer
+	Fire        bool    // fire held
+	FirePressed bool    // fire pressed since the last tick
+	Pause       bool    // pause pressed since the last tick; backs out of menus
+	Confirm     bool    // start or confirm pressed since the last tick; pauses during play
+	Up          bool    // up pressed since the last tick, for menus
+	Down        bool    // down pressed since the last tick, for menus
+	CycleWeapon bool    // weapon switch pressed since the last tick
+	SmartBomb   bool    // smart bomb pressed since the last tick
+}
+
+// Axis returns the move axis for a pair of left and right buttons
+func Axis(left, right bool) float64 {
+	switch {
+	case left && !right:
+		return -1
+	case right && !left:
+		return 1
+	}
+	return 0
+}
+
+// steering returns the move axis as left and right buttons
+func (c Commands) steering() (left, right bool) {
+	return c.MoveAxis <= -steerDeadZone, c.MoveAxis >= steerDeadZone
+}
+
+// started reports whether the commands start a game or move past a screen
+func (c Commands) started() bool {
+	return c.FirePressed || c.Pause || c.Confirm
+}
+
+// selected reports whether the commands pick the highlighted menu entry
+func (c Commands) selected() bool {
+	return c.FirePressed || c.Confirm
+}
+
+// ProcessCommands applies a player's commands for the next update. Player
+// one also drives the title and end-of-game screens, the pause button and
+// the menus; co-op partners only steer and shoot.
+func (e *Engine) ProcessCommands(index int, commands Commands) {
+	if index > 0 {
+		e.processPartnerCommands(index, commands)
+		return
+	}
+
+	// Detect fresh left/right presses for menu navigation
+	input := e.state.InputState
+	left, right := commands.steering()
+	leftJustPressed := left && !input.LeftPressed
+	rightJustPressed := right && !input.RightPressed
+
+	// Update input state
+	input.LeftPressed = left
+	input.RightPressed = right
+	input.FirePressed = commands.Fire
+	input.FireJustPressed = commands.FirePressed
+	input.PauseJustPressed = commands.Pause || commands.Confirm
+
+	// Aimed input dashes on a sharp flick of the axis
+	flick := 0.0
+	if commands.Aim {
+		flick = commands.MoveAxis - e.lastAimX
+		e.lastAimX = commands.MoveAxis
+	}
+
+	// Handle mode-specific input
+	switch e.state.Mode {
+	case AttractMode:
+		// Browsing the options brings back the title, where they show, and
+		// keeps the demo away
+		if leftJustPressed || rightJustPressed {
+			e.showTitle()
+		}
+
+		// Left/right picks the difficulty preset
+		if leftJustPressed {
+			e.updateSettings(SettingDifficulty.adjust(e.state.Settings, -1))
+		} else if rightJustPressed {
+			e.updateSettings(SettingDifficulty.adjust(e.state.Settings, 1))
+		}
+	case Playing:
+		if e.state.Demo {
+			e.interruptDemo(commands.started())
+			return
+		}
+		if e.state.Paused {
+			e.processPauseMenuCommands(commands)
+			return
+		}
+		if commands.Pause || commands.Confirm {
+			e.state.TogglePause()
+			return
+		}
+		e.processPlayingCommands(e.state.PrimaryPlayer(), commands, flick)
+		return
+	case SettingsMenu:
+		e.processSettingsCommands(commands, leftJustPressed, rightJustPressed)
+		return
+	case HighScore:
+		e.processInitialsCommands(commands, leftJustPressed, rightJustPressed)
+		return
+	}
+	e.processScreenInput(commands.started())
+}
+
+// processPartnerCommands applies a co-op partner's commands during play
+func (e *Engine) processPartnerCommands(index int, commands Commands) {
+	if e.state.Mode != Playing || e.state.Paused || e.state.Demo || index >= len(e.state.Players) {
+		return
+	}
+	e.processPlayingCommands(e.state.Players[index], commands, 0)
+}
+
+// processPlayingCommands moves and fires a player's ship during gameplay
+func (e *Engine) processPlayingCommands(player *Player, commands Commands, flick float64) {
+	if player == nil || !player.IsActive() {
+		return
+	}
+	ship := player.Ship
+
+	// Handle movement
+	if commands.Aim {
+		e.aimShip(ship, commands.MoveAxis, flick)
+	} else {
+		left, right := commands.steering()
+		ship.ApplyInput(left, right, e.state.FixedDeltaTime)
+	}
+
+	// Handle shooting, holding fire while a new wave assembles
+	if commands.FirePressed && e.state.WaveIntro == 0 {
+		e.firePlayerBullets(player, ship.TryShoot(e.clock.Now()))
+	}
+	if commands.CycleWeapon {
+		ship.CycleWeapon()
+	}
+	if commands.SmartBomb {
+		e.triggerSmartBomb(player)
+	}
+}
+
+// aimShip glides the ship toward the screen position the axis points at
+func (e *Engine) aimShip(ship *PlayerShip, axis, flick float64) {
+	// A sharp flick dashes that way, and the dash carries the ship until
+	// it ends
+	if math.Abs(flick) >= headFlickThreshold {
+		ship.Dash(math.Copysign(1, flick))
+	}
+	if ship.IsDashing() {
+		return
+	}
+
+	// Map the axis (-1 to 1) to screen position
+	centerX := float64(e.state.ScreenWidth) / 2
+	maxOffset := float64(e.state.ScreenWidth)/2 - 30 // Keep ship on screen
+
+	// Wrapping ships use the full width and glide the short way round
+	if ship.ScreenWrap {
+		width := float64(e.state.ScreenWidth)
+		delta := math.Mod(centerX+axis*centerX-ship.Position.X+1.5*width, width) - width/2
+		ship.Position.X += delta * 0.7
+		ship.Confine(width)
+		return
+	}
+
+	// Smooth the movement slightly
+	targetX := centerX + (axis * maxOffset)
+	ship.Position.X = ship.Position.X*0.3 + targetX*0.7
+
+	// Keep within bounds
+	ship.Position.X = max(30, min(ship.Position.X, float64(e.state.ScreenWidth)-30))
+}
@@ -0,0 +1,53 @@
+package game
+
+// ContinueCountdown is how long the continue offer stays open, in seconds
+const ContinueCountdown = 10.0
+
+// continueCredits is how many continues each game starts with
+const continueCredits = 3
+
+// canContinue reports whether a lost game may be continued. Daily runs stay
+// comparable by never allowing continues.
+func (gs *GameState) canContinue() bool {
+	return gs.Credits > 0 && !gs.Demo && !gs.IsMultiplayer() && !gs.Options.Daily
+}
+
+// updateContinue runs down the continue offer, ending the game when it expires
+func (e *Engine) updateContinue(deltaTime float64) {
+	e.state.ContinueTimer -= deltaTime
+	if e.state.ContinueTimer <= 0 {
+		e.setMode(GameOver)
+	}
+}
+
+// continueGame spends a credit to resume the current wave with full lives
+// and half the score
+func (e *Engine) continueGame() {
+	gs := e.state
+	if gs.Mode != Continue || gs.Credits <= 0 {
+		return
+	}
+	gs.Credits--
+	e.setMode(Playing)
+	gs.GameEnded = false
+
+	for _, player := range gs.Players {
+		player.Score /= 2
+		player.Lives = gs.Options.StartingLives()
+		player.SmartBombs = SmartBombsPerLife
+		player.Status = PlayerActive
+		player.StatusTimer = 0
+		player.Ship = gs.NewPlayer(player.Index)
+		player.Ship.InvulnerableTimer = respawnInvulnerability
+	}
+
+	// Landed invaders would end the game again at once, so the wave restarts
+	if gs.InvadersLanded() {
+		gs.initializeWave()
+		e.startWave()
+	}
+
+	// Clear enemy fire for a fair restart
+	gs.Bullets = []*Bullet{}
+	gs.ResetCombo()
+}
@@ -0,0 +1,31 @@
+package game
+
+import "time"
+
+// DailyDate returns the UTC calendar date a daily run belongs to, as YYYY-MM-DD
+func DailyDate(now time.Time) string {
+	return now.UTC().Format("2006-01-02")
+}
+
+// DailySeed derives the wave seed for the given day, so every player on the
+// same date gets the same run
+func DailySeed(now time.Time) int64 {
+	year, month, day := now.UTC().Date()
+	return int64(year)*10000 + int64(month)*100 + int64(day)
+}
+
+// startDailyRun pins the seed and rules for today's daily challenge
+func (gs *GameState) startDailyRun(now time.Time) {
+	date := DailyDate(now)
+	if gs.DailyDate != date {
+		// A new day starts a fresh leaderboard
+		gs.DailyDate = date
+		gs.DailyHighScore = 0
+	}
+
+	// Everyone plays the same rules on the daily run
+	gs.Seed = DailySeed(now)
+	gs.Options.Difficulty = DifficultyNormal
+	gs.Options.Adaptive = false
+	gs.Adaptive = NewAdaptiveDifficulty(false)
+}
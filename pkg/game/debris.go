@@ -0,0 +1,30 @@
+package game
+
+import "math/rand"
+
+// Debris field layout: how many chunks drift through the mid-field each wave
+// and how far they can drift per second
+const (
+	debrisCount    = 3
+	debrisMaxSpeed = 30.0
+)
+
+// initializeDebris scatters a fresh debris field across the mid-field. The
+// layout comes from the run seed, so each wave opens different firing lanes
+// while daily runs stay identical for everyone.
+func (gs *GameState) initializeDebris() {
+	gs.Debris = []*Debris{}
+	if !gs.Options.Debris {
+		return
+	}
+
+	rng := rand.New(rand.NewSource(gs.Seed*31 + int64(gs.Wave)))
+	laneWidth := float64(gs.ScreenWidth) / debrisCount
+	for i := 0; i < debrisCount; i++ {
+		x := laneWidth*float64(i) + laneWidth*(0.2+0.6*rng.Float64())
+		y := gs.ScreenY(float64(gs.ScreenHeight)*0.5 + 40*(rng.Float64()-0.5))
+		velX := debrisMaxSpeed * (2*rng.Float64() - 1)
+		gs.Debris = append(gs.Debris, NewDebris(x, y, velX))
+	}
+	track(&gs.Entities, gs.Debris...)
+}
@@ -0,0 +1,632 @@
+package game
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// DefaultKeyframeInterval sends a full keyframe once a second at 20Hz
+const DefaultKeyframeInterval = 20
+
+// wireScale is the number of steps per game unit positions and velocities
+// are rounded to on the wire
+const wireScale = 16
+
+// EntityKind tells remote viewers what an entity is
+type EntityKind uint8
+
+const (
+	KindShip EntityKind = iota
+	KindInvader
+	KindBoss
+	KindUFO
+	KindBullet
+	KindBeam
+	KindPickup
+	KindDebris
+	KindScorePopup
+	KindExplosion
+	KindSplat
+)
+
+// WireEntity is an entity as streamed to remote viewers
+type WireEntity struct {
+	Kind     EntityKind
+	Position Vector2
+	Velocity Vector2
+	Variant  int // invader type, pickup kind, weapon, explosion kind, or 1 for player bullets
+	Value    int // health for the boss and debris, points for score popups, the frame for explosions, the far end of beams
+}
+
+// WireState is the part of the game state streamed to spectators and co-op
+// clients
+type WireState struct {
+	Tick     uint64
+	Mode     GameMode
+	Wave     int
+	Paused   bool
+	Scores   []int    // per player
+	Lives    []int    // per player
+	Barriers [][]bool // barrier blocks by column, as in GameState
+	Entities map[EntityID]WireEntity
+}
+
+// Field masks: which parts of the state a delta carries
+const (
+	wireMode uint64 = 1 << iota
+	wireWave
+	wirePaused
+	wireScores
+	wireLives
+	wireBarriers     // the whole barrier grid, on keyframes and when its shape changes
+	wireBarrierFlips // the barrier blocks destroyed or rebuilt since the last frame
+)
+
+const (
+	wireX uint64 = 1 << iota
+	wireY
+	wireVX
+	wireVY
+	wireVariant
+	wireValue
+	wireKind // sent when the entity first appears or changes kind
+)
+
+// frameKeyframe marks a frame holding the whole state rather than changes
+const frameKeyframe = 1
+
+// DeltaEncoder turns successive game states into a compact binary stream.
+// Each frame carries only the entity fields that changed since the previous
+// one, with a full keyframe every KeyframeInterval frames so viewers can join
+// or recover from a lost frame.
+type DeltaEncoder struct {
+	KeyframeInterval int
+
+	tick     uint64
+	last     *WireState
+	keyframe bool // send a keyframe next, whatever the interval
+}
+
+// NewDeltaEncoder creates an encoder sending a keyframe every interval frames
+func NewDeltaEncoder(interval int) *DeltaEncoder {
+	return &DeltaEncoder{KeyframeInterval: interval}
+}
+
+// ForceKeyframe makes the next frame a keyframe, for example when a viewer joins
+func (enc *DeltaEncoder) ForceKeyframe() {
+	enc.keyframe = true
+}
+
+// Encode returns the next frame of the stream for the given state
+func (enc *DeltaEncoder) Encode(gs *GameState) []byte {
+	current := captureWireState(gs, enc.tick)
+	keyframe := enc.last == nil || enc.keyframe ||
+		(enc.KeyframeInterval > 0 && enc.tick%uint64(enc.KeyframeInterval) == 0)
+
+	previous := &WireState{Entities: map[EntityID]WireEntity{}}
+	flags := byte(0)
+	if keyframe {
+		flags = frameKeyframe
+	} else {
+		previous = enc.last
+	}
+
+	data := []byte{flags}
+	data = binary.AppendUvarint(data, current.Tick)
+	data = appendGlobals(data, previous, current, keyframe)
+
+	// Removed entities, by ascending ID
+	var removed []EntityID
+	if !keyframe {
+		for id := range previous.Entities {
+			if _, ok := current.Entities[id]; !ok {
+				removed = append(removed, id)
+			}
+		}
+		slices.Sort(removed)
+	}
+	data = binary.AppendUvarint(data, uint64(len(removed)))
+	for _, id := range removed {
+		data = binary.AppendUvarint(data, uint64(id))
+	}
+
+	// New and changed entities, by ascending ID
+	var changed []EntityID
+	for id, entity := range current.Entities {
+		if old, ok := previous.Entities[id]; !ok || old != entity {
+			changed = append(changed, id)
+		}
+	}
+	slices.Sort(changed)
+	data = binary.AppendUvarint(data, uint64(len(changed)))
+	for _, id := range changed {
+		old, existed := previous.Entities[id]
+		data = binary.AppendUvarint(data, uint64(id))
+		data = appendEntity(data, old, current.Entities[id], existed)
+	}
+
+	enc.last = current
+	enc.keyframe = false
+	enc.tick++
+	return data
+}
+
+// appendGlobals writes the game-wide fields that changed
+func appendGlobals(data []byte, old, cur *WireState, keyframe bool) []byte {
+	var mask uint64
+	if keyframe || old.Mode != cur.Mode {
+		mask |= wireMode
+	}
+	if keyframe || old.Wave != cur.Wave {
+		mask |= wireWave
+	}
+	if keyframe || old.Paused != cur.Paused {
+		mask |= wirePaused
+	}
+	if keyframe || !slices.Equal(old.Scores, cur.Scores) {
+		mask |= wireScores
+	}
+	if keyframe || !slices.Equal(old.Lives, cur.Lives) {
+		mask |= wireLives
+	}
+	var flips []uint64
+	if keyframe || !sameGridShape(old.Barriers, cur.Barriers) {
+		mask |= wireBarriers
+	} else if flips = barrierFlips(old.Barriers, cur.Barriers); len(flips) > 0 {
+		mask |= wireBarrierFlips
+	}
+
+	data = binary.AppendUvarint(data, mask)
+	if mask&wireMode != 0 {
+		data = binary.AppendVarint(data, int64(cur.Mode))
+	}
+	if mask&wireWave != 0 {
+		data = binary.AppendVarint(data, int64(cur.Wave))
+	}
+	if mask&wirePaused != 0 {
+		data = binary.AppendUvarint(data, boolToWire(cur.Paused))
+	}
+	if mask&wireScores != 0 {
+		data = appendInts(data, cur.Scores)
+	}
+	if mask&wireLives != 0 {
+		data = appendInts(data, cur.Lives)
+	}
+	if mask&wireBarriers != 0 {
+		data = appendBarriers(data, cur.Barriers)
+	}
+	if mask&wireBarrierFlips != 0 {
+		data = binary.AppendUvarint(data, uint64(len(flips)))
+		for _, block := range flips {
+			data = binary.AppendUvarint(data, block)
+		}
+	}
+	return data
+}
+
+// appendBarriers writes the barrier grid's dimensions followed by its blocks,
+// column by column, packed eight to a byte
+func appendBarriers(data []byte, barriers [][]bool) []byte {
+	rows := 0
+	if len(barriers) > 0 {
+		rows = len(barriers[0])
+	}
+	data = binary.AppendUvarint(data, uint64(len(barriers)))
+	data = binary.AppendUvarint(data, uint64(rows))
+	var packed byte
+	bit := 0
+	for _, column := range barriers {
+		for _, solid := range column {
+			if solid {
+				packed |= 1 << bit
+			}
+			if bit++; bit == 8 {
+				data = append(data, packed)
+				packed, bit = 0, 0
+			}
+		}
+	}
+	if bit > 0 {
+		data = append(data, packed)
+	}
+	return data
+}
+
+// barrierFlips lists the blocks that differ between two grids of the same
+// shape, each as its index counting column by column
+func barrierFlips(old, cur [][]bool) []uint64 {
+	var flips []uint64
+	block := uint64(0)
+	for x, column := range cur {
+		for y, solid := range column {
+			if solid != old[x][y] {
+				flips = append(flips, block)
+			}
+			block++
+		}
+	}
+	return flips
+}
+
+// appendEntity writes the fields of an entity that differ from its last state
+func appendEntity(data []byte, old, cur WireEntity, existed bool) []byte {
+	var mask uint64
+	if !existed || old.Kind != cur.Kind {
+		mask |= wireKind
+	}
+	if !existed || old.Position.X != cur.Position.X {
+		mask |= wireX
+	}
+	if !existed || old.Position.Y != cur.Position.Y {
+		mask |= wireY
+	}
+	if !existed || old.Velocity.X != cur.Velocity.X {
+		mask |= wireVX
+	}
+	if !existed || old.Velocity.Y != cur.Velocity.Y {
+		mask |= wireVY
+	}
+	if !existed || old.Variant != cur.Variant {
+		mask |= wireVariant
+	}
+	if !existed || old.Value != cur.Value {
+		mask |= wireValue
+	}
+
+	data = binary.AppendUvarint(data, mask)
+	if mask&wireKind != 0 {
+		data = binary.AppendUvarint(data, uint64(cur.Kind))
+	}
+	for _, field := range []struct {
+		bit   uint64
+		value float64
+	}{
+		{wireX, cur.Position.X},
+		{wireY, cur.Position.Y},
+		{wireVX, cur.Velocity.X},
+		{wireVY, cur.Velocity.Y},
+	} {
+		if mask&field.bit != 0 {
+			data = binary.AppendVarint(data, int64(math.Round(field.value*wireScale)))
+		}
+	}
+	if mask&wireVariant != 0 {
+		data = binary.AppendVarint(data, int64(cur.Variant))
+	}
+	if mask&wireValue != 0 {
+		data = binary.AppendVarint(data, int64(cur.Value))
+	}
+	return data
+}
+
+// appendInts writes a length-prefixed list of integers
+func appendInts(data []byte, values []int) []byte {
+	data = binary.AppendUvarint(data, uint64(len(values)))
+	for _, value := range values {
+		data = binary.AppendVarint(data, int64(value))
+	}
+	return data
+}
+
+// boolToWire encodes a flag as 0 or 1
+func boolToWire(b bool) uint64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// DeltaDecoder rebuilds the streamed state from an encoder's frames
+type DeltaDecoder struct {
+	state *WireState
+}
+
+// NewDeltaDecoder creates a decoder waiting for its first keyframe
+func NewDeltaDecoder() *DeltaDecoder {
+	return &DeltaDecoder{}
+}
+
+// Decode applies the next frame of the stream and returns the resulting
+// state. A delta that doesn't follow on from the last frame is rejected, and
+// the decoder waits for the next keyframe.
+func (dec *DeltaDecoder) Decode(data []byte) (*WireState, error) {
+	r := wireReader{data: data}
+	flags := r.byte()
+	tick := r.uvarint()
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	keyframe := flags&frameKeyframe != 0
+	if !keyframe {
+		if dec.state == nil {
+			return nil, fmt.Errorf("delta for tick %d before any keyframe", tick)
+		}
+		if tick != dec.state.Tick+1 {
+			missed := dec.state.Tick
+			dec.state = nil
+			return nil, fmt.Errorf("delta for tick %d after tick %d; waiting for a keyframe", tick, missed)
+		}
+	}
+
+	state := &WireState{Entities: map[EntityID]WireEntity{}}
+	if !keyframe {
+		state = dec.state.clone()
+	}
+	state.Tick = tick
+	r.readGlobals(state)
+
+	for range r.count() {
+		delete(state.Entities, EntityID(r.uvarint()))
+	}
+	for range r.count() {
+		id := EntityID(r.uvarint())
+		state.Entities[id] = r.readEntity(state.Entities[id])
+	}
+
+	if r.err != nil {
+		dec.state = nil
+		return nil, r.err
+	}
+	if len(r.data) > 0 {
+		dec.state = nil
+		return nil, fmt.Errorf("%d unexpected bytes after frame", len(r.data))
+	}
+	dec.state = state
+	return state.clone(), nil
+}
+
+// clone copies the state so the decoder's copy is never shared
+func (s *WireState) clone() *WireState {
+	c := *s
+	c.Scores = slices.Clone(s.Scores)
+	c.Lives = slices.Clone(s.Lives)
+	c.Barriers = cloneBarriers(s.Barriers)
+	c.Entities = make(map[EntityID]WireEntity, len(s.Entities))
+	for id, entity := range s.Entities {
+		c.Entities[id] = entity
+	}
+	return &c
+}
+
+// wireReader reads values from a frame, remembering the first error
+type wireReader struct {
+	data []byte
+	err  error
+}
+
+func (r *wireReader) byte() byte {
+	if r.err != nil {
+		return 0
+	}
+	if len(r.data) == 0 {
+		r.err = fmt.Errorf("frame is truncated")
+		return 0
+	}
+	b := r.data[0]
+	r.data = r.data[1:]
+	return b
+}
+
+func (r *wireReader) uvarint() uint64 {
+	if r.err != nil {
+		return 0
+	}
+	value, n := binary.Uvarint(r.data)
+	if n <= 0 {
+		r.err = fmt.Errorf("frame is truncated or malformed")
+		return 0
+	}
+	r.data = r.data[n:]
+	return value
+}
+
+func (r *wireReader) varint() int64 {
+	if r.err != nil {
+		return 0
+	}
+	value, n := binary.Varint(r.data)
+	if n <= 0 {
+		r.err = fmt.Errorf("frame is truncated or malformed")
+		return 0
+	}
+	r.data = r.data[n:]
+	return value
+}
+
+// count reads the length of a list, which can't exceed the bytes left since
+// every value takes at least one
+func (r *wireReader) count() uint64 {
+	count := r.uvarint()
+	if count > uint64(len(r.data)) {
+		r.err = fmt.Errorf("frame is truncated or malformed")
+		return 0
+	}
+	return count
+}
+
+func (r *wireReader) ints() []int {
+	values := make([]int, r.count())
+	for i := range values {
+		values[i] = int(r.varint())
+	}
+	return values
+}
+
+// readGlobals applies the game-wide fields present in the frame
+func (r *wireReader) readGlobals(state *WireState) {
+	mask := r.uvarint()
+	if mask&wireMode != 0 {
+		state.Mode = GameMode(r.varint())
+	}
+	if mask&wireWave != 0 {
+		state.Wave = int(r.varint())
+	}
+	if mask&wirePaused != 0 {
+		state.Paused = r.uvarint() != 0
+	}
+	if mask&wireScores != 0 {
+		state.Scores = r.ints()
+	}
+	if mask&wireLives != 0 {
+		state.Lives = r.ints()
+	}
+	if mask&wireBarriers != 0 {
+		state.Barriers = r.barriers()
+	}
+	if mask&wireBarrierFlips != 0 {
+		r.flipBarriers(state.Barriers)
+	}
+}
+
+// barriers reads a whole barrier grid
+func (r *wireReader) barriers() [][]bool {
+	columns, rows := r.uvarint(), r.uvarint()
+	if r.err != nil || columns == 0 {
+		return nil
+	}
+	if (rows == 0 && columns > 0) || (rows > 0 && columns > uint64(len(r.data))*8/rows) {
+		r.err = fmt.Errorf("frame is truncated or malformed")
+		return nil
+	}
+	barriers := make([][]bool, columns)
+	var packed byte
+	bit := 0
+	for x := range barriers {
+		barriers[x] = make([]bool, rows)
+		for y := range barriers[x] {
+			if bit == 0 {
+				packed = r.byte()
+			}
+			barriers[x][y] = packed&(1<<bit) != 0
+			bit = (bit + 1) % 8
+		}
+	}
+	return barriers
+}
+
+// flipBarriers toggles the barrier blocks listed in the frame
+func (r *wireReader) flipBarriers(barriers [][]bool) {
+	for range r.count() {
+		block := r.uvarint()
+		if r.err != nil {
+			return
+		}
+		if len(barriers) == 0 || block >= uint64(len(barriers)*len(barriers[0])) {
+			r.err = fmt.Errorf("barrier block %d is outside the grid", block)
+			return
+		}
+		rows := uint64(len(barriers[0]))
+		barriers[block/rows][block%rows] = !barriers[block/rows][block%rows]
+	}
+}
+
+// readEntity applies the entity fields present in the frame
+func (r *wireReader) readEntity(entity WireEntity) WireEntity {
+	mask := r.uvarint()
+	if mask&wireKind != 0 {
+		entity.Kind = EntityKind(r.uvarint())
+	}
+	for _, field := range []struct {
+		bit   uint64
+		value *float64
+	}{
+		{wireX, &entity.Position.X},
+		{wireY, &entity.Position.Y},
+		{wireVX, &entity.Velocity.X},
+		{wireVY, &entity.Velocity.Y},
+	} {
+		if mask&field.bit != 0 {
+			*field.value = float64(r.varint()) / wireScale
+		}
+	}
+	if mask&wireVariant != 0 {
+		entity.Variant = int(r.varint())
+	}
+	if mask&wireValue != 0 {
+		entity.Value = int(r.varint())
+	}
+	return entity
+}
+
+// captureWireState reduces the game state to what is streamed, rounding
+// positions and velocities to the wire's precision
+func captureWireState(gs *GameState, tick uint64) *WireState {
+	state := &WireState{
+		Tick:     tick,
+		Mode:     gs.Mode,
+		Wave:     gs.Wave,
+		Paused:   gs.Paused,
+		Barriers: cloneBarriers(gs.Barriers),
+		Entities: map[EntityID]WireEntity{},
+	}
+	for _, player := range gs.Players {
+		state.Scores = append(state.Scores, player.Score)
+		state.Lives = append(state.Lives, player.Lives)
+	}
+
+	add := func(id EntityID, kind EntityKind, position, velocity Vector2, variant, value int) {
+		state.Entities[id] = WireEntity{
+			Kind:     kind,
+			Position: Vector2{X: toWire(position.X), Y: toWire(position.Y)},
+			Velocity: Vector2{X: toWire(velocity.X), Y: toWire(velocity.Y)},
+			Variant:  variant,
+			Value:    value,
+		}
+	}
+	for _, player := range gs.Players {
+		if ship := player.Ship; ship != nil && ship.Alive {
+			add(ship.ID, KindShip, ship.Position, ship.Velocity, int(ship.Weapon), 0)
+		}
+	}
+	for _, invader := range gs.Invaders {
+		if invader.Alive {
+			add(invader.ID, KindInvader, invader.Position, Vector2{}, int(invader.Type), 0)
+		}
+	}
+	for _, invader := range gs.DyingInvaders {
+		add(invader.ID, KindSplat, invader.Position, Vector2{}, int(invader.Type), 0)
+	}
+	if boss := gs.Boss; boss != nil && boss.Alive {
+		add(boss.ID, KindBoss, boss.Position, boss.Velocity, 0, boss.Health)
+	}
+	if ufo := gs.UFO; ufo != nil && ufo.Alive {
+		add(ufo.ID, KindUFO, ufo.Position, ufo.Velocity, 0, 0)
+	}
+	for _, bullet := range gs.Bullets {
+		if bullet.Alive {
+			variant := 0
+			if bullet.IsPlayerBullet {
+				variant = 1
+			}
+			add(bullet.ID, KindBullet, bullet.Position, bullet.Velocity, variant, 0)
+		}
+	}
+	for _, beam := range gs.Beams {
+		add(beam.ID, KindBeam, Vector2{X: beam.X, Y: beam.StartY}, Vector2{}, 0, int(beam.EndY))
+	}
+	for _, pickup := range gs.Pickups {
+		if pickup.Alive {
+			add(pickup.ID, KindPickup, pickup.Position, pickup.Velocity, int(pickup.Kind), 0)
+		}
+	}
+	for _, debris := range gs.Debris {
+		if debris.Alive {
+			add(debris.ID, KindDebris, debris.Position, debris.Velocity, 0, debris.Health)
+		}
+	}
+	for _, popup := range gs.ScorePopups {
+		add(popup.ID, KindScorePopup, popup.Position, Vector2{}, 0, popup.Points)
+	}
+	for _, explosion := range gs.Explosions {
+		add(explosion.ID, KindExplosion, explosion.Position, Vector2{}, int(explosion.Kind), explosion.Anim.Frame())
+	}
+	return state
+}
+
+// toWire rounds a value to the precision it is streamed with
+func toWire(v float64) float64 {
+	return math.Round(v*wireScale) / wireScale
+}
@@ -0,0 +1,189 @@
+package game
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// loadGoldenReplay reads the input log of one of the golden replays
+func loadGoldenReplay(t *testing.T, name string) *Replay {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "replays", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var golden goldenReplay
+	if err := json.Unmarshal(data, &golden); err != nil {
+		t.Fatalf("failed to parse %s: %v", name, err)
+	}
+	return &golden.Replay
+}
+
+// wireChanges counts the kinds of change a stream has carried
+type wireChanges struct {
+	appeared, disappeared, changedKind int
+	mode, wave, scores, barriers       int
+}
+
+func (c *wireChanges) record(old, cur *WireState) {
+	for id, entity := range cur.Entities {
+		if before, ok := old.Entities[id]; !ok {
+			c.appeared++
+		} else if before.Kind != entity.Kind {
+			c.changedKind++
+		}
+	}
+	for id := range old.Entities {
+		if _, ok := cur.Entities[id]; !ok {
+			c.disappeared++
+		}
+	}
+	if old.Mode != cur.Mode {
+		c.mode++
+	}
+	if old.Wave != cur.Wave {
+		c.wave++
+	}
+	if !reflect.DeepEqual(old.Scores, cur.Scores) {
+		c.scores++
+	}
+	if !reflect.DeepEqual(old.Barriers, cur.Barriers) {
+		c.barriers++
+	}
+}
+
+func TestDeltaRoundTripsRecordedRuns(t *testing.T) {
+	var changes wireChanges
+	for _, name := range []string{"classic.json", "coop_mirror.json", "hard_heat_debris.json"} {
+		replay := loadGoldenReplay(t, name)
+		e := NewEngine()
+		e.state.Options = replay.Options
+		e.StartNewGame()
+
+		enc := NewDeltaEncoder(DefaultKeyframeInterval)
+		dec := NewDeltaDecoder()
+		var last *WireState
+		tick := uint64(0)
+		check := func() {
+			want := captureWireState(e.GetState(), tick)
+			got, err := dec.Decode(enc.Encode(e.GetState()))
+			if err != nil {
+				t.Fatalf("%s: tick %d: %v", name, tick, err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("%s: tick %d decoded as %+v, want %+v", name, tick, got, want)
+			}
+			if last != nil {
+				changes.record(last, want)
+			}
+			last = want
+			tick++
+		}
+
+		check()
+		for _, run := range replay.Inputs {
+			for range run.Ticks {
+				e.Step(run.Input)
+				check()
+			}
+		}
+
+		// Then clear the wave and carry on into the next one
+		e.state.Invaders = nil
+		e.state.Boss = nil
+		for range int((waveClearDuration + WaveIntroDuration) / e.state.FixedDeltaTime) {
+			e.Step(InputFrame{})
+			check()
+		}
+	}
+
+	// The runs must exercise every part of the format to prove anything
+	for what, count := range map[string]int{
+		"entities appearing":     changes.appeared,
+		"entities disappearing":  changes.disappeared,
+		"entities changing kind": changes.changedKind,
+		"mode changes":           changes.mode,
+		"wave changes":           changes.wave,
+		"score changes":          changes.scores,
+		"barrier changes":        changes.barriers,
+	} {
+		if count == 0 {
+			t.Errorf("recorded runs have no %s", what)
+		}
+	}
+}
+
+func TestDeltaWaitsForKeyframeAfterMissedFrame(t *testing.T) {
+	e := steadyStateEngine()
+	enc := NewDeltaEncoder(DefaultKeyframeInterval)
+	dec := NewDeltaDecoder()
+
+	step := func() []byte {
+		e.Step(InputFrame{Fire: true})
+		return enc.Encode(e.GetState())
+	}
+	if _, err := dec.Decode(step()); err != nil {
+		t.Fatal(err)
+	}
+	step() // lost in transit
+	if _, err := dec.Decode(step()); err == nil {
+		t.Fatal("delta after a missed frame was accepted")
+	}
+	if _, err := dec.Decode(step()); err == nil {
+		t.Fatal("delta accepted before the next keyframe")
+	}
+
+	enc.ForceKeyframe()
+	frame := step()
+	got, err := dec.Decode(frame)
+	if err != nil {
+		t.Fatalf("keyframe rejected: %v", err)
+	}
+	if want := captureWireState(e.GetState(), got.Tick); !reflect.DeepEqual(got, want) {
+		t.Errorf("keyframe decoded as %+v, want %+v", got, want)
+	}
+}
+
+func TestDeltaRejectsMalformedFrames(t *testing.T) {
+	e := steadyStateEngine()
+	frame := NewDeltaEncoder(0).Encode(e.GetState())
+	for _, data := range [][]byte{nil, frame[:len(frame)/2], append(frame, 0)} {
+		if _, err := NewDeltaDecoder().Decode(data); err == nil {
+			t.Errorf("decoded a malformed frame of %d bytes", len(data))
+		}
+	}
+}
+
+func TestDeltaResendsKindWhenSplatReusesID(t *testing.T) {
+	e := steadyStateEngine()
+	gs := e.GetState()
+	enc := NewDeltaEncoder(0)
+	dec := NewDeltaDecoder()
+	if _, err := dec.Decode(enc.Encode(gs)); err != nil {
+		t.Fatal(err)
+	}
+
+	var invader *Invader
+	for _, candidate := range gs.Invaders {
+		if candidate.Alive {
+			invader = candidate
+			break
+		}
+	}
+	if invader == nil {
+		t.Fatal("no live invader to destroy")
+	}
+	invader.Alive = false
+	gs.DyingInvaders = append(gs.DyingInvaders, invader)
+
+	state, err := dec.Decode(enc.Encode(gs))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := state.Entities[invader.ID].Kind; got != KindSplat {
+		t.Errorf("destroyed invader decoded as kind %d, want KindSplat", got)
+	}
+}
@@ -0,0 +1,99 @@
+package game
+
+import "math"
+
+// Attract-mode demo timing (in seconds)
+const (
+	demoDelay    = 8.0  // idle time on the title screen before the demo starts
+	demoDuration = 30.0 // longest a demo game runs before returning to the title
+)
+
+// Demo bot tuning (in pixels)
+const (
+	demoAimTolerance   = 8.0   // fire when an invader is this close to the ship's column
+	demoDodgeWidth     = 24.0  // enemy bullets this close horizontally are threats
+	demoDodgeLookahead = 140.0 // how far ahead of the ship the bot watches for bullets
+)
+
+// startDemo begins a bot-controlled demo game with classic rules
+func (e *Engine) startDemo() {
+	e.applyPendingRules() // so they aren't applied over the demo's
+	e.demoOptions = e.state.Options
+	e.state.Options = GameOptions{Difficulty: DifficultyNormal}
+	e.StartNewGame()
+	e.state.Demo = true
+	e.demoTimer = demoDuration
+}
+
+// stopDemo ends the demo and returns to the title screen, which restores the
+// player's chosen options
+func (e *Engine) stopDemo() {
+	e.setMode(AttractMode)
+}
+
+// updateDemo drives the demo bot and ends the demo when its time runs out
+func (e *Engine) updateDemo(deltaTime float64) {
+	e.demoTimer -= deltaTime
+	if e.demoTimer <= 0 {
+		e.stopDemo()
+		return
+	}
+
+	player := e.state.PrimaryPlayer()
+	if player == nil || !player.IsActive() {
+		return
+	}
+	e.processPlayingCommands(player, e.demoCommands(player.Ship), 0)
+}
+
+// demoCommands picks the bot's moves: dodge incoming fire, otherwise line up
+// under the nearest invader and shoot
+func (e *Engine) demoCommands(ship *PlayerShip) Commands {
+	commands := Commands{}
+	x := ship.Position.X
+
+	// Step away from the closest bullet heading for the ship
+	for _, bullet := range e.state.Bullets {
+		if !bullet.Alive || bullet.IsPlayerBullet {
+			continue
+		}
+		ahead := (ship.Position.Y - bullet.Position.Y) * e.state.Orientation().Advance()
+		if ahead > 0 && ahead < demoDodgeLookahead && math.Abs(bullet.Position.X-x) < demoDodgeWidth {
+			if bullet.Position.X > x || x > float64(e.state.ScreenWidth)-40 {
+				commands.MoveAxis = -1
+			} else {
+				commands.MoveAxis = 1
+			}
+			return commands
+		}
+	}
+
+	// Chase the invader nearest the ship's column
+	targetX, found := 0.0, false
+	for _, invader := range e.state.Invaders {
+		if invader.Alive && (!found || math.Abs(invader.Position.X-x) < math.Abs(targetX-x)) {
+			targetX, found = invader.Position.X, true
+		}
+	}
+	if boss := e.state.Boss; boss != nil && boss.Alive {
+		for _, point := range boss.WeakPoints {
+			if !point.Destroyed() {
+				targetX, found = point.Bounds.X+point.Bounds.Width/2, true
+				break
+			}
+		}
+	}
+	if !found {
+		return commands
+	}
+
+	switch {
+	case targetX < x-demoAimTolerance:
+		commands.MoveAxis = -1
+	case targetX > x+demoAimTolerance:
+		commands.MoveAxis = 1
+	default:
+		commands.FirePressed = true
+	}
+	return commands
+}
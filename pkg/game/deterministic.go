@@ -0,0 +1,150 @@
+//go:build deterministic
+
+package game
+
+import "math"
+
+// Deterministic reports whether the engine is built for lockstep play, where
+// peers stepping the same inputs must agree exactly. Build with
+// -tags deterministic to enable it.
+//
+// Basic float64 arithmetic is exact IEEE rounding on every platform, but the
+// compiler may fuse a*b+c into one instruction on some architectures, and the
+// trigonometry in the math package differs in its last bit between assembly
+// and pure Go implementations. Either way two peers can drift apart by a
+// rounding error that then grows. The integration steps that accumulate a
+// product wrap it in float64(), which the language guarantees rounds rather
+// than fuses, so those steps come out the same everywhere. After each step,
+// deterministic builds snap positions, velocities, formation slots and the
+// formation's drop to a 1/gridScale grid, and every simulation timer to
+// a 1/timerScale grid, so any difference left over from the trigonometry is
+// rounded away before it can compound. Values stay float64 snapped to a
+// grid rather than integers: the arithmetic within a step is still float64,
+// and a value landing within a last-bit error of halfway between two grid
+// steps could still round apart.
+// The simulation otherwise only reads the wall clock to pick a seed, and
+// deterministic builds use a fixed one instead; peers should still agree on a
+// seed with SetSeed, and start daily runs on the same UTC day.
+const Deterministic = true
+
+// gridScale is the number of grid steps per game unit. A power of two
+// keeps every grid value exact in a float64.
+const gridScale = 256
+
+// timerScale is the number of timer steps per second, a whole number per
+// 20Hz tick so snapped timers never drift from the ticks they count
+const timerScale = 1280
+
+// deterministicSeed is the seed used when none was set
+const deterministicSeed = 1
+
+// newSeed returns the seed for a game played without a fixed seed
+func newSeed() int64 {
+	return deterministicSeed
+}
+
+// toGrid rounds a value to the nearest grid step
+func toGrid(v float64) float64 {
+	return math.Round(v*gridScale) / gridScale
+}
+
+// toTimerStep rounds a time in seconds to the nearest timer step
+func toTimerStep(t float64) float64 {
+	return math.Round(t*timerScale) / timerScale
+}
+
+// snapTimers rounds each timer to the nearest timer step
+func snapTimers(timers ...*float64) {
+	for _, t := range timers {
+		*t = toTimerStep(*t)
+	}
+}
+
+// snapVector rounds both components of a vector to the grid
+func snapVector(v *Vector2) {
+	v.X = toGrid(v.X)
+	v.Y = toGrid(v.Y)
+}
+
+// snapBounds rounds the corner of a bounding box to the grid
+func snapBounds(b *Bounds) {
+	b.X = toGrid(b.X)
+	b.Y = toGrid(b.Y)
+}
+
+// snapToGrid rounds the engine's timers and the game state to the grid
+func (e *Engine) snapToGrid() {
+	snapTimers(&e.invaderMoveTimer, &e.invaderDropTimer, &e.ufoTimer, &e.diveTimer, &e.aimedShotTimer)
+	e.state.snapToGrid()
+}
+
+// snapToGrid rounds every entity's position, velocity and timers, and the
+// game's own timers, to the grid
+func (gs *GameState) snapToGrid() {
+	gs.FormationDrop = toGrid(gs.FormationDrop)
+	snapTimers(&gs.BombFlash, &gs.ComboTimer, &gs.ChallengeTime, &gs.WaveClearTimer, &gs.WaveIntro,
+		&gs.ContinueTimer, &gs.Adaptive.WaveTime)
+
+	for _, player := range gs.Players {
+		snapTimers(&player.StatusTimer)
+		if ship := player.Ship; ship != nil {
+			snapVector(&ship.Position)
+			snapVector(&ship.Velocity)
+			snapBounds(&ship.Bounds)
+			snapTimers(&ship.ChargeTimer, &ship.InvulnerableTimer, &ship.ShieldTimer, &ship.TripleShotTimer,
+				&ship.DashTimer, &ship.DashCooldownTimer, &ship.tapTimer, &ship.Anim.Timer)
+		}
+	}
+	for _, invader := range gs.Invaders {
+		snapVector(&invader.Position)
+		snapVector(&invader.Home)
+		snapBounds(&invader.Bounds)
+		invader.DiveSpeed = toGrid(invader.DiveSpeed)
+		snapTimers(&invader.Anim.Timer)
+	}
+	for _, invader := range gs.DyingInvaders {
+		snapTimers(&invader.Splat)
+	}
+	for _, bullet := range gs.Bullets {
+		snapVector(&bullet.Position)
+		snapVector(&bullet.Velocity)
+		snapBounds(&bullet.Bounds)
+	}
+	if ufo := gs.UFO; ufo != nil {
+		snapVector(&ufo.Position)
+		snapVector(&ufo.Velocity)
+		snapBounds(&ufo.Bounds)
+		snapTimers(&ufo.Age, &ufo.Anim.Timer)
+	}
+	if boss := gs.Boss; boss != nil {
+		snapVector(&boss.Position)
+		snapVector(&boss.Velocity)
+		snapBounds(&boss.Bounds)
+		snapTimers(&boss.PatternTimer, &boss.ShotTimer, &boss.HitTimer, &boss.Anim.Timer)
+		for _, point := range boss.WeakPoints {
+			snapBounds(&point.Bounds)
+			snapTimers(&point.HitTimer)
+		}
+	}
+	for _, pickup := range gs.Pickups {
+		snapVector(&pickup.Position)
+		snapVector(&pickup.Velocity)
+		snapBounds(&pickup.Bounds)
+		snapTimers(&pickup.Anim.Timer)
+	}
+	for _, debris := range gs.Debris {
+		snapVector(&debris.Position)
+		snapVector(&debris.Velocity)
+		snapBounds(&debris.Bounds)
+	}
+	for _, beam := range gs.Beams {
+		snapTimers(&beam.Timer)
+	}
+	for _, popup := range gs.ScorePopups {
+		snapVector(&popup.Position)
+		snapTimers(&popup.Timer)
+	}
+	for _, explosion := range gs.Explosions {
+		snapTimers(&explosion.Anim.Timer)
+	}
+}
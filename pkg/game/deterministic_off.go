@@ -0,0 +1,17 @@
+//go:build !deterministic
+
+package game
+
+import "time"
+
+// Deterministic reports whether the engine is built for lockstep play.
+// Build with -tags deterministic to enable it.
+const Deterministic = false
+
+// newSeed returns the seed for a game played without a fixed seed
+func newSeed() int64 {
+	return time.Now().UnixNano()
+}
+
+// snapToGrid does nothing outside deterministic builds
+func (e *Engine) snapToGrid() {}
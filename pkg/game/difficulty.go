@@ -0,0 +1,128 @@
+package game
+
+// AttackType represents the kind of shot an invader fires
+type AttackType int
+
+const (
+	AttackSingle AttackType = iota // One bullet straight down
+	AttackHoming                   // Slow bullet that curves toward the player
+	AttackSpread                   // Three-way fan of bullets
+)
+
+// String returns the string representation of the attack type
+func (a AttackType) String() string {
+	switch a {
+	case AttackSingle:
+		return "Single"
+	case AttackHoming:
+		return "Homing"
+	case AttackSpread:
+		return "Spread"
+	default:
+		return "Unknown"
+	}
+}
+
+// WaveDifficulty describes how invaders attack from a given wave onward
+type WaveDifficulty struct {
+	FromWave int
+	Attacks  map[InvaderType]AttackType
+}
+
+// difficultyTable lists wave difficulties in ascending FromWave order
+var difficultyTable = []WaveDifficulty{
+	{
+		FromWave: 1,
+		Attacks: map[InvaderType]AttackType{
+			InvaderTypeSmall:  AttackHoming,
+			InvaderTypeMedium: AttackSingle,
+			InvaderTypeLarge:  AttackSingle,
+		},
+	},
+	{
+		FromWave: 4,
+		Attacks: map[InvaderType]AttackType{
+			InvaderTypeSmall:  AttackHoming,
+			InvaderTypeMedium: AttackSpread,
+			InvaderTypeLarge:  AttackSingle,
+		},
+	},
+}
+
+// DifficultyForWave returns the difficulty entry that applies to the wave
+func DifficultyForWave(wave int) WaveDifficulty {
+	current := difficultyTable[0]
+	for _, entry := range difficultyTable {
+		if wave >= entry.FromWave {
+			current = entry
+		}
+	}
+	return current
+}
+
+// AttackFor returns the attack type used by the given invader type
+func (d WaveDifficulty) AttackFor(invaderType InvaderType) AttackType {
+	if attack, ok := d.Attacks[invaderType]; ok {
+		return attack
+	}
+	return AttackSingle
+}
+
+// DifficultyLevel represents a selectable difficulty preset
+type DifficultyLevel int
+
+const (
+	DifficultyEasy DifficultyLevel = iota
+	DifficultyNormal
+	DifficultyHard
+	DifficultyInsane
+	difficultyLevelCount
+)
+
+// String returns the display name of the difficulty level
+func (d DifficultyLevel) String() string {
+	switch d {
+	case DifficultyEasy:
+		return "EASY"
+	case DifficultyNormal:
+		return "NORMAL"
+	case DifficultyHard:
+		return "HARD"
+	case DifficultyInsane:
+		return "INSANE"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// DifficultyPreset holds the tuning values scaled by a difficulty level
+type DifficultyPreset struct {
+	InvaderSpeed  float64 // formation speed multiplier
+	ShootChance   float64 // invader shoot chance multiplier
+	BulletSpeed   float64 // enemy bullet speed multiplier
+	StartingLives int
+}
+
+// Preset returns the tuning values for the difficulty level
+func (d DifficultyLevel) Preset() DifficultyPreset {
+	switch d {
+	case DifficultyEasy:
+		return DifficultyPreset{InvaderSpeed: 0.75, ShootChance: 0.5, BulletSpeed: 0.8, StartingLives: 5}
+	case DifficultyHard:
+		return DifficultyPreset{InvaderSpeed: 1.25, ShootChance: 1.5, BulletSpeed: 1.2, StartingLives: 3}
+	case DifficultyInsane:
+		return DifficultyPreset{InvaderSpeed: 1.6, ShootChance: 2.5, BulletSpeed: 1.5, StartingLives: 2}
+	default:
+		return DifficultyPreset{InvaderSpeed: 1.0, ShootChance: 1.0, BulletSpeed: 1.0, StartingLives: 3}
+	}
+}
+
+// Next returns the following difficulty level, wrapping around
+func (d DifficultyLevel) Next() DifficultyLevel {
+	return (d + 1) % difficultyLevelCount
+}
+
+// Previous returns the preceding difficulty level, wrapping around
+func (d DifficultyLevel) Previous() DifficultyLevel {
+	return (d + difficultyLevelCount - 1) % difficultyLevelCount
+}
@@ -0,0 +1,10 @@
+// Package game is the Bobn simulation: the invaders, ships, bullets, scoring
+// and game flow, with no knowledge of how the game is drawn or controlled.
+//
+// A front-end creates an Engine, feeds it player input and elapsed time, and
+// draws the GameState it exposes. Headless callers step it one tick at a
+// time with Step instead. The engine runs on a fixed timestep against
+// its own game clock and seeds every random source from the game seed, so the
+// same seed and inputs always play out the same way. Everything a front-end
+// needs is described by the Simulation interface.
+package game
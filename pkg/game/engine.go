@@ -0,0 +1,1413 @@
+package game
+
+import (
+	"math"
+	"slices"
+	"sync/atomic"
+)
+
+// SmartBombFlashDuration is how long the smart bomb screen flash lasts, in seconds
+const SmartBombFlashDuration = 0.4
+
+// kamikazeTrigger is the remaining invader count at which kamikazes charge
+const kamikazeTrigger = 10
+
+// formationEdgeMargin is how close the formation's edge gets to the side of
+// the screen before it drops and turns around
+const formationEdgeMargin = 8
+
+// aimedShotWave is the first wave where invaders fire aimed shots at the player
+const aimedShotWave = 3
+
+// headFlickThreshold is how far the head must move between camera frames,
+// in analog units, to count as a flick that dashes the ship
+const headFlickThreshold = 0.4
+
+// respawnInvulnerability is how long a respawned ship ignores enemy fire, in seconds
+const respawnInvulnerability = 2.5
+
+// Bonus pickups: chance an invader drops one, its fall speed and its value
+const (
+	pickupDropChance = 0.08
+	pickupFallSpeed  = 90.0
+	pickupPoints     = 250
+)
+
+// beamBossDamage is how much damage a charged beam deals to the boss
+const beamBossDamage = 5
+
+// Power-up pickups: share of drops that are each power-up
+const (
+	shieldDropShare     = 0.25
+	tripleShotDropShare = 0.2
+)
+
+// How long the power-ups last, in seconds
+const (
+	ShieldDuration     = 10.0
+	TripleShotDuration = 12.0
+)
+
+// Engine handles the core game loop and logic
+type Engine struct {
+	state           *GameState
+	clock           Clock   // game time, advanced by the fixed timestep
+	gameStartTime   float64 // game time the current game started
+	invaderMoveTimer float64
+	invaderDropTimer float64
+
+	// UFO scheduling in simulation time
+	ufoRNG   *RunRNG
+	ufoTimer float64 // seconds until the next UFO
+
+	// Bonus pickups dropped by destroyed invaders
+	dropRNG *RunRNG
+
+	// Picks which column shooters fire each frame
+	shotRNG *RunRNG
+
+	// Scatters cosmetic particles
+	particleRNG *RunRNG
+
+	// Dive-bombing state
+	diveTimer        float64
+	diveCounter      int
+	detachedInvaders []*Invader
+
+	// Scratch space for picking column shooters, reused every tick
+	shooters      []*Invader
+	shooterColumn map[float64]int
+
+	// Seconds since the last aimed shot
+	aimedShotTimer float64
+
+	// Previous aimed move axis, for spotting head flicks
+	lastAimX float64
+
+	// How invader kills are scored
+	scoring ScoringPolicy
+
+	// Barrier layout the next game starts with
+	barrierConfig BarrierConfig
+
+	// Rule settings changed during a game, held until the next one starts
+	rulesPending bool
+
+	// Developer cheats, compiled in with the cheats build tag
+	cheats cheatState
+
+	// Spatial hash for collision queries, rebuilt each step
+	collisions *CollisionSystem
+
+	// Timing and work counts for the last tick
+	metrics Metrics
+
+	// Designer hooks for wave and boss behavior
+	script Script
+
+	// Event subscribers, by type and for every event
+	handlers    map[EventType][]EventHandler
+	allHandlers []EventHandler
+
+	// Values last reported by the change events
+	observed observed
+
+	// Invader movement parameters
+	invaderMoveSpeed     float64
+	invaderDropDistance  float64
+	invaderMoveInterval  float64
+	baseInvaderSpeed     float64
+
+	// Whether player one fired since the last ghost sample
+	ghostFired bool
+
+	// Set from the pause menu until the front-end recalibrates the camera
+	calibrationRequested bool
+
+	// Set when the player changes settings until the front-end saves them
+	settingsChanged bool
+
+	// Set when a score joins the high score table until the front-end saves it
+	highScoresChanged bool
+
+	// Attract-mode demo
+	attractTimer float64     // seconds the current attract screen has shown
+	demoTimer    float64     // seconds left in the running demo
+	demoOptions  GameOptions // player's options, restored after the demo
+
+	// Timing accumulators for fixed timestep
+	accumulator float64
+
+	// Read-only copy of the state after each update, for other goroutines
+	publishing bool
+	published  atomic.Pointer[GameState]
+}
+
+// NewEngine creates a new game engine simulating the fixed logical playfield
+func NewEngine() *Engine {
+	return &Engine{
+		state:                NewGameState(PlayfieldWidth, PlayfieldHeight),
+		clock:                NewGameClock(),
+		baseInvaderSpeed:     1.0,  // base speed multiplier
+		invaderDropDistance:  20.0, // pixels to drop down
+		invaderMoveInterval:  1.0,  // seconds between horizontal moves
+		scoring:              DefaultScoringPolicy(),
+		barrierConfig:        DefaultBarrierConfig(),
+		script:               NoScript{},
+		ufoRNG:               resumeRunRNG(0), // replaced with seeded streams at game start
+		dropRNG:              resumeRunRNG(0),
+		shotRNG:              resumeRunRNG(0),
+		particleRNG:          resumeRunRNG(0),
+		collisions:           NewCollisionSystem(PlayfieldWidth, PlayfieldHeight),
+	}
+}
+
+// GetState returns the live game state. It changes with every update, so
+// only the goroutine driving the engine may use it; others use LatestState.
+func (e *Engine) GetState() *GameState {
+	return e.state
+}
+
+// ToggleDailyMode switches between regular games and the daily challenge
+func (e *Engine) ToggleDailyMode() {
+	if e.state.Mode == AttractMode {
+		e.state.Options.Daily = !e.state.Options.Daily
+		e.showTitle()
+	}
+}
+
+// TogglePracticeMode switches practice (invincible) games on or off
+func (e *Engine) TogglePracticeMode() {
+	if e.state.Mode == AttractMode {
+		e.state.Options.Practice = !e.state.Options.Practice
+		e.showTitle()
+	}
+}
+
+// ToggleMirrorMode switches the playfield orientation for the next game
+func (e *Engine) ToggleMirrorMode() {
+	if e.state.Mode == AttractMode {
+		e.state.Options.Mirror = !e.state.Options.Mirror
+		e.showTitle()
+	}
+}
+
+// ToggleTwoPlayer switches between single-player and hotseat games
+func (e *Engine) ToggleTwoPlayer() {
+	if e.state.Mode == AttractMode {
+		e.state.Options.TwoPlayer = !e.state.Options.TwoPlayer
+		e.showTitle()
+	}
+}
+
+// ToggleCoop switches local co-op with two ships on or off
+func (e *Engine) ToggleCoop() {
+	if e.state.Mode == AttractMode {
+		e.state.Options.Coop = !e.state.Options.Coop
+		e.showTitle()
+	}
+}
+
+// ToggleDebris switches the drifting mid-field debris on or off
+func (e *Engine) ToggleDebris() {
+	if e.state.Mode == AttractMode {
+		e.state.Options.Debris = !e.state.Options.Debris
+		e.showTitle()
+	}
+}
+
+// ToggleScreenWrap switches ships between wrapping around and stopping at the screen edges
+func (e *Engine) ToggleScreenWrap() {
+	if e.state.Mode == AttractMode {
+		e.state.Options.ScreenWrap = !e.state.Options.ScreenWrap
+		e.showTitle()
+	}
+}
+
+// ToggleGhost switches racing against the best previous run on or off
+func (e *Engine) ToggleGhost() {
+	if e.state.Mode == AttractMode {
+		e.state.Options.Ghost = !e.state.Options.Ghost
+		e.showTitle()
+	}
+}
+
+// SetAdaptiveDifficulty enables or disables adaptive difficulty for the next game
+func (e *Engine) SetAdaptiveDifficulty(enabled bool) {
+	e.state.Options.Adaptive = enabled
+}
+
+// LoadWaveConfig replaces the wave definitions with a JSON configuration
+func (e *Engine) LoadWaveConfig(data []byte) error {
+	config, err := ParseWaveConfig(data)
+	if err != nil {
+		return err
+	}
+	e.state.WaveConfig = config
+	return nil
+}
+
+// StartNewGame initializes a new game
+func (e *Engine) StartNewGame() {
+	e.applyPendingRules()
+	e.setMode(Playing)
+	e.state.BarrierConfig = e.barrierConfig
+	e.state.InitializeNewGame()
+	e.state.Cheated = e.godMode() // God mode carries over between games
+	e.baseInvaderSpeed = e.state.Options.Difficulty.Preset().InvaderSpeed
+	e.gameStartTime = e.clock.Now()
+	e.ufoRNG = e.state.NewRunRNG(streamUFO)
+	e.dropRNG = e.state.NewRunRNG(streamDrops)
+	e.shotRNG = e.state.NewRunRNG(streamShots)
+	e.particleRNG = e.state.NewRunRNG(streamParticles)
+	e.ufoTimer = e.nextUFODelay()
+	e.startWave()
+}
+
+// interruptDemo starts a real game when a player presses start during the demo
+func (e *Engine) interruptDemo(startPressed bool) {
+	if startPressed {
+		e.stopDemo()
+		e.StartNewGame()
+	}
+}
+
+// firePlayerBullets adds bullets newly fired by the player and counts them as shots
+func (e *Engine) firePlayerBullets(player *Player, bullets []*Bullet) {
+	for _, bullet := range bullets {
+		bullet.Owner = player.Index
+	}
+	e.orientBullets(bullets, player.Ship.Position.Y)
+	e.countShots(player, len(bullets))
+	e.state.Bullets = append(e.state.Bullets, bullets...)
+	track(&e.state.Entities, bullets...)
+}
+
+// fireBeam fires the player's charged beam up the ship's column
+func (e *Engine) fireBeam(player *Player) {
+	ship := player.Ship
+	nose := ship.Position.Y - ship.Bounds.Height/2
+	end := 0.0
+	if e.state.Orientation() == OrientationMirrored {
+		nose = ship.Position.Y + ship.Bounds.Height/2
+		end = float64(e.state.ScreenHeight)
+	}
+
+	beam := NewBeam(ship.Position.X, nose, end)
+	beam.Owner = player.Index
+	e.countShots(player, 1)
+	e.state.Beams = append(e.state.Beams, beam)
+	track(&e.state.Entities, beam)
+}
+
+// countShots records shots fired by the player
+func (e *Engine) countShots(player *Player, shots int) {
+	if shots == 0 {
+		return
+	}
+	if player.Index == 0 {
+		e.ghostFired = true
+	}
+	e.state.ShotsFired++
+	e.state.WaveShotsFired += shots
+	e.state.Stats.ShotsFired += shots
+}
+
+// recordHit marks a player bullet as having hit an enemy
+func (e *Engine) recordHit(bullet *Bullet) {
+	if !bullet.HitTarget {
+		bullet.HitTarget = true
+		e.state.WaveShotsHit++
+		e.state.Stats.ShotsHit++
+	}
+}
+
+// recordBeamHit marks a beam as having hit an enemy
+func (e *Engine) recordBeamHit(beam *Beam) {
+	if !beam.HitTarget {
+		beam.HitTarget = true
+		e.state.WaveShotsHit++
+		e.state.Stats.ShotsHit++
+	}
+}
+
+// recordMiss handles a player bullet that was spent without hitting an enemy
+func (e *Engine) recordMiss() {
+	e.state.ResetCombo()
+	if e.state.Challenge != nil && e.state.Challenge.Kind == ObjectiveNoMiss {
+		e.state.FailChallenge()
+	}
+}
+
+// triggerSmartBomb clears all enemy bullets and the bottom row of invaders,
+// using one of the player's bombs
+func (e *Engine) triggerSmartBomb(player *Player) {
+	if player.SmartBombs <= 0 {
+		return
+	}
+
+	player.SmartBombs--
+	e.state.BombFlash = SmartBombFlashDuration
+	e.shake(shakeSmartBomb)
+
+	// Destroy every enemy bullet on screen
+	for _, bullet := range e.state.Bullets {
+		if !bullet.IsPlayerBullet {
+			bullet.Alive = false
+		}
+	}
+
+	// Destroy the row of the formation closest to the player
+	bottomRow := math.Inf(-1)
+	for _, invader := range e.state.Invaders {
+		if invader.Alive && e.state.Depth(invader.Home.Y) > bottomRow {
+			bottomRow = e.state.Depth(invader.Home.Y)
+		}
+	}
+	for _, invader := range e.state.Invaders {
+		if invader.Alive && e.state.Depth(invader.Home.Y) == bottomRow {
+			invader.Alive = false
+			points := e.invaderPoints(invader)
+			e.state.RegisterKill(player, points)
+			e.emitInvaderKilled(invader, player, points)
+		}
+	}
+}
+
+// Update runs a fixed timestep update loop
+func (e *Engine) Update(deltaTime float64) {
+	defer e.publishState()
+	defer e.emitChanges()
+
+	// Update delta time in state for reference
+	e.state.DeltaTime = deltaTime
+
+	// Don't update if paused
+	if e.state.Paused {
+		return
+	}
+
+	// Accumulate time for fixed timestep updates
+	e.accumulator += deltaTime
+
+	// Run fixed timestep updates
+	for e.accumulator >= e.state.FixedDeltaTime {
+		e.fixedUpdate(e.state.FixedDeltaTime)
+		e.accumulator -= e.state.FixedDeltaTime
+	}
+}
+
+// fixedUpdate performs updates at a fixed timestep (20Hz)
+func (e *Engine) fixedUpdate(deltaTime float64) {
+	defer e.endTick(e.beginTick())
+	e.clock.Advance(deltaTime)
+
+	// Unlock notifications run down in every mode
+	e.state.UpdateAchievementToasts(deltaTime)
+
+	switch e.state.Mode {
+	case AttractMode:
+		e.updateAttractMode(deltaTime)
+	case Playing:
+		e.updatePlaying(deltaTime)
+	case GameOver:
+		e.updateGameOver(deltaTime)
+	case Continue:
+		e.updateContinue(deltaTime)
+	}
+
+	// Clear away what was destroyed this tick, then look entities up afresh
+	e.state.removeDead()
+	e.state.Entities.invalidate()
+
+	// Lockstep builds keep positions and timers on a fixed grid
+	e.snapToGrid()
+}
+
+// updatePlaying handles the main gameplay updates
+func (e *Engine) updatePlaying(deltaTime float64) {
+	// The bot plays while the demo runs
+	if e.state.Demo {
+		e.updateDemo(deltaTime)
+		if !e.state.Demo {
+			return
+		}
+	}
+
+	// Update players
+	for _, player := range e.state.Players {
+		if player.Ship != nil {
+			player.Ship.Update(deltaTime, float64(e.state.ScreenWidth), e.clock.Now())
+			if player.IsActive() && e.state.WaveIntro == 0 && player.Ship.ReleaseBeam() {
+				e.fireBeam(player)
+			}
+		}
+		e.updatePlayerStatus(player, deltaTime)
+	}
+	e.updateGhost(deltaTime)
+	e.state.Stats.PlayTime += deltaTime
+
+	// Fade the smart bomb flash
+	if e.state.BombFlash > 0 {
+		e.state.BombFlash -= deltaTime
+	}
+	e.updateParticles(deltaTime)
+	e.updateExplosions(deltaTime)
+	e.updateDyingInvaders(deltaTime)
+	e.exhaust()
+	e.ufoTrail()
+
+	// Hold the wave-clear interstitial before starting the next wave
+	if e.state.WaveCleared {
+		e.updateWaveClear(deltaTime)
+		return
+	}
+
+	// Hold the new wave back while it assembles
+	if e.state.WaveIntro > 0 {
+		e.updateWaveIntro(deltaTime)
+		return
+	}
+
+	// Update invaders
+	e.updateInvaders(deltaTime)
+
+	// Update boss
+	e.updateBoss(deltaTime)
+
+	// Update bullets
+	e.updateBullets(deltaTime)
+
+	// Update UFO
+	e.updateUFO(deltaTime)
+	e.updateScorePopups(deltaTime)
+	e.updatePickups(deltaTime)
+	e.updateBeams(deltaTime)
+	e.updateDebris(deltaTime)
+
+	// Decay the combo if the player stops scoring
+	e.state.UpdateCombo(deltaTime)
+	e.state.Adaptive.Update(deltaTime)
+	e.state.UpdateChallenge(deltaTime)
+
+	// Handle collisions
+	e.handleCollisions()
+
+	// Check win/lose conditions
+	e.checkGameConditions()
+
+	// Spawn UFO occasionally
+	e.maybeSpawnUFO(deltaTime)
+}
+
+// updateGhost records this run and advances the replayed ghost
+func (e *Engine) updateGhost(deltaTime float64) {
+	e.state.recordGhostFrame(e.ghostFired)
+	e.ghostFired = false
+
+	if e.state.Ghost != nil {
+		shotSpeed := -ghostShotSpeed * e.state.Orientation().Advance()
+		e.state.Ghost.Advance(deltaTime, shotSpeed, e.state.ScreenHeight)
+	}
+}
+
+// updateGameOver handles game over state
+func (e *Engine) updateGameOver(deltaTime float64) {
+	// A lost demo goes straight back to the title screen
+	if e.state.Demo {
+		e.stopDemo()
+	}
+}
+
+// updateInvaders updates all invaders and handles formation movement
+func (e *Engine) updateInvaders(deltaTime float64) {
+	difficulty := DifficultyForWave(e.state.Wave)
+
+	// Update individual invaders, compacting the live ones in place
+	liveInvaders := e.state.Invaders[:0]
+	for _, invader := range e.state.Invaders {
+		if !invader.Alive {
+			continue
+		}
+
+		invader.Update(deltaTime)
+		liveInvaders = append(liveInvaders, invader)
+	}
+	clear(e.state.Invaders[len(liveInvaders):])
+	e.state.Invaders = liveInvaders
+
+	// Only the invader nearest the player in each column may fire
+	shooters := e.columnShooters()
+	for _, invader := range shooters {
+		attack := difficulty.AttackFor(invader.Type)
+		e.addEnemyBullets(invader.TryShoot(e.shotRNG.Float64(), deltaTime, e.clock.Now(), attack), invader.Position.Y)
+	}
+	e.updateAimedShot(deltaTime, shooters)
+
+	// Handle formation movement
+	e.updateInvaderFormation(deltaTime)
+
+	// Handle invaders that broke formation
+	e.updateDivingInvaders(deltaTime)
+}
+
+// updateDyingInvaders counts down each destroyed invader's splat and drops
+// the finished ones, compacting the list in place
+func (e *Engine) updateDyingInvaders(deltaTime float64) {
+	dying := e.state.DyingInvaders[:0]
+	for _, invader := range e.state.DyingInvaders {
+		invader.Splat -= deltaTime
+		if invader.Splat > 0 {
+			dying = append(dying, invader)
+		}
+	}
+	clear(e.state.DyingInvaders[len(dying):])
+	e.state.DyingInvaders = dying
+}
+
+// columnShooters returns the live invader nearest the player in each
+// formation column, in formation order. Invaders that broke formation don't
+// shoot and don't shield the invaders behind them. The returned slice is
+// reused by the next call.
+func (e *Engine) columnShooters() []*Invader {
+	advance := e.state.Orientation().Advance()
+	shooters := e.shooters[:0]
+	if e.shooterColumn == nil {
+		e.shooterColumn = make(map[float64]int)
+	}
+	columns := e.shooterColumn
+	clear(columns)
+
+	for _, invader := range e.state.Invaders {
+		if !invader.Alive || invader.MoveState != InvaderInFormation {
+			continue
+		}
+		index, ok := columns[invader.Home.X]
+		if !ok {
+			columns[invader.Home.X] = len(shooters)
+			shooters = append(shooters, invader)
+			continue
+		}
+		if (invader.Home.Y-shooters[index].Home.Y)*advance > 0 {
+			shooters[index] = invader
+		}
+	}
+
+	e.shooters = shooters
+	return shooters
+}
+
+// updateAimedShot periodically fires a shot from the column nearest a player.
+// Aimed shots start on a later wave and come more often as the waves go on.
+func (e *Engine) updateAimedShot(deltaTime float64, shooters []*Invader) {
+	if e.state.Wave < aimedShotWave {
+		return
+	}
+
+	e.aimedShotTimer += deltaTime
+	interval := math.Max(1.5, 7.0-0.5*float64(e.state.Wave))
+	if e.aimedShotTimer < interval {
+		return
+	}
+
+	// Pick the shooter lined up most closely with any ship
+	var aimer *Invader
+	closest := math.Inf(1)
+	for _, invader := range shooters {
+		ship := e.state.NearestShip(invader.Position.X)
+		if ship == nil {
+			return
+		}
+		if distance := math.Abs(ship.Position.X - invader.Position.X); distance < closest {
+			aimer, closest = invader, distance
+		}
+	}
+	if aimer == nil {
+		return
+	}
+
+	e.aimedShotTimer = 0
+	e.addEnemyBullets(aimer.fire(AttackSingle), aimer.Position.Y)
+}
+
+// updateDivingInvaders launches new dives and advances detached invaders
+func (e *Engine) updateDivingInvaders(deltaTime float64) {
+	const maxDivers = 2
+
+	// Drop detached invaders that were destroyed or rejoined the formation
+	stillDetached := e.detachedInvaders[:0]
+	for _, invader := range e.detachedInvaders {
+		if invader.Alive && invader.IsDetached() {
+			stillDetached = append(stillDetached, invader)
+		}
+	}
+	e.detachedInvaders = stillDetached
+
+	// Periodically send another invader down, more often on later waves
+	e.diveTimer += deltaTime
+	diveInterval := math.Max(2.0, 8.0-float64(e.state.Wave))
+	if e.diveTimer >= diveInterval && len(e.detachedInvaders) < maxDivers && len(e.state.Invaders) > 0 {
+		e.diveTimer = 0
+		e.diveCounter++
+
+		invader := e.state.Invaders[(e.diveCounter*7)%len(e.state.Invaders)]
+		if !invader.IsDetached() {
+			invader.StartDive(e.targetX(invader.Position.X))
+			e.detachedInvaders = append(e.detachedInvaders, invader)
+		}
+	}
+
+	// Kamikazes charge once the formation thins out
+	if len(e.state.Invaders) <= kamikazeTrigger {
+		for _, invader := range e.state.Invaders {
+			if invader.Type == InvaderTypeKamikaze && !invader.IsDetached() {
+				invader.StartCharge(e.targetX(invader.Position.X))
+				e.detachedInvaders = append(e.detachedInvaders, invader)
+			}
+		}
+	}
+
+	pullOutY := e.state.ScreenY(float64(e.state.ScreenHeight - 80))
+	advance := e.state.Orientation().Advance()
+	for _, invader := range e.detachedInvaders {
+		if e.script.MoveInvader(e.state, invader, deltaTime) {
+			invader.updateBounds()
+		} else {
+			invader.UpdateDive(deltaTime, pullOutY, advance)
+		}
+
+		// Kamikazes that miss fly off the player's edge of the screen
+		if invader.MoveState == InvaderCharging && e.state.Depth(invader.Position.Y) > float64(e.state.ScreenHeight) {
+			invader.Alive = false
+		}
+	}
+}
+
+// targetX returns the column an enemy at column x should aim at
+func (e *Engine) targetX(x float64) float64 {
+	if ship := e.state.NearestShip(x); ship != nil {
+		return ship.Position.X
+	}
+	return float64(e.state.ScreenWidth) / 2
+}
+
+// updateInvaderFormation handles the classic invader formation movement
+func (e *Engine) updateInvaderFormation(deltaTime float64) {
+	if len(e.state.Invaders) == 0 {
+		return
+	}
+
+	e.invaderMoveTimer += deltaTime
+
+	// Calculate movement speed based on remaining invaders (fewer = faster)
+	invaderCount := float64(len(e.state.Invaders))
+	speedMultiplier := e.baseInvaderSpeed * e.state.WaveSpec.SpeedScale * e.state.Adaptive.Scale() * (55.0 / (invaderCount + 5.0))
+	currentMoveInterval := e.invaderMoveInterval / speedMultiplier
+
+	if e.invaderMoveTimer >= currentMoveInterval {
+		e.invaderMoveTimer = 0
+
+		// Find the bounds of the formation, whatever its shape
+		bounds := FormationBounds(e.state.Invaders)
+
+		// Determine if we need to drop down and reverse direction
+		shouldDrop := false
+		direction := e.state.Invaders[0].Direction
+
+		if direction > 0 && bounds.X+bounds.Width >= float64(e.state.ScreenWidth)-formationEdgeMargin {
+			shouldDrop = true
+			direction = -1
+		} else if direction < 0 && bounds.X <= formationEdgeMargin {
+			shouldDrop = true
+			direction = 1
+		}
+
+		// Move all invaders
+		moveDistance := 10.0 * float64(direction)
+
+		for _, invader := range e.state.Invaders {
+			invader.Direction = direction
+
+			if shouldDrop {
+				invader.Move(0, e.invaderDropDistance*e.state.Orientation().Advance())
+			} else {
+				invader.Move(moveDistance, 0)
+			}
+		}
+		if shouldDrop {
+			e.state.FormationDrop += e.invaderDropDistance
+		}
+
+		// Check if invaders reached the bottom
+		e.checkInvaderReachBottom()
+	}
+}
+
+// checkInvaderReachBottom checks if any invader has reached the bottom
+func (e *Engine) checkInvaderReachBottom() {
+	if !e.state.InvadersLanded() {
+		return
+	}
+
+	switch e.state.Rules().Landed(e.state) {
+	case LandingRestartWave:
+		e.state.initializeWave()
+		e.startWave()
+	case LandingLoseTurn:
+		// Only this player's game ends
+		if player := e.state.PrimaryPlayer(); player != nil && player.Status == PlayerActive {
+			player.Lives = 1
+			e.killPlayer(player)
+		}
+	default:
+		e.endGame()
+	}
+}
+
+// updateBoss updates the boss and fires its current attack pattern
+func (e *Engine) updateBoss(deltaTime float64) {
+	boss := e.state.Boss
+	if boss == nil {
+		return
+	}
+
+	if !boss.Alive {
+		e.state.Boss = nil
+		return
+	}
+
+	e.script.SteerBoss(e.state, boss, deltaTime)
+	boss.Update(deltaTime, float64(e.state.ScreenWidth))
+
+	targetX := boss.Position.X
+	if ship := e.state.NearestShip(boss.Position.X); ship != nil {
+		targetX = ship.Position.X
+	}
+	e.addEnemyBullets(boss.TryShoot(deltaTime, targetX), boss.Position.Y)
+}
+
+// addEnemyBullets adds enemy bullets fired from originY with the difficulty's speed applied
+func (e *Engine) addEnemyBullets(bullets []*Bullet, originY float64) {
+	scale := e.state.Options.Difficulty.Preset().BulletSpeed
+	for _, bullet := range bullets {
+		bullet.Velocity = bullet.Velocity.Scale(scale)
+	}
+	e.orientBullets(bullets, originY)
+	e.state.Bullets = append(e.state.Bullets, bullets...)
+	track(&e.state.Entities, bullets...)
+}
+
+// orientBullets flips newly fired bullets about their shooter when the
+// playfield is mirrored; entities always fire as if facing the classic way
+func (e *Engine) orientBullets(bullets []*Bullet, originY float64) {
+	if e.state.Orientation() != OrientationMirrored {
+		return
+	}
+	for _, bullet := range bullets {
+		bullet.Velocity.Y = -bullet.Velocity.Y
+		bullet.Position.Y = 2*originY - bullet.Position.Y
+		bullet.Bounds.Y = bullet.Position.Y - bullet.Bounds.Height/2
+	}
+}
+
+// updateBullets updates all bullets and removes dead ones
+func (e *Engine) updateBullets(deltaTime float64) {
+	liveBullets := e.state.Bullets[:0]
+
+	for _, bullet := range e.state.Bullets {
+		if !bullet.Alive {
+			continue
+		}
+
+		// Homing bullets track the nearest ship's current column
+		if ship := e.state.NearestShip(bullet.Position.X); ship != nil {
+			bullet.Steer(ship.Position.X, deltaTime)
+		}
+		bullet.Update(deltaTime, float64(e.state.ScreenWidth), float64(e.state.ScreenHeight))
+
+		if bullet.Alive {
+			liveBullets = append(liveBullets, bullet)
+		} else if bullet.IsPlayerBullet && !bullet.HitTarget {
+			// Player shot left the screen without hitting anything
+			e.recordMiss()
+		}
+	}
+
+	clear(e.state.Bullets[len(liveBullets):])
+	e.state.Bullets = liveBullets
+}
+
+// updateUFO updates the UFO if it exists
+func (e *Engine) updateUFO(deltaTime float64) {
+	if e.state.UFO != nil {
+		e.state.UFO.Update(deltaTime, float64(e.state.ScreenWidth))
+		if !e.state.UFO.Alive {
+			e.state.UFO = nil
+		}
+	}
+}
+
+// updateScorePopups advances floating score popups and removes expired ones
+func (e *Engine) updateScorePopups(deltaTime float64) {
+	livePopups := e.state.ScorePopups[:0]
+	for _, popup := range e.state.ScorePopups {
+		popup.Update(deltaTime)
+		if popup.Timer > 0 {
+			livePopups = append(livePopups, popup)
+		}
+	}
+	e.state.ScorePopups = livePopups
+}
+
+// updatePickups moves falling pickups and removes collected or lost ones
+func (e *Engine) updatePickups(deltaTime float64) {
+	livePickups := e.state.Pickups[:0]
+	for _, pickup := range e.state.Pickups {
+		pickup.Update(deltaTime, float64(e.state.ScreenHeight))
+		if pickup.Alive {
+			livePickups = append(livePickups, pickup)
+		}
+	}
+	e.state.Pickups = livePickups
+}
+
+// updateBeams expires beams that have finished firing
+func (e *Engine) updateBeams(deltaTime float64) {
+	liveBeams := e.state.Beams[:0]
+	for _, beam := range e.state.Beams {
+		beam.Update(deltaTime)
+		if beam.Timer > 0 {
+			liveBeams = append(liveBeams, beam)
+		} else if !beam.HitTarget {
+			e.recordMiss()
+		}
+	}
+	e.state.Beams = liveBeams
+}
+
+// updateDebris drifts the debris field and clears away crumbled chunks
+func (e *Engine) updateDebris(deltaTime float64) {
+	liveDebris := e.state.Debris[:0]
+	for _, debris := range e.state.Debris {
+		debris.Update(deltaTime, float64(e.state.ScreenWidth))
+		if debris.Alive {
+			liveDebris = append(liveDebris, debris)
+		}
+	}
+	e.state.Debris = liveDebris
+}
+
+// maybeDropPickup occasionally leaves a bonus token where an invader died
+func (e *Engine) maybeDropPickup(invader *Invader) {
+	if e.dropRNG.Float64() >= pickupDropChance {
+		return
+	}
+	kind := PickupScore
+	switch roll := e.dropRNG.Float64(); {
+	case roll < shieldDropShare:
+		kind = PickupShield
+	case roll < shieldDropShare+tripleShotDropShare:
+		kind = PickupTripleShot
+	}
+	velY := pickupFallSpeed * e.state.Orientation().Advance()
+	pickup := NewPickup(invader.Position.X, invader.Position.Y, velY, kind, pickupPoints)
+	e.state.Pickups = append(e.state.Pickups, pickup)
+	track(&e.state.Entities, pickup)
+}
+
+// maybeSpawnUFO spawns a UFO once the countdown to the next one runs out
+func (e *Engine) maybeSpawnUFO(deltaTime float64) {
+	if e.state.UFO != nil {
+		return // UFO already exists
+	}
+
+	e.ufoTimer -= deltaTime
+	if e.ufoTimer > 0 {
+		return
+	}
+	e.ufoTimer = e.nextUFODelay()
+
+	// Spawn from random side
+	var startX float64
+	var direction int
+
+	if e.ufoRNG.Intn(2) == 0 {
+		// Spawn from left
+		startX = -50
+		direction = 1
+	} else {
+		// Spawn from right
+		startX = float64(e.state.ScreenWidth) + 50
+		direction = -1
+	}
+
+	e.state.UFO = NewUFO(startX, e.state.ScreenY(50), direction)
+	track(&e.state.Entities, e.state.UFO)
+	e.emit(Event{Type: EventUFOSpawned, Position: e.state.UFO.Position, Entity: e.state.UFO.ID})
+}
+
+// nextUFODelay picks the seconds until the next UFO within the wave's interval
+func (e *Engine) nextUFODelay() float64 {
+	spec := e.state.WaveSpec
+	return spec.UFOIntervalMin + (spec.UFOIntervalMax-spec.UFOIntervalMin)*e.ufoRNG.Float64()
+}
+
+// handleCollisions handles all collision detection and responses
+func (e *Engine) handleCollisions() {
+	// Player bullets vs invaders
+	e.handlePlayerBulletCollisions()
+
+	// Player bullets vs UFO
+	e.handlePlayerBulletUFOCollisions()
+
+	// Player bullets vs boss
+	e.handlePlayerBulletBossCollisions()
+
+	// Charged beams vs invaders and boss
+	e.handleBeamCollisions()
+
+	// Enemy bullets vs player
+	e.handleEnemyBulletCollisions()
+
+	// Invaders ramming the player
+	e.handlePlayerInvaderCollisions()
+
+	// Bullets vs debris
+	e.handleDebrisCollisions()
+
+	// Players collecting pickups
+	e.handlePickupCollisions()
+
+	// Bullets vs barriers
+	e.handleBarrierCollisions()
+}
+
+// handleBarrierCollisions lets bullets and invaders chew through the barriers
+func (e *Engine) handleBarrierCollisions() {
+	barriers := e.state.Barriers
+	if len(barriers) == 0 {
+		return
+	}
+
+	for _, bullet := range e.state.Bullets {
+		if hit, x, y := e.collisions.BulletHitsBarrier(bullet, e.state); hit {
+			bullet.Alive = false
+			DestroyBarrierBlock(barriers, x, y, 2)
+			e.chipBarrier(bullet)
+			if bullet.IsPlayerBullet && !bullet.HitTarget {
+				e.recordMiss() // Shooting your own shields is a miss
+			}
+		}
+	}
+
+	// Invaders marching through a barrier erase whatever they touch
+	for _, invader := range e.state.Invaders {
+		if !invader.Alive {
+			continue
+		}
+		for {
+			hit, x, y := e.collisions.BoundsHitBarrier(invader.Bounds, e.state)
+			if !hit {
+				break
+			}
+			barriers[x][y] = false
+		}
+	}
+}
+
+// handlePlayerBulletCollisions handles collisions between player bullets and invaders
+func (e *Engine) handlePlayerBulletCollisions() {
+	// Hash the invaders so each bullet only checks those nearby
+	e.collisions.Reset()
+	for i, invader := range e.state.Invaders {
+		if invader.Alive {
+			e.collisions.Insert(i, invader.Bounds)
+		}
+	}
+
+	for _, bullet := range e.state.Bullets {
+		if !bullet.Alive || !bullet.IsPlayerBullet {
+			continue
+		}
+
+		for _, i := range e.collisions.Query(bullet.Bounds) {
+			invader := e.state.Invaders[i]
+			if e.collisions.BulletHitsInvader(bullet, invader) {
+				// Collision detected
+				invader.Alive = false
+				e.recordHit(bullet)
+				owner, points := e.state.BulletOwner(bullet), e.invaderPoints(invader)
+				e.state.RegisterKill(owner, points)
+				e.emitInvaderKilled(invader, owner, points)
+				e.maybeDropPickup(invader)
+				if bullet.Piercing {
+					continue // Piercing bullets keep going
+				}
+				bullet.Alive = false
+				break // Bullet can only hit one invader
+			}
+		}
+	}
+}
+
+// handlePlayerBulletUFOCollisions handles collisions between player bullets and UFO
+func (e *Engine) handlePlayerBulletUFOCollisions() {
+	if e.state.UFO == nil || !e.state.UFO.Alive {
+		return
+	}
+
+	for _, bullet := range e.state.Bullets {
+		if !bullet.Alive || !bullet.IsPlayerBullet {
+			continue
+		}
+
+		if e.collisions.BulletHitsUFO(bullet, e.state.UFO) {
+			// Collision detected
+			bullet.Alive = false
+			e.recordHit(bullet)
+			ufo := e.state.UFO
+			ufo.Alive = false
+			ufo.Points = UFOMysteryScore(e.state.ShotsFired)
+			e.state.AddScore(e.state.BulletOwner(bullet), ufo.Points)
+			e.state.Stats.UFOsHit++
+			if ufo.Points == maxUFOScore {
+				e.state.UnlockAchievement(AchievementMaxUFO)
+			}
+			popup := NewScorePopup(ufo.Position.X, ufo.Position.Y-12, ufo.Points)
+			popup.Flash = true
+			e.addScorePopup(popup)
+			e.explode(ExplosionUFO, ufo.Position, ufo.Bounds.Width)
+			break // Bullet hits UFO
+		}
+	}
+}
+
+// handlePlayerBulletBossCollisions handles collisions between player bullets and the boss
+func (e *Engine) handlePlayerBulletBossCollisions() {
+	boss := e.state.Boss
+	if boss == nil || !boss.Alive {
+		return
+	}
+
+	for _, bullet := range e.state.Bullets {
+		if !bullet.Alive || !bullet.IsPlayerBullet {
+			continue
+		}
+
+		point := e.collisions.BulletWeakPoint(bullet, boss)
+		if point == nil {
+			// The armored hull stops shots that miss the weak points
+			if e.collisions.BulletHitsBoss(bullet, boss) {
+				bullet.Alive = false
+				e.recordMiss()
+			}
+			continue
+		}
+
+		bullet.Alive = false
+		e.recordHit(bullet)
+		if boss.HitWeakPoint(point, bullet.Damage) {
+			e.state.AddScore(e.state.BulletOwner(bullet), boss.Points)
+			e.explode(ExplosionBoss, boss.Position, boss.Bounds.Width)
+			e.shake(shakeBossKilled)
+			e.state.Boss = nil
+			return
+		}
+		e.shake(shakeBossHit)
+	}
+}
+
+// handleBeamCollisions destroys every invader in a beam's column and damages
+// each boss weak point it crosses once per beam
+func (e *Engine) handleBeamCollisions() {
+	for _, beam := range e.state.Beams {
+		owner := e.state.Players[beam.Owner]
+
+		for _, invader := range e.state.Invaders {
+			if invader.Alive && e.collisions.BeamHits(beam, invader.Bounds) {
+				invader.Alive = false
+				e.recordBeamHit(beam)
+				points := e.invaderPoints(invader)
+				e.state.RegisterKill(owner, points)
+				e.emitInvaderKilled(invader, owner, points)
+				e.maybeDropPickup(invader)
+			}
+		}
+
+		boss := e.state.Boss
+		if boss == nil || !boss.Alive || beam.HitBoss {
+			continue
+		}
+		for _, point := range boss.WeakPoints {
+			if point.Destroyed() || !e.collisions.BeamHits(beam, point.Bounds) {
+				continue
+			}
+			beam.HitBoss = true
+			e.recordBeamHit(beam)
+			if boss.HitWeakPoint(point, beamBossDamage) {
+				e.state.AddScore(owner, boss.Points)
+				e.explode(ExplosionBoss, boss.Position, boss.Bounds.Width)
+				e.shake(shakeBossKilled)
+				e.state.Boss = nil
+				break
+			}
+			e.shake(shakeBossHit)
+		}
+	}
+}
+
+// handleEnemyBulletCollisions handles collisions between enemy bullets and the players
+func (e *Engine) handleEnemyBulletCollisions() {
+	for _, player := range e.state.Players {
+		if !player.IsActive() || player.Ship.IsInvulnerable() || e.godMode() {
+			continue
+		}
+
+		for _, bullet := range e.state.Bullets {
+			if !bullet.Alive || bullet.IsPlayerBullet {
+				continue // Friendly bullets pass through ships
+			}
+
+			if e.collisions.BulletHitsPlayer(bullet, player.Ship) {
+				// Player hit by enemy bullet
+				bullet.Alive = false
+				if player.Ship.AbsorbHit() {
+					continue // The shield pops instead of the ship
+				}
+				e.killPlayer(player)
+				break
+			}
+		}
+	}
+}
+
+// handlePlayerInvaderCollisions handles invaders colliding with the player ships
+func (e *Engine) handlePlayerInvaderCollisions() {
+	for _, player := range e.state.Players {
+		if !player.IsActive() || player.Ship.IsInvulnerable() || e.godMode() {
+			continue
+		}
+
+		for _, invader := range e.state.Invaders {
+			if e.collisions.PlayerHitsInvader(player.Ship, invader) {
+				// The invader is destroyed along with the ship
+				invader.Alive = false
+				e.killPlayer(player)
+				break
+			}
+		}
+	}
+}
+
+// handleDebrisCollisions stops bullets from either side against the debris,
+// wearing it down a little with each hit
+func (e *Engine) handleDebrisCollisions() {
+	for _, bullet := range e.state.Bullets {
+		if !bullet.Alive {
+			continue
+		}
+
+		for _, debris := range e.state.Debris {
+			if !e.collisions.BulletHitsDebris(bullet, debris) {
+				continue
+			}
+
+			bullet.Alive = false
+			debris.TakeHit()
+			if bullet.IsPlayerBullet && !bullet.HitTarget {
+				e.recordMiss() // Debris absorbs the shot
+			}
+			break
+		}
+	}
+}
+
+// handlePickupCollisions awards pickups to the ships that touch them
+func (e *Engine) handlePickupCollisions() {
+	for _, pickup := range e.state.Pickups {
+		if !pickup.Alive {
+			continue
+		}
+
+		for _, player := range e.state.Players {
+			if !player.IsActive() || !e.collisions.PlayerCatchesPickup(player.Ship, pickup) {
+				continue
+			}
+
+			pickup.Alive = false
+			e.emit(Event{
+				Type:     EventPowerUpCollected,
+				Position: pickup.Position,
+				Player:   player.Index,
+				Points:   pickup.Points,
+				Pickup:   pickup.Kind,
+				Entity:   pickup.ID,
+			})
+			switch pickup.Kind {
+			case PickupShield:
+				player.Ship.GrantShield(ShieldDuration)
+			case PickupTripleShot:
+				player.Ship.TripleShotTimer = TripleShotDuration
+			default:
+				e.state.AddScore(player, pickup.Points)
+				e.addScorePopup(NewScorePopup(pickup.Position.X, pickup.Position.Y-12, pickup.Points))
+			}
+			break
+		}
+	}
+}
+
+// addScorePopup shows a popup with its own entity ID
+func (e *Engine) addScorePopup(popup *ScorePopup) {
+	e.state.ScorePopups = append(e.state.ScorePopups, popup)
+	track(&e.state.Entities, popup)
+}
+
+// emitInvaderKilled announces that a player destroyed an invader worth the given points
+func (e *Engine) emitInvaderKilled(invader *Invader, player *Player, points int) {
+	event := Event{
+		Type:     EventInvaderKilled,
+		Position: invader.Position,
+		Player:   player.Index,
+		Points:   points,
+		Invader:  invader.Type,
+		Entity:   invader.ID,
+	}
+	e.emit(event)
+	e.explode(ExplosionInvader, invader.Position, invader.Bounds.Width)
+	e.explodeInvader(invader)
+	event.Wave = e.state.Wave
+	e.script.OnInvaderKilled(e.state, event)
+}
+
+// killPlayer destroys the player's ship and starts the death sequence
+func (e *Engine) killPlayer(player *Player) {
+	ship := player.Ship
+	ship.Alive = false
+	ship.Velocity.X = 0
+	player.DeathPosition = ship.Position
+	e.explode(ExplosionShip, ship.Position, ship.Bounds.Width)
+	e.wreckShip(ship)
+	e.state.Adaptive.RecordDeath()
+	e.emit(Event{Type: EventPlayerHit, Position: ship.Position, Player: player.Index, Entity: ship.ID})
+	e.shake(shakePlayerDeath)
+	if e.state.LoseLife(player) && !e.state.HasPlayersRemaining() {
+		e.endGame()
+	}
+
+	// Play the explosion before counting down to a respawn. The sequence
+	// only runs while playing, so a lost game leaves the ship dying.
+	player.Status = PlayerDying
+	player.StatusTimer = playerDeathDuration
+}
+
+// updatePlayerStatus advances the player's death and respawn sequence
+func (e *Engine) updatePlayerStatus(player *Player, deltaTime float64) {
+	switch player.Status {
+	case PlayerDying:
+		player.StatusTimer -= deltaTime
+		if player.StatusTimer <= 0 {
+			// In hotseat games a lost life passes the turn
+			if e.state.NextTurn() {
+				e.resetInvaderMovement()
+			}
+
+			// Co-op partners keep playing after this player runs out of lives
+			if player.Lives <= 0 && !e.state.Options.Practice {
+				player.Status = PlayerOut
+				player.StatusTimer = 0
+				return
+			}
+
+			player.Status = PlayerRespawning
+			player.StatusTimer = playerRespawnCountdown
+		}
+	case PlayerRespawning:
+		player.StatusTimer -= deltaTime
+		if player.StatusTimer <= 0 {
+			e.respawnPlayer(player)
+		}
+	}
+}
+
+// respawnPlayer respawns the player at their starting position
+func (e *Engine) respawnPlayer(player *Player) {
+	player.Status = PlayerActive
+	player.StatusTimer = 0
+	player.SmartBombs = SmartBombsPerLife
+	player.Ship = e.state.NewPlayer(player.Index)
+	player.Ship.InvulnerableTimer = respawnInvulnerability
+
+	// Clear enemy bullets for fairness
+	e.state.Bullets = slices.DeleteFunc(e.state.Bullets, func(bullet *Bullet) bool {
+		return !bullet.IsPlayerBullet
+	})
+}
+
+// checkGameConditions checks for win/lose conditions
+func (e *Engine) checkGameConditions() {
+	rules := e.state.Rules()
+	if rules.GameOver(e.state) {
+		e.endGame()
+		return
+	}
+
+	// Check if wave is cleared
+	if rules.WaveCleared(e.state) && !e.state.WaveCleared {
+		e.state.WaveCleared = true
+		e.state.WaveClearTimer = waveClearDuration
+		e.state.Stats.WavesCleared++
+
+		if e.state.WaveShotsFired > 0 && e.state.WaveShotsHit == e.state.WaveShotsFired {
+			e.state.UnlockAchievement(AchievementPerfectWave)
+		}
+
+		// Award the accuracy bonus for the completed wave
+		e.state.AccuracyBonus = e.state.CalculateAccuracyBonus()
+		e.state.AwardTeamBonus(e.state.AccuracyBonus)
+
+		// Award the challenge bonus if the objective held
+		e.state.ChallengeBonus = e.state.CompleteChallenge()
+		e.state.AwardTeamBonus(e.state.ChallengeBonus)
+
+		// Let adaptive difficulty react to how the wave went
+		e.state.Adaptive.EndWave(e.state.WaveAccuracy())
+
+		e.emit(Event{Type: EventWaveCleared, Points: e.state.AccuracyBonus + e.state.ChallengeBonus})
+	}
+}
+
+// updateWaveClear counts down the wave-clear interstitial and starts the next wave
+func (e *Engine) updateWaveClear(deltaTime float64) {
+	e.updateBullets(deltaTime)
+
+	e.state.WaveClearTimer -= deltaTime
+	if e.state.WaveClearTimer <= 0 {
+		e.state.NextWave()
+		e.startWave()
+	}
+}
+
+// updateWaveIntro counts down the get-ready banner while the new wave
+// assembles. The ships hold fire, but anything already moving carries on and
+// can still hit.
+func (e *Engine) updateWaveIntro(deltaTime float64) {
+	e.updateBullets(deltaTime)
+	e.updateUFO(deltaTime)
+	e.updateScorePopups(deltaTime)
+	e.updatePickups(deltaTime)
+	e.updateBeams(deltaTime)
+	e.updateDebris(deltaTime)
+	e.handleCollisions()
+	e.checkGameConditions()
+	e.state.WaveIntro = max(e.state.WaveIntro-deltaTime, 0)
+}
+
+// resetInvaderMovement resets invader movement timing
+func (e *Engine) resetInvaderMovement() {
+	e.invaderMoveTimer = 0
+	e.invaderDropTimer = 0
+	e.diveTimer = 0
+	e.detachedInvaders = nil
+	e.aimedShotTimer = 0
+}
\ No newline at end of file
@@ -0,0 +1,1284 @@
+package game
+
+import (
+	"math"
+)
+
+// Vector2 represents a 2D vector for position and velocity
+type Vector2 struct {
+	X, Y float64
+}
+
+// Add returns the sum of two vectors
+func (v Vector2) Add(other Vector2) Vector2 {
+	return Vector2{X: v.X + other.X, Y: v.Y + other.Y}
+}
+
+// Scale returns a scaled vector. The products are rounded on their own so
+// they can't be fused into a following Add (see Deterministic).
+func (v Vector2) Scale(scalar float64) Vector2 {
+	return Vector2{X: float64(v.X * scalar), Y: float64(v.Y * scalar)}
+}
+
+// Magnitude returns the magnitude of the vector
+func (v Vector2) Magnitude() float64 {
+	return math.Sqrt(float64(v.X*v.X) + float64(v.Y*v.Y))
+}
+
+// Bounds represents a rectangular boundary
+type Bounds struct {
+	X, Y, Width, Height float64
+}
+
+// Contains checks if a point is within the bounds
+func (b Bounds) Contains(x, y float64) bool {
+	return x >= b.X && x < b.X+b.Width && y >= b.Y && y < b.Y+b.Height
+}
+
+// Intersects checks if two bounds intersect (AABB collision)
+func (b Bounds) Intersects(other Bounds) bool {
+	return b.X < other.X+other.Width &&
+		b.X+b.Width > other.X &&
+		b.Y < other.Y+other.Height &&
+		b.Y+b.Height > other.Y
+}
+
+// WeaponType represents the player's selectable weapons
+type WeaponType int
+
+const (
+	WeaponSingle WeaponType = iota // One bullet per shot
+	WeaponDouble                   // Two parallel bullets, slower cooldown
+	WeaponLaser                    // Piercing bolt that passes through invaders
+	WeaponBeam                     // Charged beam that clears a whole column
+	weaponCount
+)
+
+// String returns the HUD name of the weapon
+func (w WeaponType) String() string {
+	switch w {
+	case WeaponSingle:
+		return "SINGLE"
+	case WeaponDouble:
+		return "DOUBLE"
+	case WeaponLaser:
+		return "LASER"
+	case WeaponBeam:
+		return "BEAM"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// FireRate returns the weapon's shots per second
+func (w WeaponType) FireRate() float64 {
+	switch w {
+	case WeaponDouble:
+		return 2.5
+	case WeaponLaser:
+		return 1.5
+	case WeaponBeam:
+		return 1.0
+	default:
+		return 4.0
+	}
+}
+
+// PlayerShip represents the player's ship
+type PlayerShip struct {
+	ID           EntityID
+	Position     Vector2
+	Velocity     Vector2
+	Bounds       Bounds
+	Alive        bool
+	MaxSpeed     float64
+	Acceleration float64
+	Friction     float64
+
+	// Animation state
+	Anim Animation
+
+	// Shooting state
+	CanShoot     bool
+	LastShotTime float64 // game time of the last shot, in seconds
+	FireRate     float64 // shots per second
+	Weapon       WeaponType
+
+	// Multiplier applied to every weapon's fire rate
+	FireRateScale float64
+
+	// Beam charge: seconds left to charge, and whether a charged beam is ready
+	ChargeTimer float64
+	BeamCharged bool
+
+	// Invulnerability after respawning (seconds remaining)
+	InvulnerableTimer float64
+
+	// Wrap from one screen edge to the other instead of stopping at the edges
+	ScreenWrap bool
+
+	// Bubble shield from a pickup that absorbs one enemy bullet (seconds remaining)
+	ShieldTimer float64
+
+	// Triple-shot power-up fanning each shot three ways (seconds remaining)
+	TripleShotTimer float64
+
+	// Sideways dash: seconds left in the current dash and until the next one
+	DashTimer         float64
+	DashCooldownTimer float64
+
+	// Double-tap detection: direction held last frame, direction of the
+	// last fresh press and seconds left to tap it again
+	heldDir  int
+	tapDir   int
+	tapTimer float64
+
+	// Optional heat model replacing the fire rate cooldown
+	HeatEnabled bool
+	Heat        float64 // 0 (cold) to 1 (overheated)
+	Overheated  bool    // locked out until the weapon cools down completely
+}
+
+// beamChargeTime is how long the beam charges after pressing fire, in seconds
+const beamChargeTime = 0.6
+
+// BeamDuration is how long a charged beam stays on screen, in seconds
+const BeamDuration = 0.25
+
+// Weapon heat tuning
+const (
+	heatPerShot     = 0.2  // heat added by each shot
+	heatCoolRate    = 0.35 // heat removed per second
+	heatMinInterval = 0.08 // minimum seconds between shots with heat enabled
+)
+
+// Sideways dash tuning
+const (
+	dashSpeed       = 700.0 // pixels per second during a dash
+	dashDuration    = 0.15  // seconds a dash lasts
+	doubleTapWindow = 0.25  // seconds between taps that count as a double-tap
+)
+
+// DashCooldown is how long the dash takes to recharge, in seconds
+const DashCooldown = 1.5
+
+// NewPlayerShip creates a new player ship at the specified position
+func NewPlayerShip(x, y float64) *PlayerShip {
+	const shipWidth = 24
+	const shipHeight = 16
+
+	return &PlayerShip{
+		Position:      Vector2{X: x, Y: y},
+		Velocity:      Vector2{X: 0, Y: 0},
+		Bounds:        Bounds{X: x - shipWidth/2, Y: y - shipHeight/2, Width: shipWidth, Height: shipHeight},
+		Alive:         true,
+		MaxSpeed:      200.0, // pixels per second
+		Acceleration:  800.0, // pixels per second squared
+		Friction:      400.0, // pixels per second squared
+		CanShoot:      true,
+		FireRate:      WeaponSingle.FireRate(),
+		FireRateScale: 1,
+		Weapon:        WeaponSingle,
+		LastShotTime:  neverShot,
+		Anim:          NewAnimation(AnimShipThrust),
+	}
+}
+
+// updateHeat cools the weapon and manages the overheat lockout
+func (p *PlayerShip) updateHeat(deltaTime, now float64) {
+	p.Heat = math.Max(p.Heat-float64(heatCoolRate*deltaTime), 0)
+	if p.Overheated && p.Heat == 0 {
+		p.Overheated = false
+	}
+
+	if !p.CanShoot && !p.Overheated && now-p.LastShotTime > heatMinInterval {
+		p.CanShoot = true
+	}
+}
+
+// IsInvulnerable reports whether the ship is currently immune to enemy fire
+func (p *PlayerShip) IsInvulnerable() bool {
+	return p.InvulnerableTimer > 0
+}
+
+// HasShield reports whether a bubble shield is protecting the ship
+func (p *PlayerShip) HasShield() bool {
+	return p.ShieldTimer > 0
+}
+
+// GrantShield raises a bubble shield for the given number of seconds
+func (p *PlayerShip) GrantShield(duration float64) {
+	p.ShieldTimer = duration
+}
+
+// AbsorbHit pops the shield, reporting whether it stopped the hit
+func (p *PlayerShip) AbsorbHit() bool {
+	if !p.HasShield() {
+		return false
+	}
+	p.ShieldTimer = 0
+	return true
+}
+
+// HasTripleShot reports whether the triple-shot power-up is active
+func (p *PlayerShip) HasTripleShot() bool {
+	return p.TripleShotTimer > 0
+}
+
+// SetWeapon switches the active weapon and adopts its fire rate
+func (p *PlayerShip) SetWeapon(weapon WeaponType) {
+	p.Weapon = weapon
+	p.FireRate = weapon.FireRate() * p.FireRateScale
+	p.ChargeTimer = 0
+	p.BeamCharged = false
+}
+
+// IsCharging reports whether the beam is charging up
+func (p *PlayerShip) IsCharging() bool {
+	return p.ChargeTimer > 0
+}
+
+// ChargeProgress returns how far the beam has charged, from 0 to 1
+func (p *PlayerShip) ChargeProgress() float64 {
+	if !p.IsCharging() {
+		return 0
+	}
+	return 1 - p.ChargeTimer/beamChargeTime
+}
+
+// ReleaseBeam reports whether a charged beam is ready, consuming the charge
+func (p *PlayerShip) ReleaseBeam() bool {
+	if !p.BeamCharged {
+		return false
+	}
+	p.BeamCharged = false
+	return true
+}
+
+// CycleWeapon switches to the next weapon type
+func (p *PlayerShip) CycleWeapon() {
+	p.SetWeapon((p.Weapon + 1) % weaponCount)
+}
+
+// Update updates the player ship's position and state at the given game time
+func (p *PlayerShip) Update(deltaTime, screenWidth, now float64) {
+	if !p.Alive {
+		return
+	}
+
+	// Update position based on velocity
+	p.Position = p.Position.Add(p.Velocity.Scale(deltaTime))
+
+	// Keep player within screen bounds
+	p.Confine(screenWidth)
+
+	// Count down respawn invulnerability
+	if p.InvulnerableTimer > 0 {
+		p.InvulnerableTimer -= deltaTime
+	}
+
+	// Finish the dash and let it recharge
+	if p.DashTimer > 0 {
+		p.DashTimer -= deltaTime
+		if p.DashTimer <= 0 {
+			p.DashTimer = 0
+			p.Velocity.X = 0
+		}
+	}
+	if p.DashCooldownTimer > 0 {
+		p.DashCooldownTimer = math.Max(p.DashCooldownTimer-deltaTime, 0)
+	}
+	if p.tapTimer > 0 {
+		p.tapTimer -= deltaTime
+	}
+
+	// Charge the beam
+	if p.ChargeTimer > 0 {
+		p.ChargeTimer -= deltaTime
+		if p.ChargeTimer <= 0 {
+			p.ChargeTimer = 0
+			p.BeamCharged = true
+		}
+	}
+
+	// Let the power-ups wear off
+	if p.ShieldTimer > 0 {
+		p.ShieldTimer -= deltaTime
+	}
+	if p.TripleShotTimer > 0 {
+		p.TripleShotTimer -= deltaTime
+	}
+
+	// Update shooting cooldown
+	if p.HeatEnabled {
+		p.updateHeat(deltaTime, now)
+	} else if !p.CanShoot && now-p.LastShotTime > 1.0/p.FireRate {
+		p.CanShoot = true
+	}
+
+	// Update animation
+	p.Anim.Update(deltaTime)
+}
+
+// Confine keeps the ship on screen, either stopping it at the edges or
+// wrapping it around to the far side, and refreshes its bounds
+func (p *PlayerShip) Confine(screenWidth float64) {
+	halfWidth := p.Bounds.Width / 2
+	if p.ScreenWrap {
+		if p.Position.X < 0 {
+			p.Position.X += screenWidth
+		} else if p.Position.X >= screenWidth {
+			p.Position.X -= screenWidth
+		}
+	} else if p.Position.X < halfWidth {
+		p.Position.X = halfWidth
+		p.Velocity.X = 0
+	} else if p.Position.X > screenWidth-halfWidth {
+		p.Position.X = screenWidth - halfWidth
+		p.Velocity.X = 0
+	}
+
+	p.Bounds.X = p.Position.X - halfWidth
+	p.Bounds.Y = p.Position.Y - p.Bounds.Height/2
+}
+
+// ApplyInput applies input forces to the player ship
+func (p *PlayerShip) ApplyInput(left, right bool, deltaTime float64) {
+	if !p.Alive {
+		return
+	}
+
+	// Double-tapping a direction dashes that way
+	dir := 0
+	if left && !right {
+		dir = -1
+	} else if right && !left {
+		dir = 1
+	}
+	if dir != 0 && dir != p.heldDir {
+		if dir == p.tapDir && p.tapTimer > 0 {
+			p.Dash(float64(dir))
+			p.tapTimer = 0
+		} else {
+			p.tapDir, p.tapTimer = dir, doubleTapWindow
+		}
+	}
+	p.heldDir = dir
+
+	// The dash carries the ship regardless of input
+	if p.IsDashing() {
+		return
+	}
+
+	// Apply acceleration based on input
+	if left && !right {
+		p.Velocity.X -= float64(p.Acceleration * deltaTime)
+	} else if right && !left {
+		p.Velocity.X += float64(p.Acceleration * deltaTime)
+	} else {
+		// Apply friction when no input
+		if p.Velocity.X > 0 {
+			p.Velocity.X -= float64(p.Friction * deltaTime)
+			if p.Velocity.X < 0 {
+				p.Velocity.X = 0
+			}
+		} else if p.Velocity.X < 0 {
+			p.Velocity.X += float64(p.Friction * deltaTime)
+			if p.Velocity.X > 0 {
+				p.Velocity.X = 0
+			}
+		}
+	}
+
+	// Clamp velocity to max speed
+	if p.Velocity.X > p.MaxSpeed {
+		p.Velocity.X = p.MaxSpeed
+	} else if p.Velocity.X < -p.MaxSpeed {
+		p.Velocity.X = -p.MaxSpeed
+	}
+}
+
+// Dash launches the ship sideways in the given direction (-1 left, 1 right)
+// unless the dash is still recharging, reporting whether it dashed
+func (p *PlayerShip) Dash(direction float64) bool {
+	if !p.Alive || !p.DashReady() {
+		return false
+	}
+	p.Velocity.X = direction * dashSpeed
+	p.DashTimer = dashDuration
+	p.DashCooldownTimer = DashCooldown
+	return true
+}
+
+// IsDashing reports whether the ship is mid-dash
+func (p *PlayerShip) IsDashing() bool {
+	return p.DashTimer > 0
+}
+
+// DashReady reports whether the dash has recharged
+func (p *PlayerShip) DashReady() bool {
+	return p.DashCooldownTimer <= 0
+}
+
+// TryShoot attempts to create bullets for the active weapon if shooting is
+// allowed, given the current game time
+func (p *PlayerShip) TryShoot(now float64) []*Bullet {
+	if !p.Alive || !p.CanShoot {
+		return nil
+	}
+
+	p.CanShoot = false
+	p.LastShotTime = now
+
+	// Rapid fire builds heat until the weapon locks out
+	if p.HeatEnabled {
+		p.Heat += heatPerShot
+		if p.Heat >= 1 {
+			p.Heat = 1
+			p.Overheated = true
+		}
+	}
+
+	// Create bullets at player position, moving upward
+	x := p.Position.X
+	y := p.Position.Y - p.Bounds.Height/2
+
+	bullets := p.weaponBullets(x, y)
+	if p.HasTripleShot() && len(bullets) > 0 {
+		return fanBullets(bullets[0])
+	}
+	return bullets
+}
+
+// weaponBullets creates the bullets for one shot of the active weapon
+func (p *PlayerShip) weaponBullets(x, y float64) []*Bullet {
+	switch p.Weapon {
+	case WeaponDouble:
+		const offset = 6
+		return []*Bullet{
+			NewBullet(x-offset, y, 0, -400, true),
+			NewBullet(x+offset, y, 0, -400, true),
+		}
+	case WeaponLaser:
+		laser := NewBullet(x, y, 0, -600, true)
+		laser.Piercing = true
+		laser.Bounds.Height = 16
+		return []*Bullet{laser}
+	case WeaponBeam:
+		// The beam fires once charged; see ReleaseBeam
+		p.ChargeTimer = beamChargeTime
+		return nil
+	default:
+		return []*Bullet{NewBullet(x, y, 0, -400, true)}
+	}
+}
+
+// fanBullets spreads copies of a bullet into a three-way fan
+func fanBullets(center *Bullet) []*Bullet {
+	const angle = 0.2
+	speed := center.Velocity.Magnitude()
+
+	bullets := []*Bullet{center}
+	for _, side := range []float64{-1, 1} {
+		bullet := *center
+		bullet.Velocity = Vector2{X: side * math.Sin(angle) * speed, Y: -math.Cos(angle) * speed}
+		bullets = append(bullets, &bullet)
+	}
+	return bullets
+}
+
+// InvaderType represents different types of invaders
+type InvaderType int
+
+const (
+	InvaderTypeSmall InvaderType = iota
+	InvaderTypeMedium
+	InvaderTypeLarge
+	InvaderTypeKamikaze // Charges the player once the formation thins out
+)
+
+// InvaderMoveState represents where an invader is in its movement state machine
+type InvaderMoveState int
+
+const (
+	InvaderInFormation InvaderMoveState = iota // Marching with the formation
+	InvaderDiving                              // Swooping toward the player's column
+	InvaderReturning                           // Flying back to its formation slot
+	InvaderCharging                            // Kamikaze plunging at the player
+)
+
+// neverShot is the LastShotTime of a ship or invader that has not fired yet:
+// far enough back that its first shot is always ready, and finite so saved
+// games can hold it
+const neverShot = -math.MaxFloat64
+
+// columnShotScale boosts each invader's shoot chance to make up for only the
+// front invader of each column being allowed to fire
+const columnShotScale = 10.0
+
+// Invader represents an enemy invader
+type Invader struct {
+	ID        EntityID
+	Type      InvaderType
+	Position  Vector2
+	Bounds    Bounds
+	Alive     bool
+	Points    int
+	Direction int // -1 for left, 1 for right
+
+	// Movement state
+	MoveState  InvaderMoveState
+	Home       Vector2 // Formation slot, tracked even while detached
+	DiveTarget float64 // X coordinate the dive is aimed at
+	DiveSpeed  float64 // current speed of a kamikaze charge
+
+	// Animation state
+	Anim  Animation
+	Splat float64 // seconds left showing the splat once destroyed
+
+	// Shooting state (for advanced invaders)
+	CanShoot     bool
+	LastShotTime float64 // game time of the last shot, in seconds
+	ShootChance  float64 // probability per second
+}
+
+// NewInvader creates a new invader
+func NewInvader(invaderType InvaderType, x, y float64, points int) *Invader {
+	var width, height float64
+	var shootChance float64
+
+	switch invaderType {
+	case InvaderTypeSmall:
+		width, height = 16, 16
+		shootChance = 0.03 // 3% chance per second (reduced from 10%)
+	case InvaderTypeMedium:
+		width, height = 20, 16
+		shootChance = 0.02 // 2% chance per second (reduced from 5%)
+	case InvaderTypeLarge:
+		width, height = 24, 16
+		shootChance = 0.01 // 1% chance per second (reduced from 2%)
+	case InvaderTypeKamikaze:
+		width, height = 16, 16
+		shootChance = 0 // Kamikazes attack by ramming
+	}
+
+	return &Invader{
+		Type:         invaderType,
+		Position:     Vector2{X: x, Y: y},
+		Home:         Vector2{X: x, Y: y},
+		Bounds:       Bounds{X: x - width/2, Y: y - height/2, Width: width, Height: height},
+		Alive:        true,
+		Points:       points,
+		Direction:    1, // Initially moving right
+		CanShoot:     true,
+		ShootChance:  shootChance,
+		LastShotTime: neverShot,
+		Anim:         NewAnimation(AnimInvaderMarch),
+	}
+}
+
+// Update updates the invader's animation state
+func (i *Invader) Update(deltaTime float64) {
+	if !i.Alive {
+		return
+	}
+
+	// Update animation
+	i.Anim.Update(deltaTime)
+}
+
+// Move moves the invader's formation slot by the specified offset
+func (i *Invader) Move(deltaX, deltaY float64) {
+	if !i.Alive {
+		return
+	}
+
+	i.Home.X += deltaX
+	i.Home.Y += deltaY
+
+	// Detached invaders keep flying; only the slot marches
+	if i.MoveState != InvaderInFormation {
+		return
+	}
+
+	i.Position.X += deltaX
+	i.Position.Y += deltaY
+
+	// Update bounds
+	i.updateBounds()
+}
+
+// updateBounds recenters the bounds on the invader's position
+func (i *Invader) updateBounds() {
+	i.Bounds.X = i.Position.X - i.Bounds.Width/2
+	i.Bounds.Y = i.Position.Y - i.Bounds.Height/2
+}
+
+// IsDetached reports whether the invader has broken formation
+func (i *Invader) IsDetached() bool {
+	return i.MoveState != InvaderInFormation
+}
+
+// StartDive breaks the invader out of formation toward the given column
+func (i *Invader) StartDive(targetX float64) {
+	if !i.Alive || i.MoveState != InvaderInFormation {
+		return
+	}
+	i.MoveState = InvaderDiving
+	i.DiveTarget = targetX
+}
+
+// StartCharge sends a kamikaze plunging toward the given column
+func (i *Invader) StartCharge(targetX float64) {
+	if !i.Alive || i.MoveState != InvaderInFormation {
+		return
+	}
+	i.MoveState = InvaderCharging
+	i.DiveTarget = targetX
+	i.DiveSpeed = 60
+}
+
+// UpdateDive advances a detached invader along its dive or return path.
+// advance is the screen-space sign of the direction toward the player.
+func (i *Invader) UpdateDive(deltaTime float64, pullOutY, advance float64) {
+	if !i.Alive {
+		return
+	}
+
+	const diveSpeed = 220.0    // pixels per second
+	const returnSpeed = 160.0  // pixels per second
+	const chargeAccel = 400.0  // pixels per second squared
+	const maxChargeSpeed = 450 // pixels per second
+
+	switch i.MoveState {
+	case InvaderDiving:
+		// Swoop down while drifting toward the target column
+		dirX, dirY := NormalizeVector((i.DiveTarget-i.Position.X)*0.5, 100*advance)
+		i.Position.X += float64(dirX * diveSpeed * deltaTime)
+		i.Position.Y += float64(dirY * diveSpeed * deltaTime)
+		if (i.Position.Y-pullOutY)*advance >= 0 {
+			i.MoveState = InvaderReturning
+		}
+	case InvaderReturning:
+		dx := i.Home.X - i.Position.X
+		dy := i.Home.Y - i.Position.Y
+		distance := math.Sqrt(float64(dx*dx) + float64(dy*dy))
+		step := returnSpeed * deltaTime
+		if distance <= step {
+			i.Position = i.Home
+			i.MoveState = InvaderInFormation
+		} else {
+			i.Position.X += float64(dx / distance * step)
+			i.Position.Y += float64(dy / distance * step)
+		}
+	case InvaderCharging:
+		// Accelerate straight at the player, correcting slightly toward the target
+		i.DiveSpeed = math.Min(i.DiveSpeed+float64(chargeAccel*deltaTime), maxChargeSpeed)
+		i.Position.Y += float64(i.DiveSpeed * deltaTime * advance)
+		i.Position.X += float64((i.DiveTarget - i.Position.X) * math.Min(deltaTime*2, 1))
+	}
+
+	i.updateBounds()
+}
+
+// TryShoot fires the given attack when roll, a uniform value in [0, 1), falls
+// under the invader's shoot chance for this frame, given the current game time
+func (i *Invader) TryShoot(roll, deltaTime, now float64, attack AttackType) []*Bullet {
+	if !i.Alive || !i.CanShoot {
+		return nil
+	}
+
+	// Random shooting based on shoot chance
+	shootProbability := i.ShootChance * columnShotScale * deltaTime
+	if roll < shootProbability {
+		i.LastShotTime = now
+		return i.fire(attack)
+	}
+
+	return nil
+}
+
+// fire creates the bullets for the given attack type
+func (i *Invader) fire(attack AttackType) []*Bullet {
+	x := i.Position.X
+	y := i.Position.Y + i.Bounds.Height/2
+
+	switch attack {
+	case AttackHoming:
+		// Slow shot that curves toward the player
+		bullet := NewBullet(x, y, 0, 140, false)
+		bullet.Homing = true
+		return []*Bullet{bullet}
+	case AttackSpread:
+		// Three-way fan
+		const speed = 200.0
+		const angle = 0.35
+		return []*Bullet{
+			NewBullet(x, y, -math.Sin(angle)*speed, math.Cos(angle)*speed, false),
+			NewBullet(x, y, 0, speed, false),
+			NewBullet(x, y, math.Sin(angle)*speed, math.Cos(angle)*speed, false),
+		}
+	default:
+		// Create bullet moving downward
+		return []*Bullet{NewBullet(x, y, 0, 200, false)}
+	}
+}
+
+// Bullet represents a projectile
+type Bullet struct {
+	ID             EntityID
+	Position       Vector2
+	Velocity       Vector2
+	Bounds         Bounds
+	Alive          bool
+	IsPlayerBullet bool
+	Damage         int
+	Piercing       bool // passes through invaders instead of stopping
+	Homing         bool // curves toward TargetX while falling
+	TargetX        float64
+	HitTarget      bool // set once the bullet has hit an enemy
+	Owner          int  // index of the player who fired it
+}
+
+// NewBullet creates a new bullet
+func NewBullet(x, y, velX, velY float64, isPlayerBullet bool) *Bullet {
+	const bulletWidth = 2
+	const bulletHeight = 8
+
+	return &Bullet{
+		Position:       Vector2{X: x, Y: y},
+		Velocity:       Vector2{X: velX, Y: velY},
+		Bounds:         Bounds{X: x - bulletWidth/2, Y: y - bulletHeight/2, Width: bulletWidth, Height: bulletHeight},
+		Alive:          true,
+		IsPlayerBullet: isPlayerBullet,
+		Damage:         1,
+	}
+}
+
+// Steer turns a homing bullet toward the target X, keeping its speed constant
+func (b *Bullet) Steer(targetX, deltaTime float64) {
+	if !b.Alive || !b.Homing {
+		return
+	}
+
+	const turnRate = 120.0      // horizontal acceleration in pixels per second squared
+	const maxLateralRatio = 0.6 // max horizontal speed relative to total speed
+
+	b.TargetX = targetX
+	speed := b.Velocity.Magnitude()
+	maxLateral := speed * maxLateralRatio
+
+	if targetX > b.Position.X {
+		b.Velocity.X = math.Min(b.Velocity.X+float64(turnRate*deltaTime), maxLateral)
+	} else if targetX < b.Position.X {
+		b.Velocity.X = math.Max(b.Velocity.X-float64(turnRate*deltaTime), -maxLateral)
+	}
+
+	// Preserve overall speed so steering doesn't speed the bullet up
+	vertical := math.Sqrt(math.Max(speed*speed-b.Velocity.X*b.Velocity.X, 0))
+	if b.Velocity.Y < 0 {
+		vertical = -vertical
+	}
+	b.Velocity.Y = vertical
+}
+
+// Update updates the bullet's position
+func (b *Bullet) Update(deltaTime float64, screenWidth, screenHeight float64) {
+	if !b.Alive {
+		return
+	}
+
+	// Update position
+	b.Position = b.Position.Add(b.Velocity.Scale(deltaTime))
+
+	// Update bounds
+	b.Bounds.X = b.Position.X - b.Bounds.Width/2
+	b.Bounds.Y = b.Position.Y - b.Bounds.Height/2
+
+	// Remove bullets that go off screen
+	if b.Position.Y < 0 || b.Position.Y > screenHeight ||
+		b.Position.X < 0 || b.Position.X > screenWidth {
+		b.Alive = false
+	}
+}
+
+// BossAttackPattern represents the attack pattern a boss is currently using
+type BossAttackPattern int
+
+const (
+	BossPatternAimed BossAttackPattern = iota // Single shots aimed at the player
+	BossPatternSpread                          // Five-way fan of bullets
+	BossPatternBarrage                         // Rapid fire straight down
+)
+
+// Boss represents a large multi-hit enemy that replaces the formation on boss waves
+type Boss struct {
+	ID        EntityID
+	Position  Vector2
+	Velocity  Vector2
+	Bounds    Bounds
+	Alive     bool
+	Health    int
+	MaxHealth int
+	Points    int
+
+	// Weak points are the only parts that take damage; the boss is
+	// destroyed once they are all knocked out
+	WeakPoints []*WeakPoint
+	Facing     float64 // 1 when the boss faces down the screen, -1 when mirrored
+
+	// Attack state
+	Pattern      BossAttackPattern
+	PatternTimer float64 // time spent in the current pattern
+	ShotTimer    float64 // time until the next shot
+
+	// Animation state
+	Anim     Animation
+	HitTimer float64 // flash time remaining after being hit
+}
+
+// NewBoss creates a new boss with health scaled to the wave number
+func NewBoss(x, y float64, wave int) *Boss {
+	const bossWidth = 96
+	const bossHeight = 40
+	const bossSpeed = 80.0 // pixels per second
+
+	// The core takes half the health and each cannon a quarter
+	health := 20 + wave*2
+	cannonHealth := health / 4
+	coreHealth := health - 2*cannonHealth
+
+	boss := &Boss{
+		Position: Vector2{X: x, Y: y},
+		Velocity: Vector2{X: bossSpeed, Y: 0},
+		Bounds:   Bounds{X: x - bossWidth/2, Y: y - bossHeight/2, Width: bossWidth, Height: bossHeight},
+		Alive:    true,
+		WeakPoints: []*WeakPoint{
+			NewWeakPoint(WeakPointCannon, -bossWidth/6, bossHeight/2+4, 12, 14, cannonHealth),
+			NewWeakPoint(WeakPointCore, 0, bossHeight/4, 20, 20, coreHealth),
+			NewWeakPoint(WeakPointCannon, bossWidth/6, bossHeight/2+4, 12, 14, cannonHealth),
+		},
+		Facing:    1,
+		Health:    health,
+		MaxHealth: health,
+		Points:    1000 + wave*100,
+		Pattern:   BossPatternAimed,
+		ShotTimer: 1.0,
+		Anim:      NewAnimation(AnimBossPulse),
+	}
+	boss.updateWeakPoints()
+	return boss
+}
+
+// Face turns the boss to face down the screen (1) or up it when mirrored (-1)
+func (b *Boss) Face(facing float64) {
+	b.Facing = facing
+	b.updateWeakPoints()
+}
+
+// updateWeakPoints moves the weak points along with the boss
+func (b *Boss) updateWeakPoints() {
+	for _, point := range b.WeakPoints {
+		point.Bounds.X = b.Position.X + point.Offset.X - point.Bounds.Width/2
+		point.Bounds.Y = b.Position.Y + point.Offset.Y*b.Facing - point.Bounds.Height/2
+	}
+}
+
+// Update moves the boss back and forth and cycles its attack patterns
+func (b *Boss) Update(deltaTime float64, screenWidth float64) {
+	if !b.Alive {
+		return
+	}
+
+	// Sweep across the top of the screen
+	b.Position = b.Position.Add(b.Velocity.Scale(deltaTime))
+	halfWidth := b.Bounds.Width / 2
+	if b.Position.X < halfWidth {
+		b.Position.X = halfWidth
+		b.Velocity.X = -b.Velocity.X
+	} else if b.Position.X > screenWidth-halfWidth {
+		b.Position.X = screenWidth - halfWidth
+		b.Velocity.X = -b.Velocity.X
+	}
+
+	// Update bounds
+	b.Bounds.X = b.Position.X - b.Bounds.Width/2
+	b.Bounds.Y = b.Position.Y - b.Bounds.Height/2
+	b.updateWeakPoints()
+
+	// Switch attack pattern every few seconds
+	b.PatternTimer += deltaTime
+	if b.PatternTimer > 6.0 {
+		b.PatternTimer = 0
+		b.Pattern = (b.Pattern + 1) % 3
+	}
+
+	if b.HitTimer > 0 {
+		b.HitTimer -= deltaTime
+	}
+	for _, point := range b.WeakPoints {
+		if point.HitTimer > 0 {
+			point.HitTimer -= deltaTime
+		}
+	}
+
+	// Update animation
+	b.Anim.Update(deltaTime)
+}
+
+// TryShoot fires the current attack pattern when the shot timer elapses
+func (b *Boss) TryShoot(deltaTime float64, targetX float64) []*Bullet {
+	if !b.Alive {
+		return nil
+	}
+
+	b.ShotTimer -= deltaTime
+	if b.ShotTimer > 0 {
+		return nil
+	}
+
+	const bulletSpeed = 200.0
+	x := b.Position.X
+	y := b.Position.Y + b.Bounds.Height/2
+
+	switch b.Pattern {
+	case BossPatternAimed:
+		b.ShotTimer = 0.8
+		dirX, dirY := NormalizeVector(targetX-x, 200)
+		return []*Bullet{NewBullet(x, y, dirX*bulletSpeed, dirY*bulletSpeed, false)}
+	case BossPatternSpread:
+		b.ShotTimer = 1.5
+		bullets := make([]*Bullet, 0, 5)
+		for i := -2; i <= 2; i++ {
+			angle := float64(i) * 0.3
+			bullets = append(bullets, NewBullet(x, y, math.Sin(angle)*bulletSpeed, math.Cos(angle)*bulletSpeed, false))
+		}
+		return bullets
+	case BossPatternBarrage:
+		b.ShotTimer = 0.25
+		offset := float64(b.Anim.Frame()*2-1) * b.Bounds.Width / 3
+		return []*Bullet{NewBullet(x+offset, y, 0, bulletSpeed*1.5, false)}
+	}
+
+	return nil
+}
+
+// HitWeakPoint damages one of the boss's weak points and reports whether
+// the boss was destroyed
+func (b *Boss) HitWeakPoint(point *WeakPoint, damage int) bool {
+	if !b.Alive || point.Destroyed() {
+		return false
+	}
+
+	damage = min(damage, point.Health)
+	point.Health -= damage
+	point.HitTimer = 0.1
+	b.Health -= damage
+	b.HitTimer = 0.1
+
+	for _, other := range b.WeakPoints {
+		if !other.Destroyed() {
+			return false
+		}
+	}
+	b.Health = 0
+	b.Alive = false
+	return true
+}
+
+// WeakPointKind identifies what part of the boss a weak point is
+type WeakPointKind int
+
+const (
+	WeakPointCore   WeakPointKind = iota // The glowing eye in the hull
+	WeakPointCannon                      // A cannon slung under the hull
+)
+
+// WeakPoint is a separately damaged part of the boss
+type WeakPoint struct {
+	Kind      WeakPointKind
+	Offset    Vector2 // center relative to the boss, with the boss facing down
+	Bounds    Bounds
+	Health    int
+	MaxHealth int
+	HitTimer  float64 // flash time remaining after being hit
+}
+
+// NewWeakPoint creates a weak point of the given size centered at an offset from the boss
+func NewWeakPoint(kind WeakPointKind, offsetX, offsetY, width, height float64, health int) *WeakPoint {
+	return &WeakPoint{
+		Kind:      kind,
+		Offset:    Vector2{X: offsetX, Y: offsetY},
+		Bounds:    Bounds{Width: width, Height: height},
+		Health:    health,
+		MaxHealth: health,
+	}
+}
+
+// Destroyed reports whether the weak point has been knocked out
+func (w *WeakPoint) Destroyed() bool {
+	return w.Health <= 0
+}
+
+// HealthFraction returns the weak point's remaining health as a value between 0 and 1
+func (w *WeakPoint) HealthFraction() float64 {
+	if w.MaxHealth == 0 {
+		return 0
+	}
+	return float64(w.Health) / float64(w.MaxHealth)
+}
+
+// HealthFraction returns the remaining health as a value between 0 and 1
+func (b *Boss) HealthFraction() float64 {
+	if b.MaxHealth == 0 {
+		return 0
+	}
+	return float64(b.Health) / float64(b.MaxHealth)
+}
+
+// UFO represents the bonus enemy UFO
+type UFO struct {
+	ID        EntityID
+	Position  Vector2
+	Velocity  Vector2
+	Bounds    Bounds
+	Alive     bool
+	Points    int
+	Direction int // -1 for left, 1 for right
+
+	// State tracking
+	Age         float64 // seconds since spawning
+	MaxLifetime float64 // seconds before the UFO gives up and leaves
+	Anim        Animation
+}
+
+// NewUFO creates a new UFO
+func NewUFO(startX, y float64, direction int) *UFO {
+	const ufoWidth = 32
+	const ufoHeight = 16
+	const ufoSpeed = 100.0 // pixels per second
+
+	velocity := Vector2{X: ufoSpeed * float64(direction), Y: 0}
+
+	return &UFO{
+		Position:    Vector2{X: startX, Y: y},
+		Velocity:    velocity,
+		Bounds:      Bounds{X: startX - ufoWidth/2, Y: y - ufoHeight/2, Width: ufoWidth, Height: ufoHeight},
+		Alive:       true,
+		Direction:   direction,
+		MaxLifetime: 15, // UFO disappears after 15 seconds
+		Anim:        NewAnimation(AnimUFOLights),
+	}
+}
+
+// Update updates the UFO's position and state
+func (u *UFO) Update(deltaTime float64, screenWidth float64) {
+	if !u.Alive {
+		return
+	}
+
+	// Update position
+	u.Position = u.Position.Add(u.Velocity.Scale(deltaTime))
+	u.Age += deltaTime
+	u.Anim.Update(deltaTime)
+
+	// Update bounds
+	u.Bounds.X = u.Position.X - u.Bounds.Width/2
+	u.Bounds.Y = u.Position.Y - u.Bounds.Height/2
+
+	// Remove UFO if it goes off screen or exceeds lifetime
+	if u.Position.X < -u.Bounds.Width || u.Position.X > screenWidth+u.Bounds.Width ||
+		u.Age > u.MaxLifetime {
+		u.Alive = false
+	}
+}
+
+// ufoScoreTable is the arcade mystery score table, indexed by player shot count
+var ufoScoreTable = []int{100, 50, 50, 100, 150, 100, 100, 50, 300, 100, 100, 100, 50, 150, 100}
+
+// maxUFOScore is the top value in the mystery score table
+const maxUFOScore = 300
+
+// ufoScoreLag is how many shots the cabinet's table pointer runs behind the
+// shots fired
+const ufoScoreLag = 15
+
+// UFOMysteryScore returns the UFO value for a hit on the given shot number.
+// Like the original cabinet, the table pointer runs a full table behind the
+// shot count and then wraps every 15 shots, so the first 15 shots score the
+// table's first entry and 300 points come up on the 23rd shot and every 15th
+// after it (38th, 53rd and so on).
+func UFOMysteryScore(shotNumber int) int {
+	index := max(shotNumber-ufoScoreLag, 0)
+	return ufoScoreTable[index%len(ufoScoreTable)]
+}
+
+// ScorePopup represents awarded points floating above a destroyed enemy
+type ScorePopup struct {
+	ID       EntityID
+	Position Vector2
+	Points   int
+	Timer    float64 // seconds remaining on screen
+	Flash    bool    // blink while shown, for the UFO's mystery score
+}
+
+// NewScorePopup creates a new score popup at the specified position
+func NewScorePopup(x, y float64, points int) *ScorePopup {
+	return &ScorePopup{
+		Position: Vector2{X: x, Y: y},
+		Points:   points,
+		Timer:    1.5,
+	}
+}
+
+// Update drifts the popup upward and counts down its lifetime
+func (s *ScorePopup) Update(deltaTime float64) {
+	s.Position.Y -= float64(20 * deltaTime)
+	s.Timer -= deltaTime
+}
+
+// Debris is a drifting mid-field obstacle that blocks shots from both sides
+// and crumbles a little with every hit
+type Debris struct {
+	ID        EntityID
+	Position  Vector2
+	Velocity  Vector2
+	Bounds    Bounds
+	Alive     bool
+	Health    int
+	MaxHealth int
+	Size      float64 // full width and height before any erosion
+}
+
+// NewDebris creates a debris chunk drifting horizontally at the given speed
+func NewDebris(x, y, velX float64) *Debris {
+	const debrisSize = 36
+	const debrisHealth = 6
+
+	d := &Debris{
+		Position:  Vector2{X: x, Y: y},
+		Velocity:  Vector2{X: velX, Y: 0},
+		Alive:     true,
+		Health:    debrisHealth,
+		MaxHealth: debrisHealth,
+		Size:      debrisSize,
+	}
+	d.updateBounds()
+	return d
+}
+
+// Update drifts the debris, wrapping around the screen edges
+func (d *Debris) Update(deltaTime float64, screenWidth float64) {
+	if !d.Alive {
+		return
+	}
+
+	d.Position = d.Position.Add(d.Velocity.Scale(deltaTime))
+	if d.Position.X < -d.Size/2 {
+		d.Position.X += screenWidth + d.Size
+	} else if d.Position.X > screenWidth+d.Size/2 {
+		d.Position.X -= screenWidth + d.Size
+	}
+	d.updateBounds()
+}
+
+// TakeHit erodes the debris and reports whether it crumbled away entirely
+func (d *Debris) TakeHit() bool {
+	d.Health--
+	if d.Health <= 0 {
+		d.Alive = false
+		return true
+	}
+	d.updateBounds()
+	return false
+}
+
+// updateBounds shrinks the collision box in step with the remaining health
+func (d *Debris) updateBounds() {
+	size := d.Size * (0.4 + 0.6*float64(d.Health)/float64(d.MaxHealth))
+	d.Bounds = Bounds{X: d.Position.X - size/2, Y: d.Position.Y - size/2, Width: size, Height: size}
+}
+
+// PickupKind is what a pickup gives the ship that collects it
+type PickupKind int
+
+const (
+	PickupScore      PickupKind = iota // Bonus points
+	PickupShield                       // Bubble shield that absorbs one enemy bullet
+	PickupTripleShot                   // Timed three-way fan on every shot
+)
+
+// Pickup is a bonus token dropped by a destroyed invader
+type Pickup struct {
+	ID       EntityID
+	Position Vector2
+	Velocity Vector2
+	Bounds   Bounds
+	Alive    bool
+	Kind     PickupKind
+	Points   int // awarded by score pickups
+	Anim     Animation
+}
+
+// NewPickup creates a pickup falling at the given vertical speed
+func NewPickup(x, y, velY float64, kind PickupKind, points int) *Pickup {
+	const size = 12
+	return &Pickup{
+		Position: Vector2{X: x, Y: y},
+		Velocity: Vector2{X: 0, Y: velY},
+		Bounds:   Bounds{X: x - size/2, Y: y - size/2, Width: size, Height: size},
+		Alive:    true,
+		Kind:     kind,
+		Points:   points,
+		Anim:     NewAnimation(AnimPickupPulse),
+	}
+}
+
+// Update moves the pickup and expires it once it falls off screen
+func (p *Pickup) Update(deltaTime float64, screenHeight float64) {
+	if !p.Alive {
+		return
+	}
+
+	p.Position = p.Position.Add(p.Velocity.Scale(deltaTime))
+	p.Anim.Update(deltaTime)
+	p.Bounds.X = p.Position.X - p.Bounds.Width/2
+	p.Bounds.Y = p.Position.Y - p.Bounds.Height/2
+
+	if p.Position.Y < -p.Bounds.Height || p.Position.Y > screenHeight+p.Bounds.Height {
+		p.Alive = false
+	}
+}
+
+// Beam is a charged laser column that destroys everything in its path for a
+// few frames
+type Beam struct {
+	ID        EntityID
+	X         float64
+	StartY    float64 // the firing ship's nose
+	EndY      float64 // the far edge of the screen
+	Timer     float64 // seconds remaining on screen
+	Owner     int     // index of the player who fired it
+	HitTarget bool    // whether the beam destroyed anything
+	HitBoss   bool    // the boss takes damage once per beam
+}
+
+// NewBeam creates a beam from startY to endY in the given column
+func NewBeam(x, startY, endY float64) *Beam {
+	return &Beam{
+		X:      x,
+		StartY: startY,
+		EndY:   endY,
+		Timer:  BeamDuration,
+	}
+}
+
+// Update counts down the beam's lifetime
+func (b *Beam) Update(deltaTime float64) {
+	b.Timer -= deltaTime
+}
+
+// Hits reports whether the beam passes through the bounds
+func (b *Beam) Hits(bounds Bounds) bool {
+	return LineIntersectsBounds(b.X, b.StartY, b.X, b.EndY, bounds)
+}
@@ -0,0 +1,109 @@
+package game
+
+// EventType identifies something that happened during an engine update
+type EventType int
+
+const (
+	EventInvaderKilled    EventType = iota // An invader was destroyed by a player
+	EventPlayerHit                         // A player's ship was destroyed
+	EventWaveCleared                       // The last enemy of the wave fell
+	EventUFOSpawned                        // A UFO started across the screen
+	EventPowerUpCollected                  // A player caught a falling pickup
+	EventScoreChanged                      // A player's score changed
+	EventHighScoreChanged                  // The high score changed
+	EventLivesChanged                      // A player gained or lost lives
+	EventWaveChanged                       // A new wave number began
+	EventModeChanged                       // The game moved to another mode
+	EventShake                             // Something hit hard enough to shake the screen
+)
+
+// String returns the name of the event type
+func (t EventType) String() string {
+	switch t {
+	case EventInvaderKilled:
+		return "InvaderKilled"
+	case EventPlayerHit:
+		return "PlayerHit"
+	case EventWaveCleared:
+		return "WaveCleared"
+	case EventUFOSpawned:
+		return "UFOSpawned"
+	case EventPowerUpCollected:
+		return "PowerUpCollected"
+	case EventScoreChanged:
+		return "ScoreChanged"
+	case EventHighScoreChanged:
+		return "HighScoreChanged"
+	case EventLivesChanged:
+		return "LivesChanged"
+	case EventWaveChanged:
+		return "WaveChanged"
+	case EventModeChanged:
+		return "ModeChanged"
+	case EventShake:
+		return "Shake"
+	default:
+		return "Unknown"
+	}
+}
+
+// Event describes something that happened during an engine update. Fields
+// that don't apply to the event type are left zero.
+type Event struct {
+	Type     EventType
+	Position Vector2     // where it happened
+	Player   int         // index of the player involved
+	Points   int         // points awarded, before any combo multiplier
+	Wave     int         // wave the event happened in
+	Invader  InvaderType // for EventInvaderKilled
+	Pickup   PickupKind  // for EventPowerUpCollected
+	Entity   EntityID    // the invader, ship, UFO or pickup involved
+	Value    int         // new score, high score, lives or wave for the change events
+	Mode     GameMode    // for EventModeChanged, the mode entered
+	Shake    float64     // for EventShake, how far to jolt the screen, in pixels
+}
+
+// EventHandler receives events emitted by the engine. Handlers run during
+// the update that raised the event, so they should only observe the game,
+// never change it.
+type EventHandler func(Event)
+
+// Subscribe registers a handler for events of the given types, or for every
+// event when no types are given
+func (e *Engine) Subscribe(handler EventHandler, types ...EventType) {
+	if len(types) == 0 {
+		e.allHandlers = append(e.allHandlers, handler)
+		return
+	}
+	if e.handlers == nil {
+		e.handlers = make(map[EventType][]EventHandler)
+	}
+	for _, eventType := range types {
+		e.handlers[eventType] = append(e.handlers[eventType], handler)
+	}
+}
+
+// emit delivers an event to its subscribers, stamped with the current wave
+func (e *Engine) emit(event Event) {
+	event.Wave = e.state.Wave
+	for _, handler := range e.handlers[event.Type] {
+		handler(event)
+	}
+	for _, handler := range e.allHandlers {
+		handler(event)
+	}
+}
+
+// How hard each kind of impact shakes the screen, in pixels
+const (
+	shakeBossHit     = 3.0
+	shakeSmartBomb   = 6.0
+	shakePlayerDeath = 8.0
+	shakeBossKilled  = 10.0
+)
+
+// shake asks the front-end to jolt the screen. The shake itself is left to
+// renderers, which can decay it at their own frame rate or ignore it.
+func (e *Engine) shake(strength float64) {
+	e.emit(Event{Type: EventShake, Shake: strength})
+}
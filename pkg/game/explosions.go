@@ -0,0 +1,72 @@
+package game
+
+// ExplosionKind is what blew up
+type ExplosionKind int
+
+const (
+	ExplosionInvader ExplosionKind = iota
+	ExplosionUFO
+	ExplosionBoss
+	ExplosionShip
+)
+
+// animation returns the clip a blast of this kind plays. A ship's blast is
+// slower, filling the player's death sequence.
+func (k ExplosionKind) animation() AnimationID {
+	if k == ExplosionShip {
+		return AnimExplosion
+	}
+	return AnimBlast
+}
+
+// Explosion is the blast left where an enemy or a ship was destroyed. Explosions live
+// in the game state, so every renderer and remote viewer shows the same
+// blast for the same time; each is gone once its animation has played.
+type Explosion struct {
+	ID       EntityID
+	Kind     ExplosionKind
+	Position Vector2
+	Size     float64 // width of the blast at its largest, in pixels
+	Anim     Animation
+}
+
+// NewExplosion creates a blast of the given size centered on the position
+func NewExplosion(kind ExplosionKind, x, y, size float64) *Explosion {
+	return &Explosion{
+		Kind:     kind,
+		Position: Vector2{X: x, Y: y},
+		Size:     size,
+		Anim:     NewAnimation(kind.animation()),
+	}
+}
+
+// Update plays the blast's animation
+func (x *Explosion) Update(deltaTime float64) {
+	x.Anim.Update(deltaTime)
+}
+
+// Done reports whether the blast has finished
+func (x *Explosion) Done() bool {
+	return x.Anim.Done
+}
+
+// explode leaves a blast where something of the given size was destroyed
+func (e *Engine) explode(kind ExplosionKind, position Vector2, size float64) {
+	explosion := NewExplosion(kind, position.X, position.Y, size)
+	e.state.Explosions = append(e.state.Explosions, explosion)
+	track(&e.state.Entities, explosion)
+}
+
+// updateExplosions plays each blast and drops the finished ones, compacting
+// the list in place
+func (e *Engine) updateExplosions(deltaTime float64) {
+	live := e.state.Explosions[:0]
+	for _, explosion := range e.state.Explosions {
+		explosion.Update(deltaTime)
+		if !explosion.Done() {
+			live = append(live, explosion)
+		}
+	}
+	clear(e.state.Explosions[len(live):])
+	e.state.Explosions = live
+}
@@ -0,0 +1,92 @@
+package game
+
+import (
+	"fmt"
+	"math"
+)
+
+// Formation is the overall shape the invaders are arranged in. Shapes carve
+// slots out of the wave's rectangular layout.
+type Formation string
+
+const (
+	FormationGrid    Formation = "grid"    // Every slot of the layout
+	FormationV       Formation = "v"       // A V opening toward the top of the screen
+	FormationDiamond Formation = "diamond" // A diamond centered on the layout
+	FormationColumns Formation = "columns" // Pairs of columns with gaps between them
+)
+
+// formations lists the shapes procedural waves choose from
+var formations = []Formation{FormationGrid, FormationV, FormationDiamond, FormationColumns}
+
+// validate checks the formation is a known shape; empty means grid
+func (f Formation) validate() error {
+	switch f {
+	case "", FormationGrid, FormationV, FormationDiamond, FormationColumns:
+		return nil
+	default:
+		return fmt.Errorf("unknown formation %q", f)
+	}
+}
+
+// Includes reports whether the slot at row, col of a rows x cols layout is
+// part of the shape
+func (f Formation) Includes(row, col, rows, cols int) bool {
+	centerCol := float64(cols-1) / 2
+	centerRow := float64(rows-1) / 2
+	dx := math.Abs(float64(col) - centerCol)
+
+	switch f {
+	case FormationV:
+		// Each arm steps in toward the center row by row
+		if rows < 2 {
+			return true
+		}
+		arm := centerCol * float64(rows-1-row) / float64(rows-1)
+		return math.Abs(dx-arm) <= 1
+	case FormationDiamond:
+		// Pad by half a slot so even-sized layouts keep their tips
+		dy := math.Abs(float64(row) - centerRow)
+		return dx/(centerCol+0.5)+dy/(centerRow+0.5) <= 1
+	case FormationColumns:
+		return col%3 != 2
+	default:
+		return true
+	}
+}
+
+// Apply returns a copy of the layout with slots outside the shape emptied
+func (f Formation) Apply(layout [][]InvaderType) [][]InvaderType {
+	shaped := make([][]InvaderType, len(layout))
+	for row, types := range layout {
+		shaped[row] = make([]InvaderType, len(types))
+		for col, invaderType := range types {
+			if !f.Includes(row, col, len(layout), len(types)) {
+				invaderType = InvaderTypeNone
+			}
+			shaped[row][col] = invaderType
+		}
+	}
+	return shaped
+}
+
+// FormationBounds returns the area covered by the invaders' formation slots,
+// whatever the shape. Diving invaders count at their slot so they don't
+// distort the formation.
+func FormationBounds(invaders []*Invader) Bounds {
+	if len(invaders) == 0 {
+		return Bounds{}
+	}
+
+	left, top := math.Inf(1), math.Inf(1)
+	right, bottom := math.Inf(-1), math.Inf(-1)
+	for _, invader := range invaders {
+		halfWidth := invader.Bounds.Width / 2
+		halfHeight := invader.Bounds.Height / 2
+		left = math.Min(left, invader.Home.X-halfWidth)
+		right = math.Max(right, invader.Home.X+halfWidth)
+		top = math.Min(top, invader.Home.Y-halfHeight)
+		bottom = math.Max(bottom, invader.Home.Y+halfHeight)
+	}
+	return Bounds{X: left, Y: top, Width: right - left, Height: bottom - top}
+}
@@ -0,0 +1,85 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Kinds of persisted data
+const (
+	formatSave       = "save"
+	formatSettings   = "settings"
+	formatReplay     = "replay"
+	formatHighScores = "highScores"
+)
+
+// Envelope wraps persisted data with its kind and format version, so data
+// stored by an older build can be recognized and migrated forward when the
+// engine's types change
+type Envelope struct {
+	Kind    string          `json:"kind"`
+	Version int             `json:"version"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// migration upgrades data from one format version to the next
+type migration func(data json.RawMessage) (json.RawMessage, error)
+
+// format is the current version of a kind of data and the migrations that
+// bring each older version up to the one after it
+type format struct {
+	version    int
+	migrations map[int]migration
+}
+
+// formats lists every kind of persisted data. When a change breaks a format,
+// bump its version and add a migration from the old version rather than
+// discarding what players have stored.
+var formats = map[string]format{
+	formatSave:       {version: 1},
+	formatSettings:   {version: 1},
+	formatReplay:     {version: 1},
+	formatHighScores: {version: 1},
+}
+
+// marshalVersioned encodes a value in an envelope at its format's current version
+func marshalVersioned(kind string, v any) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(Envelope{Kind: kind, Version: formats[kind].version, Data: data})
+}
+
+// unmarshalVersioned decodes an envelope of the given kind into v, migrating
+// older versions forward. Data stored before envelopes existed is taken as
+// version 1.
+func unmarshalVersioned(kind string, data []byte, v any) error {
+	var envelope Envelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return err
+	}
+	if envelope.Kind == "" {
+		envelope = Envelope{Kind: kind, Version: 1, Data: data}
+	}
+	if envelope.Kind != kind {
+		return fmt.Errorf("found %s data instead of %s", envelope.Kind, kind)
+	}
+
+	current := formats[kind]
+	if envelope.Version > current.version {
+		return fmt.Errorf("%s version %d is newer than this build supports", kind, envelope.Version)
+	}
+	raw := envelope.Data
+	for version := envelope.Version; version < current.version; version++ {
+		migrate, ok := current.migrations[version]
+		if !ok {
+			return fmt.Errorf("no migration for %s version %d", kind, version)
+		}
+		var err error
+		if raw, err = migrate(raw); err != nil {
+			return fmt.Errorf("failed to migrate %s version %d: %w", kind, version, err)
+		}
+	}
+	return json.Unmarshal(raw, v)
+}
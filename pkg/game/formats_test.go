@@ -0,0 +1,54 @@
+package game
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestVersionedFormats(t *testing.T) {
+	// Settings stored before envelopes existed still load
+	settings, err := ParseSettings([]byte(`{"difficulty":2,"volume":0.5}`))
+	if err != nil || settings.Difficulty != 2 || settings.Volume != 0.5 {
+		t.Errorf("legacy settings: got %+v, %v", settings, err)
+	}
+
+	// Data round-trips through its envelope
+	data, err := MarshalSettings(settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, err := ParseSettings(data); err != nil || again != settings {
+		t.Errorf("round trip: got %+v, %v", again, err)
+	}
+
+	// Data from a newer build or of another kind is refused
+	newer := []byte(`{"kind":"settings","version":99,"data":{}}`)
+	if _, err := ParseSettings(newer); err == nil {
+		t.Errorf("settings from a newer build were accepted")
+	}
+	if _, err := ParseReplay(data); err == nil || !strings.Contains(err.Error(), "settings") {
+		t.Errorf("settings parsed as a replay: %v", err)
+	}
+}
+
+func TestVersionedMigration(t *testing.T) {
+	formats["test"] = format{version: 3, migrations: map[int]migration{
+		1: func(data json.RawMessage) (json.RawMessage, error) {
+			return bytes.Replace(data, []byte(`"name"`), []byte(`"title"`), 1), nil
+		},
+		2: func(data json.RawMessage) (json.RawMessage, error) {
+			return bytes.Replace(data, []byte(`"title"`), []byte(`"label"`), 1), nil
+		},
+	}}
+	defer delete(formats, "test")
+
+	var v struct {
+		Label string `json:"label"`
+	}
+	data := []byte(`{"kind":"test","version":1,"data":{"name":"bobn"}}`)
+	if err := unmarshalVersioned("test", data, &v); err != nil || v.Label != "bobn" {
+		t.Errorf("migrated from version 1: got %q, %v", v.Label, err)
+	}
+}
@@ -0,0 +1,86 @@
+package game
+
+// maxGhostFrames caps a recording at twenty minutes of 20Hz samples
+const maxGhostFrames = 20 * 60 * 20
+
+// ghostShotSpeed matches the player's standard bullet speed
+const ghostShotSpeed = 400.0
+
+// GhostFrame is one fixed-update sample of player one's ship
+type GhostFrame struct {
+	X, Y  float64
+	Alive bool
+	Fired bool
+}
+
+// Ghost replays a recorded run as a translucent ship
+type Ghost struct {
+	Frames []GhostFrame
+	Score  int       // final score of the recorded run
+	Frame  int       // index of the sample being shown
+	Shots  []Vector2 // replayed shots still on screen
+}
+
+// Current returns the sample being shown, or false once the recording has ended
+func (g *Ghost) Current() (GhostFrame, bool) {
+	if g.Frame >= len(g.Frames) {
+		return GhostFrame{}, false
+	}
+	return g.Frames[g.Frame], true
+}
+
+// Advance steps the replay by one sample, moving its shots by dy per second
+// (negative moves up the screen)
+func (g *Ghost) Advance(deltaTime, dy float64, screenHeight int) {
+	liveShots := g.Shots[:0]
+	for _, shot := range g.Shots {
+		shot.Y += dy * deltaTime
+		if shot.Y >= 0 && shot.Y <= float64(screenHeight) {
+			liveShots = append(liveShots, shot)
+		}
+	}
+	g.Shots = liveShots
+
+	if frame, ok := g.Current(); ok && frame.Fired {
+		g.Shots = append(g.Shots, Vector2{X: frame.X, Y: frame.Y})
+	}
+	g.Frame++
+}
+
+// startGhost begins recording this run and loads the best run to race against
+func (gs *GameState) startGhost() {
+	gs.GhostRecording = nil // snapshots may still share the last recording
+	gs.Ghost = nil
+	if gs.Options.Ghost && gs.BestGhost != nil {
+		gs.Ghost = &Ghost{Frames: gs.BestGhost.Frames, Score: gs.BestGhost.Score}
+	}
+}
+
+// recordGhostFrame samples player one's ship for the current run
+func (gs *GameState) recordGhostFrame(fired bool) {
+	player := gs.PrimaryPlayer()
+	if player == nil || player.Ship == nil || len(gs.GhostRecording) >= maxGhostFrames {
+		return
+	}
+	gs.GhostRecording = append(gs.GhostRecording, GhostFrame{
+		X:     player.Ship.Position.X,
+		Y:     player.Ship.Position.Y,
+		Alive: player.Ship.Alive,
+		Fired: fired,
+	})
+}
+
+// saveGhost keeps the finished run as the ghost if it beat the best one
+func (gs *GameState) saveGhost() {
+	player := gs.PrimaryPlayer()
+	if player == nil || !gs.IsCompetitive() || len(gs.GhostRecording) == 0 {
+		return
+	}
+	if gs.BestGhost != nil && player.Score <= gs.BestGhost.Score {
+		return
+	}
+
+	frames := make([]GhostFrame, len(gs.GhostRecording))
+	copy(frames, gs.GhostRecording)
+	gs.BestGhost = &Ghost{Frames: frames, Score: player.Score}
+}
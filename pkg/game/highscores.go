@@ -0,0 +1,108 @@
+package game
+
+import (
+	"fmt"
+	"slices"
+)
+
+// HighScoreTableSize is how many scores the high score table keeps
+const HighScoreTableSize = 10
+
+// HighScoreEntry is one score on the high score table
+type HighScoreEntry struct {
+	Name  string `json:"name"` // the player's initials
+	Score int    `json:"score"`
+	Wave  int    `json:"wave"` // the wave the run reached
+}
+
+// HighScoreTable is the best regular scores, highest first, persisted
+// between sessions. Daily challenge scores are kept apart.
+type HighScoreTable []HighScoreEntry
+
+// Qualifies reports whether a score would make the table
+func (t HighScoreTable) Qualifies(score int) bool {
+	return score > 0 && (len(t) < HighScoreTableSize || score > t[len(t)-1].Score)
+}
+
+// With returns a copy of the table with the entry ranked in, and the index
+// it landed at, or -1 when it didn't make the table. An entry ties below
+// the scores already there.
+func (t HighScoreTable) With(entry HighScoreEntry) (HighScoreTable, int) {
+	if !t.Qualifies(entry.Score) {
+		return slices.Clone(t), -1
+	}
+	rank, _ := slices.BinarySearchFunc(t, entry.Score, func(e HighScoreEntry, score int) int {
+		// Ordered highest first, with equal scores before the new one
+		if e.Score >= score {
+			return -1
+		}
+		return 1
+	})
+	table := slices.Insert(slices.Clone(t), rank, entry)
+	return table[:min(len(table), HighScoreTableSize)], rank
+}
+
+// sanitized orders the table and trims it to size
+func (t HighScoreTable) sanitized() HighScoreTable {
+	t = slices.DeleteFunc(slices.Clone(t), func(e HighScoreEntry) bool { return e.Score <= 0 })
+	slices.SortStableFunc(t, func(a, b HighScoreEntry) int { return b.Score - a.Score })
+	return t[:min(len(t), HighScoreTableSize)]
+}
+
+// ParseHighScores parses a stored high score table
+func ParseHighScores(data []byte) (HighScoreTable, error) {
+	var table HighScoreTable
+	if err := unmarshalVersioned(formatHighScores, data, &table); err != nil {
+		return nil, fmt.Errorf("failed to parse high scores: %w", err)
+	}
+	return table.sanitized(), nil
+}
+
+// MarshalHighScores encodes a high score table for storage
+func MarshalHighScores(table HighScoreTable) ([]byte, error) {
+	return marshalVersioned(formatHighScores, table)
+}
+
+// ApplyHighScores adopts a high score table, for example one loaded from
+// storage, raising the high score to its top entry
+func (e *Engine) ApplyHighScores(table HighScoreTable) {
+	gs := e.state
+	gs.HighScores = table.sanitized()
+	if len(gs.HighScores) > 0 {
+		gs.HighScore = max(gs.HighScore, gs.HighScores[0].Score)
+	}
+}
+
+// TakeHighScoresChange returns the high score table if a score was added
+// since the last call, so the front-end can persist it
+func (e *Engine) TakeHighScoresChange() (HighScoreTable, bool) {
+	changed := e.highScoresChanged
+	e.highScoresChanged = false
+	return e.state.HighScores, changed
+}
+
+// HighScoreEntry returns the run's entry for the table, signed with the
+// initials as they stand
+func (gs *GameState) HighScoreEntry() HighScoreEntry {
+	entry := HighScoreEntry{Name: gs.signedInitials(), Wave: gs.Wave}
+	if player := gs.PrimaryPlayer(); player != nil {
+		entry.Score = player.Score
+	}
+	return entry
+}
+
+// madeHighScoreTable reports whether the finished run earned a place on the
+// table, which only regular competitive games can
+func (gs *GameState) madeHighScoreTable() bool {
+	if !gs.IsCompetitive() || gs.Options.Daily {
+		return false
+	}
+	return gs.HighScores.Qualifies(gs.HighScoreEntry().Score)
+}
+
+// recordHighScore puts the run's entry on the table
+func (e *Engine) recordHighScore() {
+	gs := e.state
+	gs.HighScores, gs.HighScoreRank = gs.HighScores.With(gs.HighScoreEntry())
+	e.highScoresChanged = true
+}
@@ -0,0 +1,37 @@
+package game
+
+import "testing"
+
+func TestHighScoreTableRanking(t *testing.T) {
+	var table HighScoreTable
+	for score := 100; score <= 1000; score += 100 {
+		table, _ = table.With(HighScoreEntry{Name: "AAA", Score: score, Wave: 1})
+	}
+	if len(table) != HighScoreTableSize || table[0].Score != 1000 || table[9].Score != 100 {
+		t.Fatalf("table not ordered highest first: %+v", table)
+	}
+
+	// A tie ranks below the score already there, and the lowest drops off
+	tied, rank := table.With(HighScoreEntry{Name: "BBB", Score: 500})
+	if rank != 6 || tied[rank].Name != "BBB" || len(tied) != HighScoreTableSize || tied[9].Score != 200 {
+		t.Errorf("tie ranked at %d: %+v", rank, tied)
+	}
+	if table[6].Name != "AAA" {
+		t.Errorf("With changed the original table: %+v", table)
+	}
+
+	// A score below the whole full table doesn't make it
+	if _, rank := table.With(HighScoreEntry{Score: 50}); rank != -1 || table.Qualifies(100) {
+		t.Errorf("a score below the table qualified at %d", rank)
+	}
+
+	// Stored tables come back ordered and trimmed
+	data, err := MarshalHighScores(append(tied, HighScoreEntry{Score: 5000}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := ParseHighScores(data)
+	if err != nil || len(loaded) != HighScoreTableSize || loaded[0].Score != 5000 {
+		t.Errorf("loaded %+v, %v", loaded, err)
+	}
+}
@@ -0,0 +1,155 @@
+package game
+
+// HotseatPlayers is the number of players taking turns in a hotseat game
+const HotseatPlayers = 2
+
+// PlayerSlot holds one player's progress while another player has the turn
+type PlayerSlot struct {
+	Score      int
+	Lives      int
+	Wave       int
+	SmartBombs int
+	ShotsFired int
+
+	// Formation state, preserved exactly between turns
+	Invaders []*Invader
+	Boss     *Boss
+	Barriers [][]bool
+	Debris   []*Debris
+	WaveSpec WaveSpec
+
+	// Distance the formation has dropped, for scoring
+	FormationDrop float64
+
+	// Challenge progress for the slot's current wave
+	Challenge       *Challenge
+	ChallengeStatus ChallengeStatus
+	ChallengeTime   float64
+}
+
+// IsMultiplayer reports whether players are taking turns
+func (gs *GameState) IsMultiplayer() bool {
+	return len(gs.Slots) > 1
+}
+
+// initializeSlots gives every hotseat player a fresh game of their own
+func (gs *GameState) initializeSlots() {
+	gs.Slots = nil
+	gs.CurrentSlot = 0
+	if !gs.Options.TwoPlayer || gs.Options.Coop {
+		return // hotseat players share the single ship
+	}
+
+	gs.Slots = make([]PlayerSlot, HotseatPlayers)
+	for i := len(gs.Slots) - 1; i >= 0; i-- {
+		gs.initializeWave()
+		gs.initializeBarriers()
+		gs.CurrentSlot = i
+		gs.saveSlot()
+	}
+	gs.loadSlot()
+}
+
+// saveSlot stores the active player's progress in their slot
+func (gs *GameState) saveSlot() {
+	player := gs.PrimaryPlayer()
+	gs.Slots[gs.CurrentSlot] = PlayerSlot{
+		Score:           player.Score,
+		Lives:           player.Lives,
+		Wave:            gs.Wave,
+		SmartBombs:      player.SmartBombs,
+		ShotsFired:      gs.ShotsFired,
+		Invaders:        gs.Invaders,
+		Boss:            gs.Boss,
+		Barriers:        gs.Barriers,
+		Debris:          gs.Debris,
+		WaveSpec:        gs.WaveSpec,
+		FormationDrop:   gs.FormationDrop,
+		Challenge:       gs.Challenge,
+		ChallengeStatus: gs.ChallengeStatus,
+		ChallengeTime:   gs.ChallengeTime,
+	}
+}
+
+// loadSlot restores the current slot's progress into the active game
+func (gs *GameState) loadSlot() {
+	slot := gs.Slots[gs.CurrentSlot]
+	player := gs.PrimaryPlayer()
+	player.Score = slot.Score
+	player.Lives = slot.Lives
+	gs.Wave = slot.Wave
+	player.SmartBombs = slot.SmartBombs
+	gs.ShotsFired = slot.ShotsFired
+	gs.Invaders = slot.Invaders
+	gs.Boss = slot.Boss
+	gs.Barriers = slot.Barriers
+	gs.Debris = slot.Debris
+	gs.WaveSpec = slot.WaveSpec
+	gs.FormationDrop = slot.FormationDrop
+	gs.Challenge = slot.Challenge
+	gs.ChallengeStatus = slot.ChallengeStatus
+	gs.ChallengeTime = slot.ChallengeTime
+
+	// Divers snap back to their slots for the new turn
+	for _, invader := range gs.Invaders {
+		invader.Position = invader.Home
+		invader.MoveState = InvaderInFormation
+		invader.updateBounds()
+	}
+}
+
+// nextSlotWithLives returns the next player after the current one who still
+// has lives, or -1 if nobody else can play
+func (gs *GameState) nextSlotWithLives() int {
+	for offset := 1; offset < len(gs.Slots); offset++ {
+		next := (gs.CurrentSlot + offset) % len(gs.Slots)
+		if gs.Slots[next].Lives > 0 {
+			return next
+		}
+	}
+	return -1
+}
+
+// HasPlayersRemaining reports whether any player can still take a turn
+func (gs *GameState) HasPlayersRemaining() bool {
+	for _, player := range gs.Players {
+		if player.Lives > 0 {
+			return true
+		}
+	}
+	return gs.IsMultiplayer() && gs.nextSlotWithLives() >= 0
+}
+
+// NextTurn hands play to the next player with lives left. It returns false
+// when the current player keeps the turn.
+func (gs *GameState) NextTurn() bool {
+	if !gs.IsMultiplayer() {
+		return false
+	}
+	next := gs.nextSlotWithLives()
+	if next < 0 {
+		return false
+	}
+
+	gs.saveSlot()
+	gs.CurrentSlot = next
+	gs.loadSlot()
+
+	// The new turn starts with a clean screen
+	gs.Bullets = []*Bullet{}
+	gs.UFO = nil
+	gs.ScorePopups = []*ScorePopup{}
+	gs.Pickups = []*Pickup{}
+	gs.Beams = []*Beam{}
+	gs.ResetCombo()
+	gs.resetWaveStats()
+	return true
+}
+
+// SlotScore returns the score for the given player, including the one whose turn it is
+func (gs *GameState) SlotScore(slot int) int {
+	if slot == gs.CurrentSlot {
+		return gs.PrimaryPlayer().Score
+	}
+	return gs.Slots[slot].Score
+}
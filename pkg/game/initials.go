@@ -0,0 +1,70 @@
+package game
+
+import "strings"
+
+// InitialsLength is how many letters sign a high score
+const InitialsLength = 3
+
+// InitialsAlphabet is what each initial is picked from, in the order up and
+// down step through it
+const InitialsAlphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789 "
+
+// startInitials readies the high score screen, starting from the initials
+// signed last so a returning player need only confirm them
+func (gs *GameState) startInitials() {
+	gs.InitialsCursor = 0
+	for i := range gs.Initials {
+		gs.Initials[i] = InitialsAlphabet[0]
+		if i < len(gs.LastInitials) && strings.IndexByte(InitialsAlphabet, gs.LastInitials[i]) >= 0 {
+			gs.Initials[i] = gs.LastInitials[i]
+		}
+	}
+}
+
+// processInitialsCommands enters initials on the high score screen. Up and
+// down change the letter under the cursor, fresh left and right presses move
+// the cursor, and select moves on to the next letter, signing the board
+// after the last.
+func (e *Engine) processInitialsCommands(commands Commands, leftJustPressed, rightJustPressed bool) {
+	gs := e.state
+	switch {
+	case commands.selected():
+		e.enterInitial()
+	case leftJustPressed:
+		gs.InitialsCursor = max(gs.InitialsCursor-1, 0)
+	case rightJustPressed:
+		gs.InitialsCursor = min(gs.InitialsCursor+1, InitialsLength-1)
+	case commands.Up, commands.Down:
+		// Up runs forward through the alphabet, as on an arcade cabinet
+		letter := strings.IndexByte(InitialsAlphabet, gs.Initials[gs.InitialsCursor])
+		letter = stepSelection(letter, len(InitialsAlphabet), commands.Down, commands.Up)
+		gs.Initials[gs.InitialsCursor] = InitialsAlphabet[letter]
+	}
+}
+
+// PointInitials picks the letter under the cursor from a head position,
+// mapping analogY (-1 top to 1 bottom) across the alphabet
+func (e *Engine) PointInitials(analogY float64) {
+	if e.state.Mode != HighScore {
+		return
+	}
+	e.state.Initials[e.state.InitialsCursor] = InitialsAlphabet[pointSelection(analogY, len(InitialsAlphabet))]
+}
+
+// enterInitial accepts the letter under the cursor, putting the run on the
+// high score table once the last one is in
+func (e *Engine) enterInitial() {
+	gs := e.state
+	if gs.InitialsCursor < InitialsLength-1 {
+		gs.InitialsCursor++
+		return
+	}
+	gs.LastInitials = gs.signedInitials()
+	e.recordHighScore()
+	e.setMode(Summary)
+}
+
+// signedInitials returns the initials entered, without trailing blanks
+func (gs *GameState) signedInitials() string {
+	return strings.TrimRight(string(gs.Initials[:]), " ")
+}
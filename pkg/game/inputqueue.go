@@ -0,0 +1,101 @@
+package game
+
+// InputEvent is a key going down or up at a moment on the front-end's clock
+type InputEvent struct {
+	Key  string
+	Down bool
+	Time float64 // seconds
+}
+
+// InputQueue buffers timestamped key events between fixed ticks. Sampling
+// which keys are down once per tick drops a tap that starts and ends between
+// two ticks; draining the queue per tick sees every press, and splits a burst
+// of presses across the ticks they happened in.
+type InputQueue struct {
+	events []InputEvent
+	down   map[string]bool
+}
+
+// KeyFrame is what the keys did over one tick
+type KeyFrame struct {
+	held    map[string]bool
+	Presses []string // keys that went down during the tick, in order
+}
+
+// NewInputQueue creates an empty input queue with every key up
+func NewInputQueue() *InputQueue {
+	return &InputQueue{down: make(map[string]bool)}
+}
+
+// Push records a key event. Events must arrive in time order.
+func (q *InputQueue) Push(event InputEvent) {
+	q.events = append(q.events, event)
+}
+
+// ReleaseAll records every key going up, for when the page loses focus and
+// the releases will never arrive
+func (q *InputQueue) ReleaseAll(time float64) {
+	// Keys pressed since the last tick are down too
+	down := make(map[string]bool, len(q.down))
+	for key, isDown := range q.down {
+		down[key] = isDown
+	}
+	for _, event := range q.events {
+		down[event.Key] = event.Down
+	}
+	for key, isDown := range down {
+		if isDown {
+			q.Push(InputEvent{Key: key, Time: time})
+		}
+	}
+}
+
+// Drain consumes the events up to and including the given time and returns
+// what the keys did over the tick ending then. Later events stay queued for
+// the next tick.
+func (q *InputQueue) Drain(until float64) KeyFrame {
+	frame := KeyFrame{held: make(map[string]bool)}
+	for key, down := range q.down {
+		frame.held[key] = down
+	}
+
+	n := 0
+	for _, event := range q.events {
+		if event.Time > until {
+			break
+		}
+		n++
+
+		// Repeats of a key already down aren't new presses
+		if event.Down && !q.down[event.Key] {
+			frame.Presses = append(frame.Presses, event.Key)
+			frame.held[event.Key] = true
+		}
+		q.down[event.Key] = event.Down
+	}
+	q.events = append(q.events[:0], q.events[n:]...)
+	return frame
+}
+
+// Held reports whether any of the keys was down at some point during the
+// tick, so a tap between ticks still counts as held for one tick
+func (f KeyFrame) Held(keys ...string) bool {
+	for _, key := range keys {
+		if f.held[key] {
+			return true
+		}
+	}
+	return false
+}
+
+// Pressed reports whether any of the keys went down during the tick
+func (f KeyFrame) Pressed(keys ...string) bool {
+	for _, press := range f.Presses {
+		for _, key := range keys {
+			if press == key {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,39 @@
+package game
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestInputQueueDrain(t *testing.T) {
+	q := NewInputQueue()
+	q.Push(InputEvent{Key: "Space", Down: true, Time: 0.01})
+	q.Push(InputEvent{Key: "Space", Time: 0.02})
+	q.Push(InputEvent{Key: "ArrowLeft", Down: true, Time: 0.03})
+	q.Push(InputEvent{Key: "ArrowLeft", Down: true, Time: 0.04}) // key repeat
+	q.Push(InputEvent{Key: "Space", Down: true, Time: 0.07})
+
+	// A tap that started and ended inside the tick still counts
+	frame := q.Drain(0.05)
+	if !frame.Pressed("Space") || !frame.Held("Space") {
+		t.Errorf("tap between ticks was dropped")
+	}
+	if !slices.Equal(frame.Presses, []string{"Space", "ArrowLeft"}) {
+		t.Errorf("presses = %v, want [Space ArrowLeft]", frame.Presses)
+	}
+
+	// The second press belongs to the next tick, and the held key carries over
+	frame = q.Drain(0.10)
+	if !frame.Pressed("Space") || !frame.Held("ArrowLeft") || frame.Pressed("ArrowLeft") {
+		t.Errorf("second tick: presses %v, left held %v", frame.Presses, frame.Held("ArrowLeft"))
+	}
+
+	// Losing focus lets go of everything once the tick it happened in ends
+	q.ReleaseAll(0.11)
+	if frame = q.Drain(0.15); !frame.Held("ArrowLeft") || !frame.Held("Space") {
+		t.Errorf("keys released mid-tick weren't held for it")
+	}
+	if frame = q.Drain(0.20); frame.Held("ArrowLeft", "Space") {
+		t.Errorf("keys still held after releasing all")
+	}
+}
@@ -0,0 +1,20 @@
+package game
+
+// stepSelection moves a menu selection one entry up or down, wrapping
+// around the ends of a menu of count entries
+func stepSelection(selection, count int, upJustPressed, downJustPressed bool) int {
+	switch {
+	case upJustPressed:
+		return (selection + count - 1) % count
+	case downJustPressed:
+		return (selection + 1) % count
+	}
+	return selection
+}
+
+// pointSelection picks the entry under a head position, mapping analogY
+// (-1 top to 1 bottom) evenly across a menu of count entries
+func pointSelection(analogY float64, count int) int {
+	selection := int((analogY + 1) / 2 * float64(count))
+	return max(0, min(selection, count-1))
+}
@@ -0,0 +1,66 @@
+package game
+
+import "time"
+
+// metricsSmoothing is the weight each new tick gets in the average tick time
+const metricsSmoothing = 0.05
+
+// Metrics describes where simulation time goes, for debug overlays and
+// server monitoring. Timings are wall-clock time spent inside the engine;
+// they never feed back into the simulation.
+type Metrics struct {
+	Ticks           int           // fixed ticks run since the metrics were reset
+	TickTime        time.Duration // time spent on the last tick
+	AverageTickTime time.Duration // smoothed time per tick
+	MaxTickTime     time.Duration // slowest tick since the metrics were reset
+
+	// Entities in play after the last tick
+	Entities int // all entities, including the ones counted below
+	Invaders int
+	Bullets  int
+	Pickups  int
+	Debris   int
+
+	// Collision work done in the last tick
+	CollisionQueries int // broad-phase grid queries
+	CollisionChecks  int // exact checks between pairs of entities
+}
+
+// Metrics returns the engine's performance metrics as of the last tick. Like
+// GetState, it is for the goroutine driving the engine.
+func (e *Engine) Metrics() Metrics {
+	return e.metrics
+}
+
+// ResetMetrics clears the tick count and the slowest tick
+func (e *Engine) ResetMetrics() {
+	e.metrics = Metrics{}
+}
+
+// beginTick starts measuring a tick
+func (e *Engine) beginTick() time.Time {
+	e.collisions.ResetCounts()
+	return time.Now()
+}
+
+// endTick records the time and work of a tick that began at start
+func (e *Engine) endTick(start time.Time) {
+	m := &e.metrics
+	m.TickTime = time.Since(start)
+	if m.Ticks == 0 {
+		m.AverageTickTime = m.TickTime
+	} else {
+		m.AverageTickTime += time.Duration(metricsSmoothing * float64(m.TickTime-m.AverageTickTime))
+	}
+	m.MaxTickTime = max(m.MaxTickTime, m.TickTime)
+	m.Ticks++
+
+	gs := e.state
+	m.Entities = 0
+	gs.EachEntity(func(Entity) { m.Entities++ })
+	m.Invaders = len(gs.Invaders)
+	m.Bullets = len(gs.Bullets)
+	m.Pickups = len(gs.Pickups)
+	m.Debris = len(gs.Debris)
+	m.CollisionQueries, m.CollisionChecks = e.collisions.Counts()
+}
@@ -0,0 +1,133 @@
+package game
+
+import "slices"
+
+// modeTransitions lists the modes each mode may move to. A new game may start
+// from any screen; every other move follows the flow of a game from the
+// title screen to the results and back.
+var modeTransitions = map[GameMode][]GameMode{
+	AttractMode:  {Playing, SettingsMenu},
+	Playing:      {Playing, Continue, GameOver, SettingsMenu, AttractMode},
+	Continue:     {Playing, GameOver},
+	GameOver:     {Playing, HighScore, Summary, AttractMode},
+	Summary:      {Playing, AttractMode},
+	HighScore:    {Playing, Summary, AttractMode},
+	SettingsMenu: {Playing, AttractMode},
+}
+
+// CanTransition reports whether the game may move from one mode to another
+func CanTransition(from, to GameMode) bool {
+	return slices.Contains(modeTransitions[from], to)
+}
+
+// setMode moves the game to another mode, running the exit hook of the mode
+// it leaves and the enter hook of the mode it enters. Every mode change goes
+// through here; it reports false, leaving the mode alone, for a move the
+// state machine doesn't allow.
+func (e *Engine) setMode(to GameMode) bool {
+	from := e.state.Mode
+	if !CanTransition(from, to) {
+		return false
+	}
+	e.exitMode(from, to)
+	e.state.Mode = to
+	e.enterMode(from, to)
+	return true
+}
+
+// exitMode tidies up the mode being left
+func (e *Engine) exitMode(from, to GameMode) {
+	gs := e.state
+	switch from {
+	case Playing:
+		// The pause menu stays open behind the settings screen
+		if to != SettingsMenu {
+			gs.Paused = false
+		}
+	case Continue:
+		gs.ContinueTimer = 0
+	}
+}
+
+// enterMode sets up the mode being entered
+func (e *Engine) enterMode(from, to GameMode) {
+	gs := e.state
+	switch to {
+	case AttractMode:
+		e.showTitle()
+		if from == SettingsMenu {
+			return
+		}
+
+		// A finished demo hands the player's own options back and moves
+		// the rotation on past the title it followed
+		if gs.Demo {
+			gs.Demo = false
+			gs.Options = e.demoOptions
+			gs.AttractScreen = AttractHighScores
+		}
+		gs.clearGame()
+	case Continue:
+		// Record the score first so the halved continue score can never
+		// cost the player their high score
+		gs.ContinueTimer = ContinueCountdown
+		gs.updateHighScore()
+	case GameOver:
+		gs.GameEnded = true
+		gs.updateHighScore()
+
+		// Keep this run as the ghost to beat
+		gs.saveGhost()
+	case HighScore:
+		gs.startInitials()
+	case SettingsMenu:
+		gs.SettingsReturn = from
+		gs.SettingsSelection = SettingDifficulty
+	}
+}
+
+// endGame ends a lost game, offering a continue when one is allowed
+func (e *Engine) endGame() {
+	if e.state.canContinue() {
+		e.setMode(Continue)
+		return
+	}
+	e.setMode(GameOver)
+}
+
+// finishGameOver moves on from the game over screen, by way of the high
+// score screen when the run made the high score table
+func (e *Engine) finishGameOver() {
+	if e.state.madeHighScoreTable() {
+		e.setMode(HighScore)
+		return
+	}
+	e.setMode(Summary)
+}
+
+// processScreenInput handles start presses on the screens around a game,
+// the same way for every input method. It reports false while playing or in
+// the settings and high score screens, which handle their own input.
+func (e *Engine) processScreenInput(startPressed bool) bool {
+	switch e.state.Mode {
+	case AttractMode:
+		if startPressed {
+			e.StartNewGame()
+		}
+	case Continue:
+		if startPressed {
+			e.continueGame()
+		}
+	case GameOver:
+		if startPressed {
+			e.finishGameOver()
+		}
+	case Summary:
+		if startPressed {
+			e.setMode(AttractMode)
+		}
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,67 @@
+package game
+
+// Orientation describes which way the playfield faces
+type Orientation int
+
+const (
+	OrientationNormal   Orientation = iota // Invaders descend toward a player at the bottom
+	OrientationMirrored                    // Invaders rise toward a player at the top
+)
+
+// String returns the string representation of the orientation
+func (o Orientation) String() string {
+	switch o {
+	case OrientationNormal:
+		return "Normal"
+	case OrientationMirrored:
+		return "Mirrored"
+	default:
+		return "Unknown"
+	}
+}
+
+// Advance returns the screen-space sign of the direction invaders advance:
+// +1 (down) normally, -1 (up) when mirrored
+func (o Orientation) Advance() float64 {
+	if o == OrientationMirrored {
+		return -1
+	}
+	return 1
+}
+
+// Y maps a y coordinate from the classic top-down layout onto the screen.
+// The mapping is its own inverse, so it also converts screen positions back
+// into layout depth.
+func (o Orientation) Y(y float64, screenHeight int) float64 {
+	if o == OrientationMirrored {
+		return float64(screenHeight) - y
+	}
+	return y
+}
+
+// SpanTop maps the top edge of a vertical span of the classic layout onto the screen
+func (o Orientation) SpanTop(top, height float64, screenHeight int) float64 {
+	if o == OrientationMirrored {
+		return float64(screenHeight) - top - height
+	}
+	return top
+}
+
+// Orientation returns the playfield orientation for the current game
+func (gs *GameState) Orientation() Orientation {
+	if gs.Options.Mirror {
+		return OrientationMirrored
+	}
+	return OrientationNormal
+}
+
+// Depth returns how far a screen y coordinate has advanced toward the player,
+// measured in the classic top-down layout
+func (gs *GameState) Depth(y float64) float64 {
+	return gs.Orientation().Y(y, gs.ScreenHeight)
+}
+
+// ScreenY maps a y coordinate from the classic top-down layout onto the screen
+func (gs *GameState) ScreenY(y float64) float64 {
+	return gs.Orientation().Y(y, gs.ScreenHeight)
+}
@@ -0,0 +1,175 @@
+package game
+
+import "math"
+
+// maxParticles caps the particles in play; bursts beyond it are cut short
+const maxParticles = 512
+
+// ParticleKind is what a particle is a piece of, for renderers to color it
+type ParticleKind int
+
+const (
+	ParticleSpark    ParticleKind = iota // an invader blowing apart
+	ParticleRubble                       // barrier blocks knocked loose
+	ParticleExhaust                      // a ship's engine trail
+	ParticleWreckage                     // a player's ship blown apart
+	ParticleTrail                        // the UFO's engine wake
+)
+
+// Particle is a short-lived speck for explosions, impacts and engine
+// exhaust. Particles are purely cosmetic: they never collide, and they draw
+// from their own random stream so spawning them never changes how a run
+// plays out. They are plain values so the list reuses its storage.
+type Particle struct {
+	Kind     ParticleKind
+	Variant  int // the invader type for sparks
+	Position Vector2
+	Velocity Vector2
+	Gravity  float64 // downward pull, in pixels per second squared
+	Life     float64 // seconds left
+	MaxLife  float64
+	Size     float64
+}
+
+// Fade returns how much of the particle's life is left, from 1 down to 0
+func (p Particle) Fade() float64 {
+	if p.MaxLife <= 0 {
+		return 0
+	}
+	return max(p.Life/p.MaxLife, 0)
+}
+
+// particleBurst describes a spray of particles
+type particleBurst struct {
+	kind    ParticleKind
+	variant int
+	count   int
+	speed   float64 // fastest a particle flies out, in pixels per second
+	spread  float64 // angle of the spray around its heading, in radians
+	heading float64 // direction of the spray, in radians; 0 points right
+	gravity float64
+	life    float64
+	size    float64
+}
+
+// spawnParticles sprays a burst of particles from the position
+func (e *Engine) spawnParticles(burst particleBurst, position Vector2) {
+	gs := e.state
+	rng := e.particleRNG
+	for range burst.count {
+		if len(gs.Particles) >= maxParticles {
+			return
+		}
+		angle := burst.heading + (rng.Float64()-0.5)*burst.spread
+		speed := burst.speed * (0.3 + 0.7*rng.Float64())
+		life := burst.life * (0.5 + 0.5*rng.Float64())
+		gs.Particles = append(gs.Particles, Particle{
+			Kind:     burst.kind,
+			Variant:  burst.variant,
+			Position: position,
+			Velocity: Vector2{X: math.Cos(angle) * speed, Y: math.Sin(angle) * speed},
+			Gravity:  burst.gravity,
+			Life:     life,
+			MaxLife:  life,
+			Size:     burst.size,
+		})
+	}
+}
+
+// updateParticles moves the particles and drops the ones that have faded,
+// compacting the list in place
+func (e *Engine) updateParticles(deltaTime float64) {
+	live := e.state.Particles[:0]
+	for _, particle := range e.state.Particles {
+		particle.Life -= deltaTime
+		if particle.Life <= 0 {
+			continue
+		}
+		particle.Velocity.Y += particle.Gravity * deltaTime
+		particle.Position = particle.Position.Add(particle.Velocity.Scale(deltaTime))
+		live = append(live, particle)
+	}
+	e.state.Particles = live
+}
+
+// explodeInvader blows an invader apart in sparks of its own color
+func (e *Engine) explodeInvader(invader *Invader) {
+	e.spawnParticles(particleBurst{
+		kind:    ParticleSpark,
+		variant: int(invader.Type),
+		count:   14,
+		speed:   140,
+		spread:  2 * math.Pi,
+		gravity: 120,
+		life:    0.6,
+		size:    2,
+	}, invader.Position)
+}
+
+// ufoTrail leaves a faint wake behind the UFO
+func (e *Engine) ufoTrail() {
+	ufo := e.state.UFO
+	if ufo == nil || !ufo.Alive {
+		return
+	}
+	back := -float64(ufo.Direction)
+	tail := Vector2{X: ufo.Position.X + back*ufo.Bounds.Width/2, Y: ufo.Position.Y}
+	e.spawnParticles(particleBurst{
+		kind:    ParticleTrail,
+		count:   1,
+		speed:   30,
+		spread:  math.Pi / 4,
+		heading: math.Atan2(0, back),
+		life:    0.4,
+		size:    2,
+	}, tail)
+}
+
+// wreckShip scatters the pieces of a destroyed ship
+func (e *Engine) wreckShip(ship *PlayerShip) {
+	e.spawnParticles(particleBurst{
+		kind:    ParticleWreckage,
+		count:   20,
+		speed:   110,
+		spread:  2 * math.Pi,
+		gravity: 160,
+		life:    0.9,
+		size:    3,
+	}, ship.Position)
+}
+
+// chipBarrier knocks rubble off a barrier back the way the bullet came
+func (e *Engine) chipBarrier(bullet *Bullet) {
+	heading := math.Atan2(-bullet.Velocity.Y, -bullet.Velocity.X)
+	e.spawnParticles(particleBurst{
+		kind:    ParticleRubble,
+		count:   6,
+		speed:   90,
+		spread:  math.Pi / 2,
+		heading: heading,
+		gravity: 240,
+		life:    0.4,
+		size:    2,
+	}, bullet.Position)
+}
+
+// exhaust trails engine sparks behind each active ship
+func (e *Engine) exhaust() {
+	advance := e.state.Orientation().Advance()
+	for _, player := range e.state.Players {
+		if !player.IsActive() {
+			continue
+		}
+		ship := player.Ship
+		tail := Vector2{X: ship.Position.X, Y: ship.Position.Y + advance*ship.Bounds.Height/2}
+		e.spawnParticles(particleBurst{
+			kind:    ParticleExhaust,
+			count:   1,
+			speed:   60,
+			spread:  math.Pi / 6,
+			heading: math.Atan2(advance, 0),
+			life:    0.25,
+			size:    1.5,
+		}, tail)
+	}
+}
@@ -0,0 +1,78 @@
+package game
+
+// PauseOption is an entry in the pause menu
+type PauseOption int
+
+const (
+	PauseResume    PauseOption = iota // Carry on playing
+	PauseRestart                      // Start a fresh game with the same options
+	PauseCalibrate                    // Re-center head tracking on the current position
+	PauseSettings                     // Open the settings screen
+	PauseQuit                         // Abandon the game and return to the title screen
+	PauseOptionCount
+)
+
+// String returns the menu label of the option
+func (o PauseOption) String() string {
+	switch o {
+	case PauseResume:
+		return "RESUME"
+	case PauseRestart:
+		return "RESTART"
+	case PauseCalibrate:
+		return "CALIBRATE CAMERA"
+	case PauseSettings:
+		return "SETTINGS"
+	case PauseQuit:
+		return "QUIT TO TITLE"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// processPauseMenuCommands moves through the pause menu and acts on the
+// chosen option. Pause closes the menu and resumes play.
+func (e *Engine) processPauseMenuCommands(commands Commands) {
+	switch {
+	case commands.Pause:
+		e.state.TogglePause()
+	case commands.selected():
+		e.selectPauseOption(e.state.PauseSelection)
+	default:
+		e.state.PauseSelection = PauseOption(stepSelection(int(e.state.PauseSelection), int(PauseOptionCount), commands.Up, commands.Down))
+	}
+}
+
+// PointPauseMenu highlights the option under a head position, mapping
+// analogY (-1 top to 1 bottom) across the menu entries
+func (e *Engine) PointPauseMenu(analogY float64) {
+	if e.state.Mode != Playing || !e.state.Paused {
+		return
+	}
+
+	e.state.PauseSelection = PauseOption(pointSelection(analogY, int(PauseOptionCount)))
+}
+
+// selectPauseOption carries out a pause menu choice
+func (e *Engine) selectPauseOption(option PauseOption) {
+	switch option {
+	case PauseResume:
+		e.state.TogglePause()
+	case PauseRestart:
+		e.StartNewGame()
+	case PauseCalibrate:
+		e.calibrationRequested = true
+	case PauseSettings:
+		e.OpenSettings()
+	case PauseQuit:
+		e.setMode(AttractMode)
+	}
+}
+
+// TakeCalibrationRequest reports whether the player asked to recalibrate the
+// camera, clearing the request
+func (e *Engine) TakeCalibrationRequest() bool {
+	requested := e.calibrationRequested
+	e.calibrationRequested = false
+	return requested
+}
@@ -0,0 +1,88 @@
+package game
+
+import "math"
+
+// CoopPlayers is the number of ships in a local co-op game
+const CoopPlayers = 2
+
+// Player holds one player's ship, lives, and score
+type Player struct {
+	Index         int         // position in GameState.Players
+	Ship          *PlayerShip // current ship; replaced on respawn
+	Status        PlayerStatus
+	StatusTimer   float64 // seconds left in the current death sequence step
+	DeathPosition Vector2 // where the ship was last destroyed
+	Lives         int
+	Score         int
+	SmartBombs    int // screen-clear specials left for this life
+}
+
+// IsActive reports whether the player has a live ship in play
+func (p *Player) IsActive() bool {
+	return p.Ship != nil && p.Ship.Alive
+}
+
+// initializePlayers creates the ships for a new game
+func (gs *GameState) initializePlayers() {
+	count := 1
+	if gs.Options.Coop {
+		count = CoopPlayers
+	}
+
+	gs.Players = make([]*Player, count)
+	for i := range gs.Players {
+		gs.Players[i] = &Player{
+			Index:      i,
+			Ship:       gs.NewPlayer(i),
+			Status:     PlayerActive,
+			Lives:      gs.Options.StartingLives(),
+			SmartBombs: SmartBombsPerLife,
+		}
+	}
+}
+
+// PrimaryPlayer returns player one, who owns the keyboard specials, or nil
+// outside of a game
+func (gs *GameState) PrimaryPlayer() *Player {
+	if len(gs.Players) == 0 {
+		return nil
+	}
+	return gs.Players[0]
+}
+
+// SpawnX returns the column where the given player's ship starts
+func (gs *GameState) SpawnX(index int) float64 {
+	// Ships share the width evenly so co-op players start apart
+	return float64(gs.ScreenWidth) * float64(index+1) / float64(len(gs.Players)+1)
+}
+
+// BulletOwner returns the player who fired the given bullet
+func (gs *GameState) BulletOwner(bullet *Bullet) *Player {
+	if bullet.Owner >= 0 && bullet.Owner < len(gs.Players) {
+		return gs.Players[bullet.Owner]
+	}
+	return gs.PrimaryPlayer()
+}
+
+// NearestShip returns the live ship closest to column x, or nil if none are in play
+func (gs *GameState) NearestShip(x float64) *PlayerShip {
+	var nearest *PlayerShip
+	for _, player := range gs.Players {
+		if !player.IsActive() {
+			continue
+		}
+		if nearest == nil || math.Abs(player.Ship.Position.X-x) < math.Abs(nearest.Position.X-x) {
+			nearest = player.Ship
+		}
+	}
+	return nearest
+}
+
+// AwardTeamBonus gives a shared bonus to every player still in the game
+func (gs *GameState) AwardTeamBonus(points int) {
+	for _, player := range gs.Players {
+		if player.Lives > 0 || gs.Options.Practice {
+			gs.AddScore(player, points)
+		}
+	}
+}
@@ -0,0 +1,9 @@
+package game
+
+// The playfield is the fixed logical area the simulation runs in, measured in
+// game units. Front-ends scale it to whatever canvas they draw on, so the size
+// of the browser window never changes how the game plays.
+const (
+	PlayfieldWidth  = 800
+	PlayfieldHeight = 500
+)
@@ -0,0 +1,68 @@
+package game
+
+import "math/rand"
+
+// Random streams drawn from the run seed, one per kind of run event so that
+// drawing more of one never shifts the others
+const (
+	streamUFO       int64 = iota // UFO timings and entry side
+	streamDrops                  // pickup drops
+	streamShots                  // which invaders fire
+	streamParticles              // cosmetic particle sprays
+)
+
+// RunRNG is the random source for one stream of run events. Its whole state
+// is a single word, so saved games and snapshots can hold it directly.
+type RunRNG struct {
+	*rand.Rand
+	source *runSource
+}
+
+// State returns the stream's position, for resuming it with resumeRunRNG
+func (r *RunRNG) State() uint64 {
+	return r.source.state
+}
+
+// runSource is a splitmix64 generator
+type runSource struct {
+	state uint64
+}
+
+func (s *runSource) Uint64() uint64 {
+	s.state += 0x9e3779b97f4a7c15
+	z := s.state
+	z = (z ^ z>>30) * 0xbf58476d1ce4e5b9
+	z = (z ^ z>>27) * 0x94d049bb133111eb
+	return z ^ z>>31
+}
+
+func (s *runSource) Int63() int64 {
+	return int64(s.Uint64() >> 1)
+}
+
+func (s *runSource) Seed(seed int64) {
+	s.state = uint64(seed)
+}
+
+// NewRunRNG returns the random source for one stream of run events, such as
+// UFO timings or pickup drops. Every stream is derived from the game's seed,
+// so a run with the same seed and inputs plays out identically; daily runs
+// share their seed so these events happen at the same moments for everyone.
+func (gs *GameState) NewRunRNG(stream int64) *RunRNG {
+	source := &runSource{}
+	source.Seed(gs.Seed + stream)
+	source.state = source.Uint64() // spread nearby seeds apart
+	return resumeRunRNG(source.state)
+}
+
+// resumeRunRNG returns a random source continuing from a saved State
+func resumeRunRNG(state uint64) *RunRNG {
+	source := &runSource{state: state}
+	return &RunRNG{Rand: rand.New(source), source: source}
+}
+
+// SetSeed fixes the seed for the next games, so they can be replayed or
+// played in lockstep elsewhere; 0 picks a new seed for each game
+func (e *Engine) SetSeed(seed int64) {
+	e.state.Options.Seed = seed
+}
@@ -0,0 +1,130 @@
+package game
+
+// EntityID identifies an entity for the rest of the game. IDs count up from 1
+// and are never reused, so 0 means an entity has not been added yet.
+type EntityID uint64
+
+// Entity is anything in play that has an ID: ships, invaders, bullets, the
+// UFO and boss, pickups, beams, debris and score popups
+type Entity interface {
+	EntityID() EntityID
+	setEntityID(id EntityID)
+}
+
+// EntityManager hands out entity IDs and finds entities in play by their ID
+type EntityManager struct {
+	NextID EntityID // ID the next entity added will get
+
+	// Entities in play by ID, rebuilt on the first lookup after a change
+	index map[EntityID]Entity
+}
+
+// track gives newly added entities their IDs
+func track[T Entity](m *EntityManager, entities ...T) {
+	for _, entity := range entities {
+		if entity.EntityID() != 0 {
+			continue
+		}
+		m.NextID++
+		entity.setEntityID(m.NextID)
+	}
+	m.invalidate()
+}
+
+// invalidate drops the lookup index after entities were added or removed
+func (m *EntityManager) invalidate() {
+	m.index = nil
+}
+
+// Entity returns the entity in play with the given ID
+func (gs *GameState) Entity(id EntityID) (Entity, bool) {
+	if gs.Entities.index == nil {
+		gs.indexEntities()
+	}
+	entity, ok := gs.Entities.index[id]
+	return entity, ok
+}
+
+// indexEntities rebuilds the lookup index from the entities in play
+func (gs *GameState) indexEntities() {
+	gs.Entities.index = make(map[EntityID]Entity)
+	gs.EachEntity(func(entity Entity) {
+		gs.Entities.index[entity.EntityID()] = entity
+	})
+}
+
+// Invader returns the invader in play with the given ID
+func (gs *GameState) Invader(id EntityID) (*Invader, bool) {
+	entity, _ := gs.Entity(id)
+	invader, ok := entity.(*Invader)
+	return invader, ok
+}
+
+// Bullet returns the bullet in flight with the given ID
+func (gs *GameState) Bullet(id EntityID) (*Bullet, bool) {
+	entity, _ := gs.Entity(id)
+	bullet, ok := entity.(*Bullet)
+	return bullet, ok
+}
+
+// EachEntity calls fn for every entity in play, in a fixed order: ships,
+// invaders, the boss, the UFO, bullets, beams, pickups, debris, then popups
+func (gs *GameState) EachEntity(fn func(Entity)) {
+	for _, player := range gs.Players {
+		if player.Ship != nil {
+			fn(player.Ship)
+		}
+	}
+	for _, invader := range gs.Invaders {
+		fn(invader)
+	}
+	for _, invader := range gs.DyingInvaders {
+		fn(invader)
+	}
+	if gs.Boss != nil {
+		fn(gs.Boss)
+	}
+	if gs.UFO != nil {
+		fn(gs.UFO)
+	}
+	for _, bullet := range gs.Bullets {
+		fn(bullet)
+	}
+	for _, beam := range gs.Beams {
+		fn(beam)
+	}
+	for _, pickup := range gs.Pickups {
+		fn(pickup)
+	}
+	for _, debris := range gs.Debris {
+		fn(debris)
+	}
+	for _, popup := range gs.ScorePopups {
+		fn(popup)
+	}
+	for _, explosion := range gs.Explosions {
+		fn(explosion)
+	}
+}
+
+// EntityID returns the entity's ID, and setEntityID gives it one
+func (p *PlayerShip) EntityID() EntityID      { return p.ID }
+func (p *PlayerShip) setEntityID(id EntityID) { p.ID = id }
+func (i *Invader) EntityID() EntityID         { return i.ID }
+func (i *Invader) setEntityID(id EntityID)    { i.ID = id }
+func (b *Boss) EntityID() EntityID            { return b.ID }
+func (b *Boss) setEntityID(id EntityID)       { b.ID = id }
+func (u *UFO) EntityID() EntityID             { return u.ID }
+func (u *UFO) setEntityID(id EntityID)        { u.ID = id }
+func (b *Bullet) EntityID() EntityID          { return b.ID }
+func (b *Bullet) setEntityID(id EntityID)     { b.ID = id }
+func (b *Beam) EntityID() EntityID            { return b.ID }
+func (b *Beam) setEntityID(id EntityID)       { b.ID = id }
+func (p *Pickup) EntityID() EntityID          { return p.ID }
+func (p *Pickup) setEntityID(id EntityID)     { p.ID = id }
+func (d *Debris) EntityID() EntityID          { return d.ID }
+func (d *Debris) setEntityID(id EntityID)     { d.ID = id }
+func (s *ScorePopup) EntityID() EntityID      { return s.ID }
+func (s *ScorePopup) setEntityID(id EntityID) { s.ID = id }
+func (x *Explosion) EntityID() EntityID       { return x.ID }
+func (x *Explosion) setEntityID(id EntityID)  { x.ID = id }
@@ -0,0 +1,75 @@
+package game
+
+import (
+	"fmt"
+	"hash/fnv"
+)
+
+// Replay is a recorded run: the options it was started with and the input
+// for every tick after the start. Played back on the same build, a replay
+// with a fixed seed reproduces the run exactly.
+type Replay struct {
+	Options GameOptions `json:"options"`
+	Inputs  []InputRun  `json:"inputs"`
+}
+
+// InputRun is one input held for a number of consecutive ticks, so long
+// stretches of the same input take a single entry
+type InputRun struct {
+	Input InputFrame `json:"input"`
+	Ticks int        `json:"ticks"`
+}
+
+// Record appends one tick's input to the replay
+func (r *Replay) Record(input InputFrame) {
+	if n := len(r.Inputs); n > 0 && r.Inputs[n-1].Input == input {
+		r.Inputs[n-1].Ticks++
+		return
+	}
+	r.Inputs = append(r.Inputs, InputRun{Input: input, Ticks: 1})
+}
+
+// Ticks returns the number of ticks the replay covers
+func (r *Replay) Ticks() int {
+	ticks := 0
+	for _, run := range r.Inputs {
+		ticks += run.Ticks
+	}
+	return ticks
+}
+
+// MarshalReplay encodes a replay for storage or sharing
+func MarshalReplay(replay *Replay) ([]byte, error) {
+	return marshalVersioned(formatReplay, replay)
+}
+
+// ParseReplay parses a stored replay, migrating one recorded by an older build
+func ParseReplay(data []byte) (*Replay, error) {
+	replay := &Replay{}
+	if err := unmarshalVersioned(formatReplay, data, replay); err != nil {
+		return nil, fmt.Errorf("failed to parse replay: %w", err)
+	}
+	return replay, nil
+}
+
+// PlayReplay starts a new game with the replay's options and steps through
+// its inputs. The engine is left where the replay ends.
+func (e *Engine) PlayReplay(replay *Replay) {
+	e.applyPendingRules() // so they aren't applied over the replay's
+	e.state.Options = replay.Options
+	e.StartNewGame()
+	for _, run := range replay.Inputs {
+		for range run.Ticks {
+			e.Step(run.Input)
+		}
+	}
+}
+
+// Checksum returns a hash of the state that matters to play: the mode, wave,
+// scores, lives and barrier blocks, and every entity at the precision it is
+// streamed with. Two runs that play out the same have the same checksum.
+func (gs *GameState) Checksum() uint64 {
+	hash := fnv.New64a()
+	hash.Write(NewDeltaEncoder(0).Encode(gs))
+	return hash.Sum64()
+}
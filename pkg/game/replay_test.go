@@ -0,0 +1,102 @@
+package game
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// updateGolden rewrites the expected results of the golden replays with what
+// the engine produces now. Only use it after checking a change is meant to
+// alter gameplay.
+var updateGolden = flag.Bool("update", false, "rewrite golden replay results")
+
+// goldenReplay is a recorded input log and the result it must produce
+type goldenReplay struct {
+	Replay Replay       `json:"replay"`
+	Expect goldenResult `json:"expect"`
+}
+
+// goldenResult is the state a golden replay ends in
+type goldenResult struct {
+	Mode     string `json:"mode"`
+	Wave     int    `json:"wave"`
+	Scores   []int  `json:"scores"`
+	Lives    []int  `json:"lives"`
+	Checksum string `json:"checksum"`
+}
+
+// playGolden runs a replay on a fresh engine and describes where it ends
+func playGolden(replay *Replay) goldenResult {
+	e := NewEngine()
+	e.PlayReplay(replay)
+
+	gs := e.GetState()
+	result := goldenResult{
+		Mode:     gs.Mode.String(),
+		Wave:     gs.Wave,
+		Checksum: fmt.Sprintf("%016x", gs.Checksum()),
+	}
+	for _, player := range gs.Players {
+		result.Scores = append(result.Scores, player.Score)
+		result.Lives = append(result.Lives, player.Lives)
+	}
+	return result
+}
+
+func TestGoldenReplays(t *testing.T) {
+	if Deterministic {
+		t.Skip("golden replays are recorded with floating-point positions")
+	}
+
+	paths, err := filepath.Glob(filepath.Join("testdata", "replays", "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) == 0 {
+		t.Fatal("no golden replays found")
+	}
+
+	for _, path := range paths {
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var golden goldenReplay
+			if err := json.Unmarshal(data, &golden); err != nil {
+				t.Fatalf("failed to parse %s: %v", path, err)
+			}
+
+			got := playGolden(&golden.Replay)
+			if *updateGolden {
+				golden.Expect = got
+				data, err := json.MarshalIndent(golden, "", "  ")
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			want := golden.Expect
+			if got.Mode != want.Mode || got.Wave != want.Wave {
+				t.Errorf("after %d ticks got %s on wave %d, want %s on wave %d",
+					golden.Replay.Ticks(), got.Mode, got.Wave, want.Mode, want.Wave)
+			}
+			if !slices.Equal(got.Scores, want.Scores) || !slices.Equal(got.Lives, want.Lives) {
+				t.Errorf("got scores %v and lives %v, want scores %v and lives %v",
+					got.Scores, got.Lives, want.Scores, want.Lives)
+			}
+			if got.Checksum != want.Checksum {
+				t.Errorf("got state checksum %s, want %s", got.Checksum, want.Checksum)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package game
+
+import "fmt"
+
+// LandingOutcome is what happens when the formation reaches the ships
+type LandingOutcome int
+
+const (
+	LandingGameOver    LandingOutcome = iota // The game ends
+	LandingLoseTurn                          // The current player's game ends and the next takes over
+	LandingRestartWave                       // The wave starts again from the top
+)
+
+// Ruleset decides how a game is won, lost and scored. The engine runs the
+// simulation and consults the ruleset at each decision, so a new game mode is
+// a new ruleset rather than more branches in the engine. Rulesets embed
+// ClassicRuleset to change only the rules they need.
+type Ruleset interface {
+	// Name identifies the ruleset in GameOptions and saved games
+	Name() string
+
+	// BossWave reports whether the given wave is fought against a boss
+	BossWave(gs *GameState, wave int) bool
+
+	// WaveCleared reports whether the current wave has been beaten
+	WaveCleared(gs *GameState) bool
+
+	// NextWave returns the wave that follows a cleared one
+	NextWave(gs *GameState) int
+
+	// InvaderPoints returns what destroying an invader is worth, given the
+	// engine's scoring policy
+	InvaderPoints(gs *GameState, invader *Invader, scoring ScoringPolicy) int
+
+	// Landed decides what happens when the formation reaches the ships
+	Landed(gs *GameState) LandingOutcome
+
+	// GameOver reports whether the game is lost for a reason other than
+	// running out of lives, checked every tick
+	GameOver(gs *GameState) bool
+}
+
+// ClassicRuleset is the arcade game: endless waves with a boss every
+// BossWaveInterval, scored by the scoring policy, ending when the lives run out
+type ClassicRuleset struct{}
+
+// Name returns "classic"
+func (ClassicRuleset) Name() string {
+	return "classic"
+}
+
+// BossWave makes every BossWaveInterval-th wave a boss wave
+func (ClassicRuleset) BossWave(gs *GameState, wave int) bool {
+	return wave%BossWaveInterval == 0
+}
+
+// WaveCleared reports whether every invader and the boss are gone
+func (ClassicRuleset) WaveCleared(gs *GameState) bool {
+	return gs.IsWaveCleared()
+}
+
+// NextWave moves on to the following wave
+func (ClassicRuleset) NextWave(gs *GameState) int {
+	return gs.Wave + 1
+}
+
+// InvaderPoints scores the invader with the scoring policy
+func (ClassicRuleset) InvaderPoints(gs *GameState, invader *Invader, scoring ScoringPolicy) int {
+	return scoring.Points(invader.Points, gs.FormationDrop, gs.Wave)
+}
+
+// Landed restarts the wave in practice, ends the turn in hotseat games and
+// ends the game otherwise
+func (ClassicRuleset) Landed(gs *GameState) LandingOutcome {
+	switch {
+	case gs.Options.Practice:
+		return LandingRestartWave
+	case gs.IsMultiplayer():
+		return LandingLoseTurn
+	default:
+		return LandingGameOver
+	}
+}
+
+// GameOver never ends the game early; only running out of lives does
+func (ClassicRuleset) GameOver(gs *GameState) bool {
+	return false
+}
+
+// rulesets holds the rulesets GameOptions can name
+var rulesets = map[string]Ruleset{
+	ClassicRuleset{}.Name(): ClassicRuleset{},
+}
+
+// RegisterRuleset makes a ruleset available to SetRuleset by its name
+func RegisterRuleset(ruleset Ruleset) {
+	rulesets[ruleset.Name()] = ruleset
+}
+
+// Rules returns the ruleset the game is played under, classic by default
+func (gs *GameState) Rules() Ruleset {
+	if ruleset, ok := rulesets[gs.Options.Ruleset]; ok {
+		return ruleset
+	}
+	return ClassicRuleset{}
+}
+
+// SetRuleset picks the registered ruleset for the next games by name
+func (e *Engine) SetRuleset(name string) error {
+	if _, ok := rulesets[name]; !ok {
+		return fmt.Errorf("unknown ruleset %q", name)
+	}
+	e.state.Options.Ruleset = name
+	return nil
+}
@@ -0,0 +1,140 @@
+package game
+
+import (
+	"fmt"
+)
+
+// SavedGame is a game in progress: the full game state, with every entity,
+// bullet in flight and barrier block, plus the engine's own timers
+type SavedGame struct {
+	State  *GameState   `json:"state"`
+	Engine EngineTimers `json:"engine"`
+}
+
+// EngineTimers is the simulation state the engine keeps outside GameState
+type EngineTimers struct {
+	Time             float64 `json:"time"` // game clock reading
+	GameStartTime    float64 `json:"gameStartTime"`
+	InvaderMoveTimer float64 `json:"invaderMoveTimer"`
+	InvaderDropTimer float64 `json:"invaderDropTimer"`
+	UFOTimer         float64 `json:"ufoTimer"`
+	DiveTimer        float64 `json:"diveTimer"`
+	DiveCounter      int     `json:"diveCounter"`
+	AimedShotTimer   float64 `json:"aimedShotTimer"`
+	GhostFired       bool    `json:"ghostFired"`
+
+	// Position of each random stream
+	UFORNG      uint64 `json:"ufoRNG"`
+	DropRNG     uint64 `json:"dropRNG"`
+	ShotRNG     uint64 `json:"shotRNG"`
+	ParticleRNG uint64 `json:"particleRNG"`
+}
+
+// SaveGame encodes the game in progress so it can be resumed with LoadGame
+func (e *Engine) SaveGame() ([]byte, error) {
+	if !e.state.GameStarted || e.state.GameEnded || e.state.Demo {
+		return nil, fmt.Errorf("no game in progress to save")
+	}
+
+	saved := SavedGame{
+		State:  e.state,
+		Engine: e.timers(),
+	}
+	data, err := marshalVersioned(formatSave, saved)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save game: %w", err)
+	}
+	return data, nil
+}
+
+// ParseSavedGame parses a saved game, migrating one saved by an older build
+func ParseSavedGame(data []byte) (*SavedGame, error) {
+	saved := &SavedGame{}
+	if err := unmarshalVersioned(formatSave, data, saved); err != nil {
+		return nil, fmt.Errorf("failed to parse saved game: %w", err)
+	}
+	if saved.State == nil || !saved.State.GameStarted || saved.State.GameEnded {
+		return nil, fmt.Errorf("saved game has no game in progress")
+	}
+	return saved, nil
+}
+
+// LoadGame resumes a game saved with SaveGame. The high scores, settings,
+// achievements and best ghost stay as they are now, since those belong to
+// the player rather than the saved run.
+func (e *Engine) LoadGame(data []byte) error {
+	saved, err := ParseSavedGame(data)
+	if err != nil {
+		return err
+	}
+	state := saved.State
+	if state.ScreenWidth != e.state.ScreenWidth || state.ScreenHeight != e.state.ScreenHeight {
+		return fmt.Errorf("saved game is for a %dx%d screen", state.ScreenWidth, state.ScreenHeight)
+	}
+
+	current := e.state
+	state.Settings = current.Settings
+	state.HighScore = max(state.HighScore, current.HighScore)
+	state.HighScores = current.HighScores
+	state.LastInitials = current.LastInitials
+	state.AchievementUnlocked = current.AchievementUnlocked
+	state.AchievementProgress = current.AchievementProgress
+	state.DailyDate = current.DailyDate
+	state.DailyHighScore = current.DailyHighScore
+	state.BestGhost = current.BestGhost
+	state.InputState = &InputState{}
+	if state.WaveConfig == nil {
+		state.WaveConfig = current.WaveConfig
+	}
+	e.state = state
+	e.restoreTimers(saved.Engine)
+	return nil
+}
+
+// timers captures the engine's simulation state outside GameState
+func (e *Engine) timers() EngineTimers {
+	return EngineTimers{
+		Time:             e.clock.Now(),
+		GameStartTime:    e.gameStartTime,
+		InvaderMoveTimer: e.invaderMoveTimer,
+		InvaderDropTimer: e.invaderDropTimer,
+		UFOTimer:         e.ufoTimer,
+		DiveTimer:        e.diveTimer,
+		DiveCounter:      e.diveCounter,
+		AimedShotTimer:   e.aimedShotTimer,
+		GhostFired:       e.ghostFired,
+		UFORNG:           e.ufoRNG.State(),
+		DropRNG:          e.dropRNG.State(),
+		ShotRNG:          e.shotRNG.State(),
+		ParticleRNG:      e.particleRNG.State(),
+	}
+}
+
+// restoreTimers adopts captured engine state to go with the current GameState
+func (e *Engine) restoreTimers(timers EngineTimers) {
+	e.clock.Advance(timers.Time - e.clock.Now())
+	e.gameStartTime = timers.GameStartTime
+	e.baseInvaderSpeed = e.state.Options.Difficulty.Preset().InvaderSpeed
+	e.invaderMoveTimer = timers.InvaderMoveTimer
+	e.invaderDropTimer = timers.InvaderDropTimer
+	e.ufoTimer = timers.UFOTimer
+	e.diveTimer = timers.DiveTimer
+	e.diveCounter = timers.DiveCounter
+	e.aimedShotTimer = timers.AimedShotTimer
+	e.ghostFired = timers.GhostFired
+	e.ufoRNG = resumeRunRNG(timers.UFORNG)
+	e.dropRNG = resumeRunRNG(timers.DropRNG)
+	e.shotRNG = resumeRunRNG(timers.ShotRNG)
+	e.particleRNG = resumeRunRNG(timers.ParticleRNG)
+
+	// Invaders away from the formation are found again by their move state
+	e.detachedInvaders = nil
+	for _, invader := range e.state.Invaders {
+		if invader.Alive && invader.IsDetached() {
+			e.detachedInvaders = append(e.detachedInvaders, invader)
+		}
+	}
+
+	e.accumulator = 0
+	e.attractTimer = 0
+}
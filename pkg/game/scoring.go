@@ -0,0 +1,65 @@
+package game
+
+import (
+	"fmt"
+	"math"
+)
+
+// ScoringPolicy decides what destroying an invader is worth. The base value
+// comes from the invader type; the optional bonuses make kills worth more the
+// further the formation has dropped and the later the wave.
+type ScoringPolicy struct {
+	DropBonus float64 // extra share of base points per 100 pixels the formation has dropped this wave
+	WaveBonus float64 // extra share of base points per wave after the first
+}
+
+// DefaultScoringPolicy returns the classic policy, where every invader is
+// always worth its base points
+func DefaultScoringPolicy() ScoringPolicy {
+	return ScoringPolicy{}
+}
+
+// ProgressiveScoringPolicy returns a policy that rewards late kills, both
+// within a wave and over the course of a run
+func ProgressiveScoringPolicy() ScoringPolicy {
+	return ScoringPolicy{DropBonus: 0.25, WaveBonus: 0.1}
+}
+
+// validate checks the policy for unusable values
+func (p ScoringPolicy) validate() error {
+	if p.DropBonus < 0 || p.WaveBonus < 0 {
+		return fmt.Errorf("scoring bonuses must not be negative")
+	}
+	return nil
+}
+
+// Points returns what an invader worth base points is worth once the
+// formation has dropped the given distance on the given wave
+func (p ScoringPolicy) Points(base int, drop float64, wave int) int {
+	scale := 1 + p.DropBonus*drop/100 + p.WaveBonus*float64(wave-1)
+	return int(math.Round(float64(base) * scale))
+}
+
+// SetScoringPolicy changes how invader kills are scored in games that don't
+// use the progressive option
+func (e *Engine) SetScoringPolicy(policy ScoringPolicy) error {
+	if err := policy.validate(); err != nil {
+		return err
+	}
+	e.scoring = policy
+	return nil
+}
+
+// scoringPolicy returns the policy kills are scored by: the progressive one
+// when the game's options ask for it, otherwise the engine's own
+func (e *Engine) scoringPolicy() ScoringPolicy {
+	if e.state.Options.Progressive {
+		return ProgressiveScoringPolicy()
+	}
+	return e.scoring
+}
+
+// invaderPoints returns what destroying the invader is worth right now
+func (e *Engine) invaderPoints(invader *Invader) int {
+	return e.state.Rules().InvaderPoints(e.state, invader, e.scoringPolicy())
+}
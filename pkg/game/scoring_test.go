@@ -0,0 +1,140 @@
+package game
+
+import "testing"
+
+func TestProgressiveScoringSetting(t *testing.T) {
+	settings := DefaultSettings()
+	settings.Progressive = true
+	data, err := MarshalSettings(settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored, err := ParseSettings(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !stored.Progressive {
+		t.Fatal("progressive scoring was lost in storage")
+	}
+
+	e := NewEngine()
+	e.ApplySettings(stored)
+	e.GetState().Options.Seed = 1
+	e.StartNewGame()
+	gs := e.GetState()
+	gs.Wave = 3
+	invader := gs.Invaders[0]
+	if got, base := e.invaderPoints(invader), invader.Points; got <= base {
+		t.Errorf("wave 3 kill worth %d with progressive scoring, want more than %d", got, base)
+	}
+
+	e.StartNewGame()
+	gs.Wave = 3
+	invader = gs.Invaders[0]
+	e.ApplySettings(DefaultSettings())
+	if got, base := e.invaderPoints(invader), invader.Points; got <= base {
+		t.Errorf("scoring changed to classic in the middle of a game: wave 3 kill worth %d", got)
+	}
+
+	e.StartNewGame()
+	gs.Wave = 3
+	invader = gs.Invaders[0]
+	if got, base := e.invaderPoints(invader), invader.Points; got != base {
+		t.Errorf("wave 3 kill worth %d with classic scoring, want %d", got, base)
+	}
+}
+
+func TestScoringSettingWaitsForNextGame(t *testing.T) {
+	e := NewEngine()
+	e.GetState().Options = GameOptions{Seed: 1, Practice: true}
+	e.StartNewGame()
+	gs := e.GetState()
+	gs.Wave = 3
+
+	// Switch to progressive scoring from the pause menu's settings screen
+	e.Step(InputFrame{PausePressed: true})
+	gs.PauseSelection = PauseSettings
+	e.Step(InputFrame{Confirm: true})
+	gs.SettingsSelection = SettingScoring
+	e.Step(InputFrame{Right: true})
+	if !gs.Settings.Progressive {
+		t.Fatal("scoring setting didn't change")
+	}
+	e.Step(InputFrame{PausePressed: true})
+	e.Step(InputFrame{PausePressed: true})
+	if gs.Mode != Playing || gs.Paused {
+		t.Fatalf("mode %v paused %v after leaving the settings, want playing", gs.Mode, gs.Paused)
+	}
+
+	player := gs.PrimaryPlayer()
+	before := player.Score
+	target := gs.Invaders[0]
+	bullet := NewBullet(target.Position.X, target.Position.Y+15, 0, -400, true)
+	gs.Bullets = append(gs.Bullets, bullet)
+	track(&gs.Entities, bullet)
+	e.Step(InputFrame{})
+	if target.Alive {
+		t.Fatal("shot missed the invader")
+	}
+	if got := player.Score - before; got != target.Points {
+		t.Errorf("kill scored %d after changing the setting mid-game, want the classic %d", got, target.Points)
+	}
+
+	e.StartNewGame()
+	if !gs.Options.Progressive {
+		t.Error("progressive scoring not in effect for the next game")
+	}
+}
+
+func TestUFOMysteryScoreShots(t *testing.T) {
+	for _, tc := range []struct {
+		shot, want int
+	}{
+		{0, 100},
+		{8, 100},
+		{22, 50},
+		{23, 300},
+		{24, 100},
+		{38, 300},
+		{53, 300},
+	} {
+		if got := UFOMysteryScore(tc.shot); got != tc.want {
+			t.Errorf("UFO hit on shot %d worth %d, want %d", tc.shot, got, tc.want)
+		}
+	}
+}
+
+func TestSmartBombKillsCountTowardCombo(t *testing.T) {
+	e := NewEngine()
+	e.GetState().Options = GameOptions{Seed: 1, Practice: true}
+	e.StartNewGame()
+	gs := e.GetState()
+	player := gs.PrimaryPlayer()
+	gs.Combo = 3 * comboKillsPerStep // multiplier already at its top
+
+	before, base := player.Score, 0
+	var alive []*Invader
+	for _, invader := range gs.Invaders {
+		if invader.Alive {
+			alive = append(alive, invader)
+		}
+	}
+	e.Step(InputFrame{SmartBomb: true})
+
+	killed := 0
+	for _, invader := range alive {
+		if !invader.Alive {
+			killed++
+			base += e.invaderPoints(invader)
+		}
+	}
+	if killed == 0 {
+		t.Fatal("smart bomb destroyed no invaders")
+	}
+	if gs.Combo != 3*comboKillsPerStep+killed {
+		t.Errorf("combo %d after %d smart bomb kills, want %d", gs.Combo, killed, 3*comboKillsPerStep+killed)
+	}
+	if got, want := player.Score-before, base*maxComboMultiplier; got != want {
+		t.Errorf("smart bomb scored %d, want %d at the top multiplier", got, want)
+	}
+}
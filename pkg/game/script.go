@@ -0,0 +1,64 @@
+package game
+
+// Script lets wave and boss behavior be driven from outside the engine, so
+// special waves and new enemy movement can be tried without rebuilding the
+// game. The engine calls each hook at a fixed point in the tick; scripts
+// embed NoScript to implement only the hooks they need.
+//
+// Scripts run inside the update, so a script that reads the wall clock or its
+// own random numbers makes the game play out differently from run to run.
+type Script interface {
+	// OnWaveStart runs once the formation or boss for a new wave is in
+	// place, and may rearrange or retune it
+	OnWaveStart(gs *GameState)
+
+	// OnInvaderKilled runs after a player destroys an invader
+	OnInvaderKilled(gs *GameState, event Event)
+
+	// MoveInvader moves an invader that broke formation. It reports whether
+	// it moved the invader; if not, the invader flies its usual dive.
+	MoveInvader(gs *GameState, invader *Invader, deltaTime float64) bool
+
+	// SteerBoss runs before the boss moves each tick and may change its
+	// velocity or attack pattern
+	SteerBoss(gs *GameState, boss *Boss, deltaTime float64)
+}
+
+// NoScript is the built-in behavior, with every hook left empty
+type NoScript struct{}
+
+// OnWaveStart leaves the wave as generated
+func (NoScript) OnWaveStart(gs *GameState) {}
+
+// OnInvaderKilled does nothing
+func (NoScript) OnInvaderKilled(gs *GameState, event Event) {}
+
+// MoveInvader leaves the invader to its usual dive
+func (NoScript) MoveInvader(gs *GameState, invader *Invader, deltaTime float64) bool {
+	return false
+}
+
+// SteerBoss leaves the boss sweeping across the screen
+func (NoScript) SteerBoss(gs *GameState, boss *Boss, deltaTime float64) {}
+
+// SetScript installs the script driving wave and boss behavior; nil restores
+// the built-in behavior
+func (e *Engine) SetScript(script Script) {
+	if script == nil {
+		script = NoScript{}
+	}
+	e.script = script
+}
+
+// startWave lets the script set up a wave that has just been laid out
+func (e *Engine) startWave() {
+	e.resetInvaderMovement()
+	e.state.WaveIntro = WaveIntroDuration
+	e.script.OnWaveStart(e.state)
+
+	// Invaders the script added need IDs of their own
+	track(&e.state.Entities, e.state.Invaders...)
+	if e.state.Boss != nil {
+		track(&e.state.Entities, e.state.Boss)
+	}
+}
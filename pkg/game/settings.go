@@ -0,0 +1,408 @@
+package game
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// ControlScheme selects how player one steers the ship
+type ControlScheme int
+
+const (
+	ControlCamera   ControlScheme = iota // Head tracking when a camera is available
+	ControlKeyboard                      // Arrow keys only, even with a camera
+	controlSchemeCount
+)
+
+// String returns the menu name of the control scheme
+func (c ControlScheme) String() string {
+	switch c {
+	case ControlCamera:
+		return "CAMERA"
+	case ControlKeyboard:
+		return "KEYBOARD"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// Themes lists the selectable color themes
+var Themes = []string{"classic", "amber", "ice", "neon", "colorblind", "deuteranopia", "protanopia"}
+
+// Settings are the player's preferences, persisted between sessions
+type Settings struct {
+	Difficulty  DifficultyLevel `json:"difficulty"`
+	Controls    ControlScheme   `json:"controls"`
+	Volume      float64         `json:"volume"`      // 0 (muted) to 1
+	Theme       string          `json:"theme"`       // one of Themes
+	Sensitivity float64         `json:"sensitivity"` // head tracking gain
+	Lives       int             `json:"lives"`       // starting lives; 0 uses the difficulty preset
+	ShipSpeed   float64         `json:"shipSpeed"`   // ship speed multiplier
+	FireRate    float64         `json:"fireRate"`    // player fire rate multiplier
+	ScreenShake bool            `json:"screenShake"` // jolt the screen on big hits
+	CRT         bool            `json:"crt"`         // scanlines and glow like an old monitor
+	Glow        float64         `json:"glow"`        // 0 (off) to 1, glow around player shots
+	ShotShapes  bool            `json:"shotShapes"`  // mark whose shot is whose by shape as well as color
+	WebGL       bool            `json:"webgl"`       // draw sprites with WebGL2 where the browser has it; off keeps them on the 2D canvas
+	Progressive bool            `json:"progressive"` // late kills are worth more, see ProgressiveScoringPolicy
+}
+
+// Setting ranges and steps for the left/right adjustments
+const (
+	volumeStep         = 0.1
+	sensitivityMin     = 1.0
+	sensitivityMax     = 10.0
+	sensitivityStep    = 0.5
+	defaultSensitivity = 4.0
+	defaultVolume      = 0.8
+	maxLives           = 9
+	shipSpeedMin       = 0.5
+	shipSpeedMax       = 1.5
+	fireRateMin        = 0.5
+	fireRateMax        = 2.0
+	scaleStep          = 0.25
+	glowStep           = 0.25
+	defaultGlow        = 0.75
+)
+
+// DefaultSettings returns the settings used before the player changes any
+func DefaultSettings() Settings {
+	return Settings{
+		Difficulty:  DifficultyNormal,
+		Controls:    ControlCamera,
+		Volume:      defaultVolume,
+		Theme:       Themes[0],
+		Sensitivity: defaultSensitivity,
+		ShipSpeed:   1,
+		FireRate:    1,
+		ScreenShake: true,
+		Glow:        defaultGlow,
+		WebGL:       true,
+	}
+}
+
+// ParseSettings parses stored settings, falling back to defaults for
+// anything missing or out of range
+func ParseSettings(data []byte) (Settings, error) {
+	settings := DefaultSettings()
+	if err := unmarshalVersioned(formatSettings, data, &settings); err != nil {
+		return DefaultSettings(), fmt.Errorf("failed to parse settings: %w", err)
+	}
+	return settings.sanitized(), nil
+}
+
+// MarshalSettings encodes settings for storage
+func MarshalSettings(settings Settings) ([]byte, error) {
+	return marshalVersioned(formatSettings, settings)
+}
+
+// sanitized clamps every setting into its valid range
+func (s Settings) sanitized() Settings {
+	defaults := DefaultSettings()
+	if s.Difficulty < 0 || s.Difficulty >= difficultyLevelCount {
+		s.Difficulty = defaults.Difficulty
+	}
+	if s.Controls < 0 || s.Controls >= controlSchemeCount {
+		s.Controls = defaults.Controls
+	}
+	s.Volume = math.Max(0, math.Min(s.Volume, 1))
+	if themeIndex(s.Theme) < 0 {
+		s.Theme = defaults.Theme
+	}
+	s.Sensitivity = math.Max(sensitivityMin, math.Min(s.Sensitivity, sensitivityMax))
+	if s.Lives < 0 || s.Lives > maxLives {
+		s.Lives = defaults.Lives
+	}
+	s.ShipSpeed = math.Max(shipSpeedMin, math.Min(s.ShipSpeed, shipSpeedMax))
+	s.FireRate = math.Max(fireRateMin, math.Min(s.FireRate, fireRateMax))
+	s.Glow = math.Max(0, math.Min(s.Glow, 1))
+	return s
+}
+
+// themeIndex returns the position of the theme in Themes, or -1
+func themeIndex(theme string) int {
+	for i, name := range Themes {
+		if name == theme {
+			return i
+		}
+	}
+	return -1
+}
+
+// SettingsOption is an entry in the settings screen
+type SettingsOption int
+
+const (
+	SettingDifficulty SettingsOption = iota
+	SettingControls
+	SettingVolume
+	SettingTheme
+	SettingSensitivity
+	SettingLives
+	SettingShipSpeed
+	SettingFireRate
+	SettingScoring
+	SettingScreenShake
+	SettingCRT
+	SettingGlow
+	SettingShotShapes
+	SettingWebGL
+	SettingBack
+	SettingsOptionCount
+)
+
+// String returns the menu label of the option
+func (o SettingsOption) String() string {
+	switch o {
+	case SettingDifficulty:
+		return "DIFFICULTY"
+	case SettingControls:
+		return "CONTROLS"
+	case SettingVolume:
+		return "VOLUME"
+	case SettingTheme:
+		return "THEME"
+	case SettingSensitivity:
+		return "SENSITIVITY"
+	case SettingLives:
+		return "LIVES"
+	case SettingShipSpeed:
+		return "SHIP SPEED"
+	case SettingFireRate:
+		return "FIRE RATE"
+	case SettingScoring:
+		return "SCORING"
+	case SettingScreenShake:
+		return "SCREEN SHAKE"
+	case SettingCRT:
+		return "CRT EFFECT"
+	case SettingGlow:
+		return "GLOW"
+	case SettingShotShapes:
+		return "SHOT SHAPES"
+	case SettingWebGL:
+		return "WEBGL"
+	case SettingBack:
+		return "BACK"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// Value returns the display value of the option under the given settings
+func (o SettingsOption) Value(s Settings) string {
+	switch o {
+	case SettingDifficulty:
+		return s.Difficulty.String()
+	case SettingControls:
+		return s.Controls.String()
+	case SettingVolume:
+		return fmt.Sprintf("%d%%", int(math.Round(s.Volume*100)))
+	case SettingTheme:
+		return strings.ToUpper(s.Theme)
+	case SettingSensitivity:
+		return fmt.Sprintf("%.1fx", s.Sensitivity)
+	case SettingLives:
+		if s.Lives == 0 {
+			return "AUTO"
+		}
+		return fmt.Sprintf("%d", s.Lives)
+	case SettingShipSpeed:
+		return fmt.Sprintf("%d%%", int(math.Round(s.ShipSpeed*100)))
+	case SettingFireRate:
+		return fmt.Sprintf("%d%%", int(math.Round(s.FireRate*100)))
+	case SettingScoring:
+		if s.Progressive {
+			return "PROGRESSIVE"
+		}
+		return "CLASSIC"
+	case SettingScreenShake:
+		return onOff(s.ScreenShake)
+	case SettingCRT:
+		return onOff(s.CRT)
+	case SettingGlow:
+		if s.Glow == 0 {
+			return "OFF"
+		}
+		return fmt.Sprintf("%d%%", int(math.Round(s.Glow*100)))
+	case SettingShotShapes:
+		return onOff(s.ShotShapes)
+	case SettingWebGL:
+		return onOff(s.WebGL)
+	default:
+		return ""
+	}
+}
+
+// Level returns where a ranged option's value sits in its range, from 0 to
+// 1, for drawing it as a slider. It reports false for options that step
+// through a list of choices instead.
+func (o SettingsOption) Level(s Settings) (float64, bool) {
+	switch o {
+	case SettingVolume:
+		return s.Volume, true
+	case SettingSensitivity:
+		return (s.Sensitivity - sensitivityMin) / (sensitivityMax - sensitivityMin), true
+	case SettingShipSpeed:
+		return (s.ShipSpeed - shipSpeedMin) / (shipSpeedMax - shipSpeedMin), true
+	case SettingFireRate:
+		return (s.FireRate - fireRateMin) / (fireRateMax - fireRateMin), true
+	case SettingGlow:
+		return s.Glow, true
+	default:
+		return 0, false
+	}
+}
+
+// Switch reports whether an on/off option is on, for drawing it as a
+// toggle. ok is false for options that aren't switches.
+func (o SettingsOption) Switch(s Settings) (on, ok bool) {
+	switch o {
+	case SettingScreenShake:
+		return s.ScreenShake, true
+	case SettingCRT:
+		return s.CRT, true
+	case SettingShotShapes:
+		return s.ShotShapes, true
+	case SettingWebGL:
+		return s.WebGL, true
+	default:
+		return false, false
+	}
+}
+
+// onOff returns the display value of a switch
+func onOff(on bool) string {
+	if on {
+		return "ON"
+	}
+	return "OFF"
+}
+
+// adjust steps the option's value in the given direction (-1 or 1)
+func (o SettingsOption) adjust(s Settings, step int) Settings {
+	switch o {
+	case SettingDifficulty:
+		if step > 0 {
+			s.Difficulty = s.Difficulty.Next()
+		} else {
+			s.Difficulty = s.Difficulty.Previous()
+		}
+	case SettingControls:
+		s.Controls = (s.Controls + ControlScheme(step) + controlSchemeCount) % controlSchemeCount
+	case SettingVolume:
+		s.Volume = math.Round((s.Volume+float64(step)*volumeStep)*10) / 10
+	case SettingTheme:
+		s.Theme = Themes[(themeIndex(s.Theme)+step+len(Themes))%len(Themes)]
+	case SettingSensitivity:
+		s.Sensitivity += float64(step) * sensitivityStep
+	case SettingLives:
+		s.Lives = (s.Lives + step + maxLives + 1) % (maxLives + 1)
+	case SettingShipSpeed:
+		s.ShipSpeed += float64(step) * scaleStep
+	case SettingFireRate:
+		s.FireRate += float64(step) * scaleStep
+	case SettingScoring:
+		s.Progressive = !s.Progressive
+	case SettingScreenShake:
+		s.ScreenShake = !s.ScreenShake
+	case SettingCRT:
+		s.CRT = !s.CRT
+	case SettingGlow:
+		s.Glow += float64(step) * glowStep
+	case SettingShotShapes:
+		s.ShotShapes = !s.ShotShapes
+	case SettingWebGL:
+		s.WebGL = !s.WebGL
+	}
+	return s.sanitized()
+}
+
+// ApplySettings adopts the given settings, for example those loaded from
+// storage. Settings that change the rules, such as difficulty and scoring,
+// wait for the next game while one is in progress, so every game is played
+// to the rules it started with.
+func (e *Engine) ApplySettings(settings Settings) {
+	e.state.Settings = settings.sanitized()
+	if e.state.GameStarted && !e.state.GameEnded {
+		e.rulesPending = true
+		return
+	}
+	e.applyRuleSettings()
+}
+
+// applyRuleSettings copies the settings that change the rules into the
+// options the next game starts with
+func (e *Engine) applyRuleSettings() {
+	e.state.Options.Difficulty = e.state.Settings.Difficulty
+	e.state.Options.Lives = e.state.Settings.Lives
+	e.state.Options.ShipSpeed = e.state.Settings.ShipSpeed
+	e.state.Options.FireRate = e.state.Settings.FireRate
+	e.state.Options.Progressive = e.state.Settings.Progressive
+	e.rulesPending = false
+}
+
+// applyPendingRules adopts rule settings changed during the last game
+func (e *Engine) applyPendingRules() {
+	if e.rulesPending {
+		e.applyRuleSettings()
+	}
+}
+
+// TakeSettingsChange returns the settings if the player changed them since
+// the last call, so the front-end can persist and apply them
+func (e *Engine) TakeSettingsChange() (Settings, bool) {
+	changed := e.settingsChanged
+	e.settingsChanged = false
+	return e.state.Settings, changed
+}
+
+// OpenSettings shows the settings screen from the title screen or pause menu
+func (e *Engine) OpenSettings() {
+	paused := e.state.Mode == Playing && e.state.Paused
+	if e.state.Mode != AttractMode && !paused {
+		return
+	}
+	e.setMode(SettingsMenu)
+}
+
+// processSettingsCommands moves through the settings screen, adjusting the
+// highlighted setting with fresh left and right presses. Pause returns to
+// where the screen was opened from.
+func (e *Engine) processSettingsCommands(commands Commands, leftJustPressed, rightJustPressed bool) {
+	option := e.state.SettingsSelection
+	switch {
+	case commands.Pause, commands.selected() && option == SettingBack:
+		e.closeSettings()
+	case commands.Up, commands.Down:
+		e.state.SettingsSelection = SettingsOption(stepSelection(int(option), int(SettingsOptionCount), commands.Up, commands.Down))
+	case leftJustPressed:
+		e.updateSettings(option.adjust(e.state.Settings, -1))
+	case rightJustPressed, commands.selected():
+		e.updateSettings(option.adjust(e.state.Settings, 1))
+	}
+}
+
+// PointSettingsMenu highlights the setting under a head position, mapping
+// analogY (-1 top to 1 bottom) across the screen's entries
+func (e *Engine) PointSettingsMenu(analogY float64) {
+	if e.state.Mode != SettingsMenu {
+		return
+	}
+	e.state.SettingsSelection = SettingsOption(pointSelection(analogY, int(SettingsOptionCount)))
+}
+
+// updateSettings adopts changed settings and flags them for the front-end
+func (e *Engine) updateSettings(settings Settings) {
+	if settings == e.state.Settings {
+		return
+	}
+	e.ApplySettings(settings)
+	e.settingsChanged = true
+}
+
+// closeSettings leaves the settings screen
+func (e *Engine) closeSettings() {
+	e.setMode(e.state.SettingsReturn)
+}
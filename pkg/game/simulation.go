@@ -0,0 +1,34 @@
+package game
+
+// Simulation is the surface a front-end uses to embed the game: a browser
+// canvas, a terminal, another game library or a headless server
+type Simulation interface {
+	// StartNewGame begins a game with the current options
+	StartNewGame()
+
+	// Update advances the game by the given seconds of elapsed time, running
+	// as many fixed timesteps as fit
+	Update(deltaTime float64)
+
+	// Step applies one tick's input and advances exactly one fixed tick,
+	// for callers that keep their own time or none at all
+	Step(input InputFrame)
+
+	// ProcessCommands applies a player's commands for the next update
+	ProcessCommands(index int, commands Commands)
+
+	// Snapshot and Restore copy the whole simulation and put it back, for
+	// rollback and rewind
+	Snapshot() *Snapshot
+	Restore(snapshot *Snapshot)
+
+	// Subscribe registers a handler for gameplay events
+	Subscribe(handler EventHandler, types ...EventType)
+
+	// GetState returns the live state for the goroutine driving the engine,
+	// and LatestState a read-only copy for any goroutine
+	GetState() *GameState
+	LatestState() *GameState
+}
+
+var _ Simulation = (*Engine)(nil)
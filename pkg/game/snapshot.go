@@ -0,0 +1,157 @@
+package game
+
+import (
+	"maps"
+	"slices"
+)
+
+// Snapshot is an in-memory copy of the whole simulation, cheap enough to take
+// every frame so predicted frames can be rolled back or the game rewound
+type Snapshot struct {
+	state  *GameState
+	timers EngineTimers
+}
+
+// Snapshot copies the simulation as it stands. The copy is independent of the
+// running game, which can carry on and later be restored to it.
+func (e *Engine) Snapshot() *Snapshot {
+	return &Snapshot{
+		state:  e.state.clone(),
+		timers: e.timers(),
+	}
+}
+
+// Restore puts the simulation back to a snapshot. A snapshot can be restored
+// any number of times.
+func (e *Engine) Restore(snapshot *Snapshot) {
+	e.state = snapshot.state.clone()
+	e.restoreTimers(snapshot.timers)
+}
+
+// PublishStates makes the engine publish a read-only copy of the game state
+// at the end of every Update, for LatestState. Call it before updates start,
+// from the goroutine that drives the engine.
+func (e *Engine) PublishStates() {
+	e.publishing = true
+	e.publishState()
+}
+
+// LatestState returns the game state as of the end of the last Update. It is
+// safe to call from any goroutine, and the state it returns never changes, so
+// readers must not modify it either. It returns nil unless PublishStates was
+// called.
+func (e *Engine) LatestState() *GameState {
+	return e.published.Load()
+}
+
+// publishState publishes a copy of the state if publishing is enabled
+func (e *Engine) publishState() {
+	if !e.publishing {
+		return
+	}
+	state := e.state.clone()
+	state.indexEntities() // lookups on the copy must not write to it
+	e.published.Store(state)
+}
+
+// clone copies the game state deeply enough that updating either copy never
+// touches the other. Data that is only ever replaced, never changed in place,
+// such as the wave config and recorded ghosts, is shared.
+func (gs *GameState) clone() *GameState {
+	c := *gs
+	c.Players = cloneAll(gs.Players, (*Player).clone)
+	c.Slots = make([]PlayerSlot, len(gs.Slots))
+	for i, slot := range gs.Slots {
+		c.Slots[i] = slot.clone()
+	}
+	c.Ghost = gs.Ghost.clone()
+	c.GhostRecording = gs.GhostRecording[:len(gs.GhostRecording):len(gs.GhostRecording)]
+	c.AchievementUnlocked = maps.Clone(gs.AchievementUnlocked)
+	c.AchievementProgress = maps.Clone(gs.AchievementProgress)
+	c.AchievementToasts = cloneAll(gs.AchievementToasts, cloneEntity)
+
+	c.Invaders = cloneAll(gs.Invaders, cloneEntity)
+	c.DyingInvaders = cloneAll(gs.DyingInvaders, cloneEntity)
+	c.Bullets = cloneAll(gs.Bullets, cloneEntity)
+	c.UFO = cloneEntity(gs.UFO)
+	c.Boss = gs.Boss.clone()
+	c.ScorePopups = cloneAll(gs.ScorePopups, cloneEntity)
+	c.Pickups = cloneAll(gs.Pickups, cloneEntity)
+	c.Beams = cloneAll(gs.Beams, cloneEntity)
+	c.Debris = cloneAll(gs.Debris, cloneEntity)
+	c.Explosions = cloneAll(gs.Explosions, cloneEntity)
+	c.Particles = slices.Clone(gs.Particles)
+	c.Barriers = cloneBarriers(gs.Barriers)
+	c.InputState = cloneEntity(gs.InputState)
+	c.Entities.invalidate()
+	return &c
+}
+
+// clone copies the player along with their ship
+func (p *Player) clone() *Player {
+	c := *p
+	c.Ship = cloneEntity(p.Ship)
+	return &c
+}
+
+// clone copies the slot's formation
+func (s PlayerSlot) clone() PlayerSlot {
+	s.Invaders = cloneAll(s.Invaders, cloneEntity)
+	s.Boss = s.Boss.clone()
+	s.Barriers = cloneBarriers(s.Barriers)
+	s.Debris = cloneAll(s.Debris, cloneEntity)
+	return s
+}
+
+// clone copies the boss and its weak points
+func (b *Boss) clone() *Boss {
+	if b == nil {
+		return nil
+	}
+	c := *b
+	c.WeakPoints = cloneAll(b.WeakPoints, cloneEntity)
+	return &c
+}
+
+// clone copies the replay position and its shots; the frames are shared
+func (g *Ghost) clone() *Ghost {
+	if g == nil {
+		return nil
+	}
+	c := *g
+	c.Shots = append([]Vector2(nil), g.Shots...)
+	return &c
+}
+
+// cloneEntity copies an entity with no pointers of its own
+func cloneEntity[T any](entity *T) *T {
+	if entity == nil {
+		return nil
+	}
+	c := *entity
+	return &c
+}
+
+// cloneAll copies every entity in a slice, keeping a nil slice nil
+func cloneAll[T any](entities []*T, clone func(*T) *T) []*T {
+	if entities == nil {
+		return nil
+	}
+	c := make([]*T, len(entities))
+	for i, entity := range entities {
+		c[i] = clone(entity)
+	}
+	return c
+}
+
+// cloneBarriers copies the barrier blocks
+func cloneBarriers(barriers [][]bool) [][]bool {
+	if barriers == nil {
+		return nil
+	}
+	c := make([][]bool, len(barriers))
+	for i, row := range barriers {
+		c[i] = append([]bool(nil), row...)
+	}
+	return c
+}
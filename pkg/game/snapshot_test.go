@@ -0,0 +1,27 @@
+package game
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestRestoreReplaysParticles(t *testing.T) {
+	e := steadyStateEngine()
+	snapshot := e.Snapshot()
+
+	run := func() []Particle {
+		for tick := range 40 {
+			e.Step(InputFrame{Left: tick < 20, Right: tick >= 20, FirePressed: tick%5 == 0})
+		}
+		return slices.Clone(e.GetState().Particles)
+	}
+	want := run()
+	if len(want) == 0 {
+		t.Fatal("no particles to compare")
+	}
+
+	e.Restore(snapshot)
+	if got := run(); !slices.Equal(got, want) {
+		t.Error("particles sprayed differently after restoring a snapshot")
+	}
+}
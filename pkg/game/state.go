@@ -0,0 +1,664 @@
+package game
+
+import (
+	"slices"
+	"time"
+)
+
+// GameMode represents the current mode of the game
+type GameMode int
+
+const (
+	AttractMode GameMode = iota
+	Playing
+	GameOver
+	HighScore
+	Summary
+	SettingsMenu
+	Continue
+)
+
+// String returns the string representation of the game mode
+func (gm GameMode) String() string {
+	switch gm {
+	case AttractMode:
+		return "AttractMode"
+	case Playing:
+		return "Playing"
+	case GameOver:
+		return "GameOver"
+	case HighScore:
+		return "HighScore"
+	case Summary:
+		return "Summary"
+	case SettingsMenu:
+		return "Settings"
+	case Continue:
+		return "Continue"
+	default:
+		return "Unknown"
+	}
+}
+
+// PlayerStatus represents the player's life-cycle sub-state while playing
+type PlayerStatus int
+
+const (
+	PlayerActive     PlayerStatus = iota // Ship is in play
+	PlayerDying                          // Explosion is playing at the death position
+	PlayerRespawning                     // Counting down before the ship reappears
+	PlayerOut                            // Out of lives while a co-op partner plays on
+)
+
+// String returns the string representation of the player status
+func (ps PlayerStatus) String() string {
+	switch ps {
+	case PlayerActive:
+		return "Active"
+	case PlayerDying:
+		return "Dying"
+	case PlayerRespawning:
+		return "Respawning"
+	case PlayerOut:
+		return "Out"
+	default:
+		return "Unknown"
+	}
+}
+
+// Death sequence timing (in seconds)
+const (
+	playerDeathDuration    = 1.0
+	playerRespawnCountdown = 2.0
+)
+
+// SmartBombsPerLife is the number of smart bombs granted with each life
+const SmartBombsPerLife = 1
+
+// BossWaveInterval is the number of waves between boss waves
+const BossWaveInterval = 5
+
+// waveClearDuration is how long the wave-clear interstitial is shown, in seconds
+const waveClearDuration = 3.0
+
+// invaderSplatDuration is how long a destroyed invader shows its splat, in seconds
+const invaderSplatDuration = 0.15
+
+// WaveIntroDuration is how long a new wave assembles behind its get-ready
+// banner before it attacks, in seconds
+const WaveIntroDuration = 2.0
+
+// GameState represents the complete state of the game
+type GameState struct {
+	// Game mode and flow
+	Mode           GameMode
+	Paused         bool
+	PauseSelection PauseOption // highlighted pause menu entry
+
+	// Attract screen showing while nobody plays
+	AttractScreen AttractScreen
+
+	// Player preferences and the settings screen
+	Settings          Settings
+	SettingsSelection SettingsOption // highlighted settings entry
+	SettingsReturn    GameMode       // mode to go back to when the screen closes
+	GameStarted    bool
+	GameEnded      bool
+	Demo           bool // the attract-mode bot is playing
+
+	// Player state
+	Players   []*Player // one per ship in play
+	HighScore int
+	BombFlash float64 // seconds left on the smart bomb flash
+
+	// High score entry: whether this run set the high score, the initials
+	// being entered on the high score screen, and the initials last signed,
+	// offered again the next time
+	NewHighScore   bool
+	Initials       [InitialsLength]byte
+	InitialsCursor int
+	LastInitials   string
+
+	// The best scores, and where this run's entry landed in them, or -1
+	HighScores    HighScoreTable
+	HighScoreRank int
+
+	// Hotseat players; empty for single-player games
+	Slots       []PlayerSlot
+	CurrentSlot int // index of the player whose turn it is
+
+	// Ghost runs: this run's recording, the best run kept, and its replay
+	GhostRecording []GhostFrame
+	BestGhost      *Ghost
+	Ghost          *Ghost
+
+	// Achievements persist across games; toasts queue unlock notifications
+	AchievementUnlocked map[AchievementID]bool
+	AchievementProgress map[AchievementID]int
+	AchievementToasts   []*AchievementToast
+
+	// Daily challenge scores, tracked apart from the regular high score
+	DailyDate      string // day the daily high score belongs to
+	DailyHighScore int
+
+	// Combo state
+	Combo      int     // consecutive kills without a miss
+	ComboTimer float64 // seconds left before the combo decays
+
+	// Game entities, with the IDs that identify them
+	Entities         EntityManager
+	Invaders         []*Invader
+	DyingInvaders    []*Invader // destroyed invaders showing their splat
+	Bullets          []*Bullet
+	UFO              *UFO
+	Boss             *Boss
+	ScorePopups      []*ScorePopup
+	Pickups          []*Pickup
+	Beams            []*Beam
+	Debris           []*Debris
+	Explosions       []*Explosion
+	Particles        []Particle // cosmetic sparks, rubble and exhaust
+	BarrierConfig    BarrierConfig
+	Barriers         [][]bool // 2D array representing barrier blocks
+	BarrierOrigin    Vector2  // World position of barrier block [0][0]
+	BarrierBlockSize float64  // Size of a single barrier block in pixels
+
+	// Wave generation
+	WaveConfig *WaveConfig // data-driven wave definitions
+	Seed       int64       // seed for procedural waves, so runs can be reproduced
+	WaveSpec   WaveSpec    // layout and tuning of the current wave
+
+	// Pixels the formation has dropped toward the player this wave
+	FormationDrop float64
+
+	// Challenge stage objective for the current wave
+	Challenge       *Challenge
+	ChallengeStatus ChallengeStatus
+	ChallengeTime   float64 // seconds spent on the current challenge
+	ChallengeBonus  int     // bonus awarded for the last passed objective
+
+	// Game timing
+	Wave           int
+	WaveCleared    bool
+	WaveClearTimer float64 // seconds left in the wave-clear interstitial
+	WaveIntro      float64 // seconds left before a new wave attacks
+	DeltaTime      float64
+
+	// Developer cheats: whether any were used this game, and the HUD status
+	Cheated     bool
+	CheatStatus string
+
+	// Shot counter driving the UFO mystery score, over the whole game
+	ShotsFired int
+
+	// Results for the end-of-game summary
+	Stats GameStats
+
+	// Arcade continues left and seconds left on the continue offer
+	Credits       int
+	ContinueTimer float64
+
+	// Adaptive difficulty tracking
+	Adaptive AdaptiveDifficulty
+
+	// Per-wave stats
+	WaveShotsFired int
+	WaveShotsHit   int
+	AccuracyBonus  int // bonus awarded for the last cleared wave
+
+	// Game world dimensions
+	ScreenWidth  int
+	ScreenHeight int
+
+	// Game timing constants (in seconds)
+	FixedDeltaTime float64 // 1/20 = 0.05 for 20Hz updates
+
+	// Game options
+	Options GameOptions
+
+	// Input state
+	InputState   *InputState
+}
+
+// GameOptions holds optional rule changes; the zero value is classic mode
+type GameOptions struct {
+	WeaponHeat  bool            // use the heat model instead of the fire rate cooldown
+	Seed        int64           // fixed wave seed; 0 picks a new seed each game
+	Difficulty  DifficultyLevel // active difficulty preset
+	Adaptive    bool            // scale invader aggression to player performance
+	Daily       bool            // play today's fixed-seed daily challenge
+	Practice    bool            // lives are never lost and the score is non-competitive
+	Mirror      bool            // flip the playfield so invaders rise from the bottom
+	TwoPlayer   bool            // two players alternate turns when a life is lost
+	Coop        bool            // two ships play at once on one keyboard
+	Ghost       bool            // race a replay of the best previous run
+	Debris      bool            // drifting obstacles block shots in the mid-field
+	ScreenWrap  bool            // ships wrap between the screen edges instead of stopping
+	Progressive bool            // score kills with ProgressiveScoringPolicy, so late kills are worth more
+	Lives       int             // lives each player starts with; 0 uses the difficulty preset
+	ShipSpeed   float64         // ship speed multiplier; 0 means normal speed
+	FireRate    float64         // player fire rate multiplier; 0 means the normal rate
+	Ruleset     string          // name of the registered ruleset; empty plays classic
+}
+
+// StartingLives returns the lives each player starts a game with
+func (o GameOptions) StartingLives() int {
+	if o.Lives > 0 {
+		return o.Lives
+	}
+	return o.Difficulty.Preset().StartingLives
+}
+
+// ShipSpeedScale returns the ship speed multiplier
+func (o GameOptions) ShipSpeedScale() float64 {
+	if o.ShipSpeed > 0 {
+		return o.ShipSpeed
+	}
+	return 1
+}
+
+// FireRateScale returns the player fire rate multiplier
+func (o GameOptions) FireRateScale() float64 {
+	if o.FireRate > 0 {
+		return o.FireRate
+	}
+	return 1
+}
+
+// InputState tracks the current input state
+type InputState struct {
+	LeftPressed  bool
+	RightPressed bool
+	FirePressed  bool
+	FireJustPressed bool
+	PauseJustPressed bool
+}
+
+// NewGameState creates a new game state with default values
+func NewGameState(screenWidth, screenHeight int) *GameState {
+	return &GameState{
+		Mode:           AttractMode,
+		HighScore:      0,
+		HighScoreRank:  -1,
+		Wave:           1,
+		ScreenWidth:    screenWidth,
+		ScreenHeight:   screenHeight,
+		FixedDeltaTime: 1.0 / 20.0, // 20Hz update rate
+		WaveConfig:     DefaultWaveConfig(),
+		Options:        GameOptions{Difficulty: DifficultyNormal},
+		Settings:       DefaultSettings(),
+		BarrierConfig:  DefaultBarrierConfig(),
+		InputState:     &InputState{},
+	}
+}
+
+// InitializeNewGame sets up a fresh game state for starting a new game
+func (gs *GameState) InitializeNewGame() {
+	gs.Paused = false
+	gs.GameStarted = true
+	gs.GameEnded = false
+	gs.Wave = 1
+	gs.WaveCleared = false
+	gs.ResetCombo()
+	gs.resetWaveStats()
+	gs.Stats = GameStats{}
+	gs.Cheated = false
+	gs.Credits = continueCredits
+	gs.ContinueTimer = 0
+	gs.NewHighScore = false
+	gs.HighScoreRank = -1
+
+	gs.Adaptive = NewAdaptiveDifficulty(gs.Options.Adaptive)
+	gs.Entities = EntityManager{}
+
+	// Pick the seed for procedural waves
+	gs.Seed = gs.Options.Seed
+	if gs.Seed == 0 {
+		gs.Seed = newSeed()
+	}
+	if gs.Options.Daily {
+		gs.startDailyRun(time.Now())
+	}
+
+	// Initialize players
+	gs.initializePlayers()
+	gs.BombFlash = 0
+
+	// Initialize invaders
+	gs.initializeWave()
+
+	// Clear bullets and UFO
+	gs.Bullets = []*Bullet{}
+	gs.UFO = nil
+	gs.ScorePopups = []*ScorePopup{}
+	gs.Pickups = []*Pickup{}
+	gs.Beams = []*Beam{}
+	gs.DyingInvaders = []*Invader{}
+	gs.Explosions = []*Explosion{}
+	gs.Particles = nil
+	gs.ShotsFired = 0
+
+	// Initialize barriers
+	gs.initializeBarriers()
+
+	// Give each hotseat player their own copy of the opening wave
+	gs.initializeSlots()
+
+	// Record this run and race the best one
+	gs.startGhost()
+
+	// Reset input state
+	gs.InputState = &InputState{}
+}
+
+// NewPlayer creates a ship for the given player at its starting position with
+// the game options applied
+func (gs *GameState) NewPlayer(index int) *PlayerShip {
+	player := NewPlayerShip(gs.SpawnX(index), gs.ScreenY(gs.shipLine()))
+	track(&gs.Entities, player)
+	player.HeatEnabled = gs.Options.WeaponHeat
+	player.ScreenWrap = gs.Options.ScreenWrap
+
+	speed := gs.Options.ShipSpeedScale()
+	player.MaxSpeed *= speed
+	player.Acceleration *= speed
+	player.Friction *= speed
+	player.FireRateScale = gs.Options.FireRateScale()
+	player.SetWeapon(player.Weapon)
+	return player
+}
+
+// shipLine returns the y coordinate ships fly along in the classic top-down layout
+func (gs *GameState) shipLine() float64 {
+	return float64(gs.ScreenHeight - 40)
+}
+
+// clearGame clears away the last game for the title screen
+func (gs *GameState) clearGame() {
+	gs.GameStarted = false
+	gs.GameEnded = false
+	gs.Players = nil
+	gs.Invaders = []*Invader{}
+	gs.Bullets = []*Bullet{}
+	gs.UFO = nil
+	gs.Boss = nil
+	gs.DyingInvaders = []*Invader{}
+	gs.Explosions = []*Explosion{}
+	gs.Particles = nil
+	gs.Entities.invalidate()
+	gs.InputState = &InputState{}
+}
+
+// updateHighScore records every player's score against the board for the current run
+func (gs *GameState) updateHighScore() {
+	if !gs.IsCompetitive() {
+		return
+	}
+	for _, player := range gs.Players {
+		if gs.Options.Daily {
+			if player.Score > gs.DailyHighScore {
+				gs.DailyHighScore = player.Score
+			}
+			continue
+		}
+		if player.Score > gs.HighScore {
+			gs.HighScore = player.Score
+			gs.NewHighScore = true
+		}
+	}
+}
+
+// NextWave advances to the next wave
+func (gs *GameState) NextWave() {
+	gs.Wave = gs.Rules().NextWave(gs)
+	if gs.Wave >= 10 {
+		gs.UnlockAchievement(AchievementWave10)
+	}
+	gs.WaveCleared = false
+	gs.resetWaveStats()
+	gs.initializeWave()
+	gs.regenerateBarriers()
+
+	// Reset player positions
+	for _, player := range gs.Players {
+		if player.Ship != nil {
+			player.Ship.Position.X = gs.SpawnX(player.Index)
+			player.Ship.Position.Y = gs.ScreenY(gs.shipLine())
+			player.Ship.Velocity.X = 0
+			player.Ship.Confine(float64(gs.ScreenWidth))
+		}
+	}
+
+	// Clear player bullets (but keep enemy bullets for challenge)
+	newBullets := []*Bullet{}
+	for _, bullet := range gs.Bullets {
+		if !bullet.IsPlayerBullet {
+			newBullets = append(newBullets, bullet)
+		}
+	}
+	gs.Bullets = newBullets
+	gs.Pickups = []*Pickup{}
+	gs.Beams = []*Beam{}
+}
+
+// removeDead drops destroyed entities from the lists of entities in play.
+// Destroyed invaders stay on screen a moment longer as splats.
+func (gs *GameState) removeDead() {
+	live := gs.Invaders[:0]
+	for _, invader := range gs.Invaders {
+		if invader.Alive {
+			live = append(live, invader)
+			continue
+		}
+		invader.Splat = invaderSplatDuration
+		gs.DyingInvaders = append(gs.DyingInvaders, invader)
+	}
+	clear(gs.Invaders[len(live):])
+	gs.Invaders = live
+	gs.Bullets = slices.DeleteFunc(gs.Bullets, func(bullet *Bullet) bool { return !bullet.Alive })
+	gs.Pickups = slices.DeleteFunc(gs.Pickups, func(pickup *Pickup) bool { return !pickup.Alive })
+	gs.Debris = slices.DeleteFunc(gs.Debris, func(debris *Debris) bool { return !debris.Alive })
+	if gs.UFO != nil && !gs.UFO.Alive {
+		gs.UFO = nil
+	}
+	if gs.Boss != nil && !gs.Boss.Alive {
+		gs.Boss = nil
+	}
+}
+
+// landingDepth is how far invaders may advance, in the classic top-down
+// layout, before they've landed: the line just above the player area
+func (gs *GameState) landingDepth() float64 {
+	return float64(gs.ScreenHeight - 100)
+}
+
+// LandingLine returns the screen y of the line invaders land on reaching
+func (gs *GameState) LandingLine() float64 {
+	return gs.ScreenY(gs.landingDepth())
+}
+
+// InvadersLanded reports whether any invader's formation slot has reached the
+// line above the player area
+func (gs *GameState) InvadersLanded() bool {
+	bottomLine := gs.landingDepth()
+	for _, invader := range gs.Invaders {
+		if gs.Depth(invader.Home.Y) >= bottomLine {
+			return true
+		}
+	}
+	return false
+}
+
+// IsBossWave reports whether the current wave is a boss wave
+func (gs *GameState) IsBossWave() bool {
+	return gs.Rules().BossWave(gs, gs.Wave)
+}
+
+// initializeWave spawns either a boss or the normal invader formation
+func (gs *GameState) initializeWave() {
+	gs.Boss = nil
+	gs.WaveSpec = gs.WaveConfig.SpecForWave(gs.Seed, gs.Wave)
+	gs.FormationDrop = 0
+	gs.startChallenge()
+	gs.initializeDebris()
+	if gs.IsBossWave() {
+		gs.Invaders = []*Invader{}
+		gs.Boss = NewBoss(float64(gs.ScreenWidth/2), gs.ScreenY(100), gs.Wave)
+		gs.Boss.Face(gs.Orientation().Advance())
+		track(&gs.Entities, gs.Boss)
+		return
+	}
+	gs.initializeInvaders()
+}
+
+// initializeInvaders creates the invader formation for the current wave
+func (gs *GameState) initializeInvaders() {
+	gs.Invaders = []*Invader{}
+	spec := gs.WaveSpec
+
+	for row, types := range spec.Layout {
+		for col, invaderType := range types {
+			if invaderType == InvaderTypeNone {
+				continue
+			}
+
+			x := spec.StartX + float64(col)*spec.SpacingX
+			y := gs.ScreenY(spec.StartY + float64(row)*spec.SpacingY)
+
+			invader := NewInvader(invaderType, x, y, InvaderPoints(invaderType))
+			invader.ShootChance *= spec.ShootScale * gs.Options.Difficulty.Preset().ShootChance * gs.Adaptive.Scale()
+			gs.Invaders = append(gs.Invaders, invader)
+		}
+	}
+	track(&gs.Entities, gs.Invaders...)
+}
+
+// BarrierBlockBounds returns the world bounds of the barrier block at (x, y)
+func (gs *GameState) BarrierBlockBounds(x, y int) Bounds {
+	return Bounds{
+		X:      gs.BarrierOrigin.X + float64(x)*gs.BarrierBlockSize,
+		Y:      gs.BarrierOrigin.Y + float64(y)*gs.BarrierBlockSize,
+		Width:  gs.BarrierBlockSize,
+		Height: gs.BarrierBlockSize,
+	}
+}
+
+// IsWaveCleared checks if all invaders have been destroyed
+func (gs *GameState) IsWaveCleared() bool {
+	return len(gs.Invaders) == 0 && gs.Boss == nil
+}
+
+// GetLiveInvaderCount returns the number of remaining invaders
+func (gs *GameState) GetLiveInvaderCount() int {
+	count := 0
+	for _, invader := range gs.Invaders {
+		if invader.Alive {
+			count++
+		}
+	}
+	return count
+}
+
+// AddScore adds points to the given player's score
+func (gs *GameState) AddScore(player *Player, points int) {
+	player.Score += points
+	gs.updateHighScore()
+}
+
+// resetWaveStats clears the per-wave shot statistics
+func (gs *GameState) resetWaveStats() {
+	gs.WaveShotsFired = 0
+	gs.WaveShotsHit = 0
+	gs.WaveClearTimer = 0
+	gs.WaveIntro = 0
+	gs.AccuracyBonus = 0
+	gs.ChallengeBonus = 0
+}
+
+// WaveAccuracy returns the fraction of shots this wave that hit an enemy
+func (gs *GameState) WaveAccuracy() float64 {
+	if gs.WaveShotsFired == 0 {
+		return 0
+	}
+	return float64(gs.WaveShotsHit) / float64(gs.WaveShotsFired)
+}
+
+// CalculateAccuracyBonus returns the end-of-wave bonus for the current accuracy
+func (gs *GameState) CalculateAccuracyBonus() int {
+	// 10 points per accuracy percent, so a perfect wave is worth 1000
+	return int(gs.WaveAccuracy()*100) * 10
+}
+
+// Combo tuning
+const (
+	comboKillsPerStep  = 5 // kills needed per multiplier step
+	maxComboMultiplier = 4
+)
+
+// ComboTimeout is how long a combo lasts between kills, in seconds
+const ComboTimeout = 3.0
+
+// ComboMultiplier returns the score multiplier earned by the current combo
+func (gs *GameState) ComboMultiplier() int {
+	multiplier := 1 + gs.Combo/comboKillsPerStep
+	if multiplier > maxComboMultiplier {
+		multiplier = maxComboMultiplier
+	}
+	return multiplier
+}
+
+// RegisterKill extends the combo and awards the player points scaled by the multiplier
+func (gs *GameState) RegisterKill(player *Player, points int) {
+	gs.Combo++
+	gs.ComboTimer = ComboTimeout
+	if gs.Combo > gs.Stats.BestCombo {
+		gs.Stats.BestCombo = gs.Combo
+	}
+	gs.AddScore(player, points*gs.ComboMultiplier())
+
+	gs.AdvanceAchievement(AchievementInvaderHunter, 1)
+	if gs.ComboMultiplier() == maxComboMultiplier {
+		gs.UnlockAchievement(AchievementMaxCombo)
+	}
+}
+
+// ResetCombo clears the combo after a missed shot
+func (gs *GameState) ResetCombo() {
+	gs.Combo = 0
+	gs.ComboTimer = 0
+}
+
+// UpdateCombo decays the combo once the timeout expires
+func (gs *GameState) UpdateCombo(deltaTime float64) {
+	if gs.Combo == 0 {
+		return
+	}
+	gs.ComboTimer -= deltaTime
+	if gs.ComboTimer <= 0 {
+		gs.ResetCombo()
+	}
+}
+
+// IsCompetitive reports whether the current score counts toward high scores
+func (gs *GameState) IsCompetitive() bool {
+	return !gs.Options.Practice && !gs.Demo && !gs.Cheated
+}
+
+// LoseLife removes a life from the player, reporting whether one was lost;
+// practice games never run out
+func (gs *GameState) LoseLife(player *Player) bool {
+	if gs.Options.Practice {
+		return false
+	}
+	player.Lives--
+	return true
+}
+
+// TogglePause toggles the pause state of the game, opening the pause menu at
+// its first option
+func (gs *GameState) TogglePause() {
+	if gs.Mode == Playing {
+		gs.Paused = !gs.Paused
+		gs.PauseSelection = PauseResume
+	}
+}
@@ -0,0 +1,19 @@
+package game
+
+// GameStats tracks a game's results for the end-of-game summary
+type GameStats struct {
+	WavesCleared int
+	ShotsFired   int // player bullets fired
+	ShotsHit     int // player bullets that hit an enemy
+	BestCombo    int
+	UFOsHit      int
+	PlayTime     float64 // seconds of play, excluding pauses
+}
+
+// Accuracy returns the fraction of shots that hit an enemy over the whole game
+func (s GameStats) Accuracy() float64 {
+	if s.ShotsFired == 0 {
+		return 0
+	}
+	return float64(s.ShotsHit) / float64(s.ShotsFired)
+}
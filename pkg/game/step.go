@@ -0,0 +1,71 @@
+package game
+
+// InputFrame is the players' input for a single fixed tick
+type InputFrame struct {
+	// Player one
+	Left         bool    `json:"left,omitempty"`         // steering left
+	Right        bool    `json:"right,omitempty"`        // steering right
+	Axis         float64 `json:"axis,omitempty"`         // analog steering, added to Left and Right
+	Aim          bool    `json:"aim,omitempty"`          // Axis is where the ship should be
+	Fire         bool    `json:"fire,omitempty"`         // fire held
+	FirePressed  bool    `json:"firePressed,omitempty"`  // fire pressed since the last tick
+	PausePressed bool    `json:"pausePressed,omitempty"` // pause pressed since the last tick
+	Confirm      bool    `json:"confirm,omitempty"`      // start or confirm pressed since the last tick
+	Up           bool    `json:"up,omitempty"`           // up pressed since the last tick, for menus
+	Down         bool    `json:"down,omitempty"`         // down pressed since the last tick, for menus
+	CycleWeapon  bool    `json:"cycleWeapon,omitempty"`  // weapon switch pressed since the last tick
+	SmartBomb    bool    `json:"smartBomb,omitempty"`    // smart bomb pressed since the last tick
+
+	// The second co-op player
+	P2Left        bool `json:"p2Left,omitempty"`
+	P2Right       bool `json:"p2Right,omitempty"`
+	P2FirePressed bool `json:"p2FirePressed,omitempty"`
+}
+
+// Commands returns player one's commands for the frame
+func (f InputFrame) Commands() Commands {
+	axis := f.Axis
+	if !f.Aim {
+		axis = max(-1, min(axis+Axis(f.Left, f.Right), 1))
+	}
+	return Commands{
+		MoveAxis:    axis,
+		Aim:         f.Aim,
+		Fire:        f.Fire,
+		FirePressed: f.FirePressed,
+		Pause:       f.PausePressed,
+		Confirm:     f.Confirm,
+		Up:          f.Up,
+		Down:        f.Down,
+		CycleWeapon: f.CycleWeapon,
+		SmartBomb:   f.SmartBomb,
+	}
+}
+
+// PartnerCommands returns the second co-op player's commands for the frame
+func (f InputFrame) PartnerCommands() Commands {
+	return Commands{MoveAxis: Axis(f.P2Left, f.P2Right), FirePressed: f.P2FirePressed}
+}
+
+// Step applies the input and advances the game by exactly one fixed tick,
+// without reference to real time. Headless callers such as tests, bots and
+// servers drive the engine with Step instead of Update.
+func (e *Engine) Step(input InputFrame) {
+	e.ProcessCommands(0, input.Commands())
+	e.ProcessCommands(1, input.PartnerCommands())
+
+	e.state.DeltaTime = e.state.FixedDeltaTime
+	if !e.state.Paused {
+		e.fixedUpdate(e.state.FixedDeltaTime)
+	}
+	e.emitChanges()
+	e.publishState()
+}
+
+// RunFor advances the game by the given number of ticks with no input, for
+// example to let the attract-mode demo or a running wave play out
+func (e *Engine) RunFor(ticks int) {
+	for range ticks {
+		e.Step(InputFrame{})
+	}
+}
@@ -0,0 +1,130 @@
+package game
+
+import "testing"
+
+// steadyStateEngine returns a practice game that has been running long enough
+// for its entity lists to reach their working size
+func steadyStateEngine() *Engine {
+	e := NewEngine()
+	e.GetState().Options = GameOptions{Seed: 1, Practice: true}
+	e.StartNewGame()
+	for tick := range 200 {
+		e.Step(InputFrame{Left: tick%80 < 40, Right: tick%80 >= 40})
+	}
+	return e
+}
+
+func BenchmarkStep(b *testing.B) {
+	e := steadyStateEngine()
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		e.Step(InputFrame{Left: i%80 < 40, Right: i%80 >= 40})
+	}
+}
+
+func BenchmarkUpdateInvaders(b *testing.B) {
+	e := steadyStateEngine()
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		e.updateInvaders(e.state.FixedDeltaTime)
+	}
+}
+
+func BenchmarkUpdateBullets(b *testing.B) {
+	e := steadyStateEngine()
+	bullets := make([]*Bullet, 100)
+	for i := range bullets {
+		bullets[i] = NewBullet(float64(8*i), 300, 0, 0, false)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		e.state.Bullets = append(e.state.Bullets[:0], bullets...)
+		e.updateBullets(e.state.FixedDeltaTime)
+	}
+}
+
+func TestWaveIntroHoldsFireButShotsStillHit(t *testing.T) {
+	e := NewEngine()
+	e.GetState().Options = GameOptions{Seed: 1, Practice: true}
+	e.StartNewGame()
+	gs := e.GetState()
+	if gs.WaveIntro == 0 {
+		t.Fatal("new game has no wave intro")
+	}
+
+	e.Step(InputFrame{Fire: true})
+	if gs.ShotsFired != 0 || len(gs.Bullets) != 0 {
+		t.Fatalf("ship fired %d shots during the wave intro", gs.ShotsFired)
+	}
+
+	// A shot already in the air still hits the assembling wave
+	target := gs.Invaders[0]
+	bullet := NewBullet(target.Position.X, target.Position.Y+15, 0, -400, true)
+	gs.Bullets = append(gs.Bullets, bullet)
+	track(&gs.Entities, bullet)
+	e.Step(InputFrame{})
+	if gs.WaveIntro == 0 {
+		t.Fatal("wave intro ended before the shot landed")
+	}
+	if target.Alive {
+		t.Error("shot passed through an invader during the wave intro")
+	}
+}
+
+func TestStepDrivesMenusAndSpecials(t *testing.T) {
+	e := NewEngine()
+	e.GetState().Options = GameOptions{Seed: 1, Practice: true}
+	e.StartNewGame()
+	gs := e.GetState()
+	player := gs.PrimaryPlayer()
+
+	weapon := player.Ship.Weapon
+	e.Step(InputFrame{CycleWeapon: true})
+	if player.Ship.Weapon == weapon {
+		t.Error("weapon switch left the ship on the same weapon")
+	}
+
+	bombs := player.SmartBombs
+	e.Step(InputFrame{SmartBomb: true})
+	if player.SmartBombs != bombs-1 {
+		t.Errorf("%d smart bombs left after using one of %d", player.SmartBombs, bombs)
+	}
+
+	e.Step(InputFrame{PausePressed: true})
+	e.Step(InputFrame{Down: true})
+	if !gs.Paused || gs.PauseSelection != PauseRestart {
+		t.Fatalf("pause menu on %v, want %v", gs.PauseSelection, PauseRestart)
+	}
+	e.Step(InputFrame{Down: true})
+	e.Step(InputFrame{Down: true})
+	e.Step(InputFrame{Confirm: true})
+	if gs.Mode != SettingsMenu {
+		t.Fatalf("mode %v after choosing settings, want the settings screen", gs.Mode)
+	}
+	e.Step(InputFrame{PausePressed: true})
+	e.Step(InputFrame{PausePressed: true})
+	if gs.Mode != Playing || gs.Paused {
+		t.Errorf("mode %v paused %v after backing out of the menus, want playing", gs.Mode, gs.Paused)
+	}
+}
+
+func TestHotseatLandingWithoutPlayers(t *testing.T) {
+	e := NewEngine()
+	e.GetState().Options = GameOptions{Seed: 1, TwoPlayer: true}
+	e.StartNewGame()
+	gs := e.GetState()
+	if !gs.IsMultiplayer() {
+		t.Fatal("two-player game has no hotseat slots")
+	}
+
+	// Landing with no player in play must not take anyone's turn
+	gs.Players = nil
+	gs.Invaders[0].Home.Y = gs.landingDepth()
+	e.checkInvaderReachBottom()
+}
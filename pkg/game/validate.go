@@ -0,0 +1,116 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// boundsTolerance is how far an entity's bounds may sit from being centered
+// on its position, allowing for rounding
+const boundsTolerance = 0.01
+
+// Validate checks the invariants the simulation relies on between ticks and
+// returns every violation found, or nil. It is meant for tests, fuzzing and
+// debug builds; a violation means a bug in the engine, not bad input.
+func (e *Engine) Validate() error {
+	v := &validator{}
+	gs := e.state
+
+	if gs.Mode < AttractMode || gs.Mode > Continue {
+		v.fail("unknown mode %d", gs.Mode)
+	}
+	if gs.Wave < 1 {
+		v.fail("wave %d is before the first", gs.Wave)
+	}
+	if gs.Mode == Playing && len(gs.Players) == 0 {
+		v.fail("playing with no players")
+	}
+	for _, player := range gs.Players {
+		if player.Lives < 0 {
+			v.fail("player %d has %d lives", player.Index, player.Lives)
+		}
+		if player.Score < 0 {
+			v.fail("player %d has a score of %d", player.Index, player.Score)
+		}
+		if player.Status == PlayerActive && !player.IsActive() {
+			v.fail("player %d is active without a live ship", player.Index)
+		}
+		if player.IsActive() {
+			v.centered("ship", player.Ship.ID, player.Ship.Position, player.Ship.Bounds)
+		}
+	}
+
+	for _, invader := range gs.Invaders {
+		v.alive("invader", invader.ID, invader.Alive)
+		v.centered("invader", invader.ID, invader.Position, invader.Bounds)
+	}
+	for _, bullet := range gs.Bullets {
+		v.alive("bullet", bullet.ID, bullet.Alive)
+		v.centered("bullet", bullet.ID, bullet.Position, bullet.Bounds)
+	}
+	for _, pickup := range gs.Pickups {
+		v.alive("pickup", pickup.ID, pickup.Alive)
+		v.centered("pickup", pickup.ID, pickup.Position, pickup.Bounds)
+	}
+	for _, debris := range gs.Debris {
+		v.alive("debris", debris.ID, debris.Alive)
+		v.centered("debris", debris.ID, debris.Position, debris.Bounds)
+	}
+	if ufo := gs.UFO; ufo != nil {
+		v.alive("UFO", ufo.ID, ufo.Alive)
+		v.centered("UFO", ufo.ID, ufo.Position, ufo.Bounds)
+	}
+	if boss := gs.Boss; boss != nil {
+		v.alive("boss", boss.ID, boss.Alive)
+		v.centered("boss", boss.ID, boss.Position, boss.Bounds)
+		if boss.Health < 0 || boss.Health > boss.MaxHealth {
+			v.fail("boss has %d of %d health", boss.Health, boss.MaxHealth)
+		}
+	}
+
+	// Every entity in play has its own ID
+	seen := map[EntityID]bool{}
+	gs.EachEntity(func(entity Entity) {
+		id := entity.EntityID()
+		if id == 0 || id > gs.Entities.NextID {
+			v.fail("%T has ID %d, which was never handed out", entity, id)
+		} else if seen[id] {
+			v.fail("ID %d is used by more than one entity", id)
+		}
+		seen[id] = true
+	})
+
+	return errors.Join(v.errs...)
+}
+
+// validator collects invariant violations
+type validator struct {
+	errs []error
+}
+
+// fail records a violation
+func (v *validator) fail(format string, args ...any) {
+	v.errs = append(v.errs, fmt.Errorf(format, args...))
+}
+
+// alive checks that an entity still in play hasn't been destroyed
+func (v *validator) alive(kind string, id EntityID, alive bool) {
+	if !alive {
+		v.fail("dead %s %d is still in play", kind, id)
+	}
+}
+
+// centered checks that an entity's position is finite and its bounds are
+// centered on it
+func (v *validator) centered(kind string, id EntityID, position Vector2, bounds Bounds) {
+	if math.IsNaN(position.X) || math.IsNaN(position.Y) || math.IsInf(position.X, 0) || math.IsInf(position.Y, 0) {
+		v.fail("%s %d is at (%g, %g)", kind, id, position.X, position.Y)
+		return
+	}
+	centerX := bounds.X + bounds.Width/2
+	centerY := bounds.Y + bounds.Height/2
+	if math.Abs(centerX-position.X) > boundsTolerance || math.Abs(centerY-position.Y) > boundsTolerance {
+		v.fail("%s %d at (%g, %g) has bounds centered on (%g, %g)", kind, id, position.X, position.Y, centerX, centerY)
+	}
+}
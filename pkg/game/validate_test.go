@@ -0,0 +1,60 @@
+package game
+
+import "testing"
+
+// fuzzOptions picks game options from the bits of a fuzzed byte
+func fuzzOptions(seed int64, bits uint8) GameOptions {
+	return GameOptions{
+		Seed:       seed,
+		Difficulty: DifficultyLevel(bits % 3),
+		WeaponHeat: bits&0x04 != 0,
+		Mirror:     bits&0x08 != 0,
+		Coop:       bits&0x10 != 0,
+		TwoPlayer:  bits&0x20 != 0,
+		Debris:     bits&0x40 != 0,
+		ScreenWrap: bits&0x80 != 0,
+	}
+}
+
+// fuzzInput turns a fuzzed byte into one tick's input
+func fuzzInput(b byte) InputFrame {
+	return InputFrame{
+		Left:          b&0x01 != 0,
+		Right:         b&0x02 != 0,
+		Fire:          b&0x04 != 0,
+		FirePressed:   b&0x08 != 0,
+		PausePressed:  b&0x80 == 0x80 && b&0x70 == 0x70, // rarely, so games get played
+		P2Left:        b&0x10 != 0,
+		P2Right:       b&0x20 != 0,
+		P2FirePressed: b&0x40 != 0,
+	}
+}
+
+// FuzzEngine plays random input through the engine and checks its
+// invariants after every tick. Each input byte is held for a few ticks so
+// fuzzed games last long enough to clear waves and lose lives.
+func FuzzEngine(f *testing.F) {
+	f.Add(int64(1), uint8(0), []byte{0x0c, 0x01, 0x0d, 0x02, 0x0e, 0x00})
+	f.Add(int64(42), uint8(0x18), []byte{0x4d, 0x2e, 0x1c, 0x6f, 0x0c})
+	f.Add(int64(7), uint8(0xe5), []byte{0x09, 0x0a, 0xff, 0x08, 0x05, 0x06})
+
+	const ticksPerByte = 8
+	f.Fuzz(func(t *testing.T, seed int64, options uint8, inputs []byte) {
+		e := NewEngine()
+		e.GetState().Options = fuzzOptions(seed, options)
+		e.StartNewGame()
+
+		for i, b := range inputs {
+			for tick := range ticksPerByte {
+				input := fuzzInput(b)
+				if tick > 0 {
+					input.FirePressed, input.PausePressed, input.P2FirePressed = false, false, false
+				}
+				e.Step(input)
+				if err := e.Validate(); err != nil {
+					t.Fatalf("after input %d, tick %d: %v", i, tick, err)
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,167 @@
+package game
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+//go:embed waves/classic.json
+var classicWaveConfig []byte
+
+// WaveConfig is a data-driven set of wave definitions
+type WaveConfig struct {
+	// ProceduralFrom is the first wave generated from the seed instead of
+	// the definitions; 0 disables procedural waves
+	ProceduralFrom int              `json:"procedural_from"`
+	Waves          []WaveDefinition `json:"waves"`
+	Challenges     []Challenge      `json:"challenges"`
+}
+
+// WaveDefinition describes a wave as data. Each layout row is a string with
+// one character per slot: S small, M medium, L large, K kamikaze, '.' empty.
+// The optional formation carves a shape out of the layout.
+type WaveDefinition struct {
+	FromWave       int       `json:"from_wave"`
+	Layout         []string  `json:"layout"`
+	Formation      Formation `json:"formation"`
+	SpacingX       float64   `json:"spacing_x"`
+	SpacingY       float64   `json:"spacing_y"`
+	StartX         float64   `json:"start_x"`
+	StartY         float64   `json:"start_y"`
+	Speed          float64   `json:"speed"`
+	Shoot          float64   `json:"shoot"`
+	UFOIntervalMin float64   `json:"ufo_interval_min"`
+	UFOIntervalMax float64   `json:"ufo_interval_max"`
+}
+
+// layoutSymbols maps layout characters to invader types
+var layoutSymbols = map[rune]InvaderType{
+	'S': InvaderTypeSmall,
+	'M': InvaderTypeMedium,
+	'L': InvaderTypeLarge,
+	'K': InvaderTypeKamikaze,
+	'.': InvaderTypeNone,
+}
+
+// DefaultWaveConfig returns the built-in classic wave configuration
+func DefaultWaveConfig() *WaveConfig {
+	config, err := ParseWaveConfig(classicWaveConfig)
+	if err != nil {
+		panic(fmt.Sprintf("invalid built-in wave config: %v", err))
+	}
+	return config
+}
+
+// ParseWaveConfig parses and validates a JSON wave configuration
+func ParseWaveConfig(data []byte) (*WaveConfig, error) {
+	config := &WaveConfig{}
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("failed to parse wave config: %w", err)
+	}
+
+	if len(config.Waves) == 0 {
+		return nil, fmt.Errorf("wave config defines no waves")
+	}
+
+	for i, def := range config.Waves {
+		if err := def.validate(); err != nil {
+			return nil, fmt.Errorf("wave definition %d: %w", i, err)
+		}
+	}
+
+	for i, challenge := range config.Challenges {
+		if err := challenge.validate(); err != nil {
+			return nil, fmt.Errorf("challenge %d: %w", i, err)
+		}
+	}
+
+	// Keep definitions ordered so lookups can scan forward
+	sort.SliceStable(config.Waves, func(a, b int) bool {
+		return config.Waves[a].FromWave < config.Waves[b].FromWave
+	})
+
+	return config, nil
+}
+
+// validate checks a definition for unusable values
+func (d WaveDefinition) validate() error {
+	if d.FromWave < 1 {
+		return fmt.Errorf("from_wave must be at least 1")
+	}
+	if len(d.Layout) == 0 {
+		return fmt.Errorf("layout is empty")
+	}
+	for row, line := range d.Layout {
+		for _, symbol := range line {
+			if _, ok := layoutSymbols[symbol]; !ok {
+				return fmt.Errorf("layout row %d: unknown symbol %q", row, symbol)
+			}
+		}
+	}
+	if err := d.Formation.validate(); err != nil {
+		return err
+	}
+	if d.Speed <= 0 || d.Shoot < 0 {
+		return fmt.Errorf("speed must be positive and shoot non-negative")
+	}
+	if d.UFOIntervalMin <= 0 || d.UFOIntervalMax < d.UFOIntervalMin {
+		return fmt.Errorf("invalid UFO interval")
+	}
+	return nil
+}
+
+// Spec converts the definition into a wave spec
+func (d WaveDefinition) Spec() WaveSpec {
+	layout := make([][]InvaderType, len(d.Layout))
+	for row, line := range d.Layout {
+		for _, symbol := range line {
+			layout[row] = append(layout[row], layoutSymbols[symbol])
+		}
+	}
+
+	formation := d.Formation
+	if formation == "" {
+		formation = FormationGrid
+	}
+
+	return WaveSpec{
+		Layout:         formation.Apply(layout),
+		Formation:      formation,
+		SpacingX:       d.SpacingX,
+		SpacingY:       d.SpacingY,
+		StartX:         d.StartX,
+		StartY:         d.StartY,
+		SpeedScale:     d.Speed,
+		ShootScale:     d.Shoot,
+		UFOIntervalMin: d.UFOIntervalMin,
+		UFOIntervalMax: d.UFOIntervalMax,
+	}
+}
+
+// SpecForWave returns the wave spec for the given wave number
+func (c *WaveConfig) SpecForWave(seed int64, wave int) WaveSpec {
+	if c.ProceduralFrom > 0 && wave >= c.ProceduralFrom {
+		return GenerateWave(seed, wave)
+	}
+
+	// Use the last definition that has started by this wave
+	current := c.Waves[0]
+	for _, def := range c.Waves {
+		if wave >= def.FromWave {
+			current = def
+		}
+	}
+	return current.Spec()
+}
+
+// ChallengeForWave returns the challenge objective for the given wave, or nil
+func (c *WaveConfig) ChallengeForWave(wave int) *Challenge {
+	for i := range c.Challenges {
+		if c.Challenges[i].Wave == wave {
+			return &c.Challenges[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package game
+
+import (
+	"math/rand"
+)
+
+// InvaderTypeNone marks an empty slot in a wave layout
+const InvaderTypeNone InvaderType = -1
+
+// WaveSpec describes the formation and tuning of a single wave
+type WaveSpec struct {
+	Layout     [][]InvaderType // rows of invader types, top row first
+	Formation  Formation       // shape already carved out of the layout
+	SpacingX   float64
+	SpacingY   float64
+	StartX     float64
+	StartY     float64
+	SpeedScale float64 // multiplier applied to formation speed
+	ShootScale float64 // multiplier applied to invader shoot chance
+
+	// Seconds between UFO appearances
+	UFOIntervalMin float64
+	UFOIntervalMax float64
+}
+
+// InvaderPoints returns the base score for an invader type
+func InvaderPoints(invaderType InvaderType) int {
+	switch invaderType {
+	case InvaderTypeSmall:
+		return 30
+	case InvaderTypeMedium:
+		return 20
+	case InvaderTypeKamikaze:
+		return 50
+	default:
+		return 10
+	}
+}
+
+// proceduralCurveStart is the wave the procedural difficulty curve climbs
+// from: the first generated wave of the classic config
+const proceduralCurveStart = 11
+
+// GenerateWave procedurally generates the wave spec for the given wave number.
+// The same seed and wave always produce the same formation.
+func GenerateWave(seed int64, wave int) WaveSpec {
+	rng := rand.New(rand.NewSource(seed + int64(wave)))
+	return proceduralWave(rng, wave)
+}
+
+// proceduralWave builds a randomized, horizontally symmetric formation
+func proceduralWave(rng *rand.Rand, wave int) WaveSpec {
+	rows := 4 + rng.Intn(3) // 4-6 rows
+	cols := 8 + rng.Intn(5) // 8-12 columns
+	depth := float64(max(wave-proceduralCurveStart, 0))
+
+	// Pick row types from the toughest at the top to the weakest at the bottom
+	rowTypes := make([]InvaderType, rows)
+	for row := range rowTypes {
+		switch {
+		case row == 0 || rng.Float64() < 0.2:
+			rowTypes[row] = InvaderTypeSmall
+		case row < rows/2+rng.Intn(2):
+			rowTypes[row] = InvaderTypeMedium
+		default:
+			rowTypes[row] = InvaderTypeLarge
+		}
+	}
+
+	layout := make([][]InvaderType, rows)
+	for row := range layout {
+		layout[row] = make([]InvaderType, cols)
+		for col := 0; col < (cols+1)/2; col++ {
+			invaderType := rowTypes[row]
+			roll := rng.Float64()
+			if roll < 0.12 {
+				invaderType = InvaderTypeNone
+			} else if roll < 0.16 {
+				invaderType = InvaderTypeKamikaze
+			}
+
+			// Mirror the left half onto the right half
+			layout[row][col] = invaderType
+			layout[row][cols-1-col] = invaderType
+		}
+	}
+
+	spec := WaveSpec{
+		Layout:     layout,
+		SpacingX:   36 + float64(rng.Intn(3))*4,
+		SpacingY:   28 + float64(rng.Intn(2))*4,
+		StartX:     100,
+		StartY:     80,
+		SpeedScale: 1.0 + depth*0.05 + rng.Float64()*0.2,
+		ShootScale: 1.0 + depth*0.1 + rng.Float64()*0.3,
+
+		UFOIntervalMin: 15,
+		UFOIntervalMax: 35,
+	}
+
+	// Give each wave its own shape
+	spec.Formation = formations[rng.Intn(len(formations))]
+	spec.Layout = spec.Formation.Apply(layout)
+	return spec
+}